@@ -12,6 +12,10 @@ const headerAMD64 = header1 + `
 .intel_syntax noprefix
 ` + header2
 
+const headerAMD64ATT = header1 + `
+.att_syntax prefix
+` + header2
+
 type RegAMD64 uint8
 
 const (
@@ -91,33 +95,6 @@ func (r RegAMD64) reg4() string {
 	panic(r)
 }
 
-func (r RegAMD64) reg1() string {
-	switch r {
-	case RAX:
-		return "al"
-	case RCX:
-		return "cl"
-	case RDX:
-		return "dl"
-	case RBX:
-		return "bl"
-	case RSP:
-		panic(r)
-	case RBP:
-		return "bpl"
-	case RSI:
-		return "sil"
-	case RDI:
-		return "dil"
-	}
-
-	if r < 16 {
-		return fmt.Sprintf("r%ddb", r)
-	}
-
-	panic(r)
-}
-
 var AMD64 = &ArchAMD64{
 	ClearableRegs: []RegAMD64{
 		RAX,
@@ -143,6 +120,10 @@ type ArchAMD64 struct {
 	ClearableRegs []RegAMD64
 }
 
+func (*ArchAMD64) ID() ArchID {
+	return IDAMD64
+}
+
 func (*ArchAMD64) Machine() string {
 	return "x86_64"
 }
@@ -155,20 +136,27 @@ func (arch *ArchAMD64) ClearReg(a *Assembly, r RegAMD64) {
 	if a.Arch != arch {
 		panic(a.Arch)
 	}
-	a.insn("xor", r.reg4(), r.reg4())
+	switch x := a.ArchAssembly.(type) {
+	case *amd64:
+		x.op("xor", 'l', x.regName(r.reg4()), x.regName(r.reg4()))
+	case *goAMD64:
+		x.insn("XORL", r.goReg(), r.goReg())
+	}
 }
 
 func (arch *ArchAMD64) OrMem4BytesImm(a *Assembly, base RegAMD64, offset, value int) {
 	if a.Arch != arch {
 		panic(a.Arch)
 	}
-	switch {
-	case offset == 0:
-		a.insnf("or dword ptr [%s], %d", base.reg(), value)
-	case offset > 0:
-		a.insnf("or dword ptr [%s + %d], %d", base.reg(), offset, value)
-	default:
-		a.insnf("or dword ptr [%s - %d], %d", base.reg(), -offset, value)
+	switch x := a.ArchAssembly.(type) {
+	case *amd64:
+		mem := x.memReg(base, offset)
+		if !x.att {
+			mem = "dword ptr " + mem
+		}
+		x.op("or", 'l', mem, x.imm(value))
+	case *goAMD64:
+		x.insn("ORL", x.imm(value), x.memReg(base, offset))
 	}
 }
 
@@ -176,27 +164,46 @@ func (arch *ArchAMD64) ExchangeMem4BytesReg(a *Assembly, base RegAMD64, offset i
 	if a.Arch != arch {
 		panic(a.Arch)
 	}
-	switch {
-	case offset == 0:
-		a.insnf("xchg [%s], %s", base.reg(), r.reg4())
-	case offset > 0:
-		a.insnf("xchg [%s + %d], %s", base.reg(), offset, r.reg4())
-	default:
-		a.insnf("xchg [%s - %d], %s", base.reg(), -offset, r.reg4())
+	switch x := a.ArchAssembly.(type) {
+	case *amd64:
+		x.op("xchg", 'l', x.memReg(base, offset), x.regName(r.reg4()))
+	case *goAMD64:
+		x.insn("XCHGL", r.goReg(), x.memReg(base, offset))
 	}
 }
 
-func (*ArchAMD64) newAssembly(sys *System, buf *buffer) ArchAssembly {
-	buf.WriteString(headerAMD64)
+func (*ArchAMD64) NewArchAssembly(sys *System, out *Output) ArchAssembly {
+	switch out.Syntax() {
+	case GoSyntax:
+		out.buf.WriteString(headerGo)
+		return &goAMD64{
+			System: sys,
+			buffer: out.buf,
+		}
+
+	case ATTSyntax:
+		out.buf.WriteString(headerAMD64ATT)
+		return &amd64{
+			System: sys,
+			buffer: out.buf,
+			att:    true,
+		}
+	}
+
+	out.buf.WriteString(headerAMD64)
 	return &amd64{
 		System: sys,
-		buffer: buf,
+		buffer: out.buf,
 	}
 }
 
+// amd64 emits GNU assembler syntax.  Operands are formatted by the helper
+// methods and passed to op in Intel order; AT&T mode reverses them and adds
+// the size suffix to the mnemonic.
 type amd64 struct {
 	*System
 	*buffer
+	att bool
 }
 
 func (a *amd64) check(r Reg) {
@@ -207,80 +214,92 @@ func (a *amd64) Set(r Reg) {
 	a.buffer.regUsage[r.AMD64] = r.Use
 }
 
-func (a *amd64) Label(name string) {
+func (a *amd64) Label(name string, opts ...SymbolOptions) {
 	if global(name) {
+		a.declare(name, "@function", opts)
 		a.printf("")
 		a.printf(".align 16,0x90") // nop
-		a.printf(".globl %s", symbol(name))
-		a.printf(".type  %s,@function", symbol(name))
+	} else {
+		symbolOptions(name, opts)
+		a.printf("")
 	}
-	a.printf("")
 	a.label(name)
+	if global(name) {
+		a.cfiStartProc(8)
+	}
 }
 
 func (a *amd64) FunctionEpilogue() {
+	if a.framePtr {
+		a.cfiRememberState()
+		a.op("pop", 'q', a.reg(a.FramePtr))
+		a.cfiRestore(a.reg(a.FramePtr), 0)
+		a.cfiAdjust(-8)
+		a.cfiDefStack(a.reg(a.StackPtr))
+	}
 }
 
-func (a *amd64) Function(name string) {
-	a.printf("")
-	a.printf(".align 16,0xcc") // int3
-	if global(name) {
-		a.printf(".globl %s", symbol(name))
-		a.printf(".type  %s,@function", symbol(name))
+func (a *amd64) Function(name string, opts ...SymbolOptions) {
+	a.FunctionWithoutPrologue(name, opts...)
+	if a.framePtr {
+		a.op("push", 'q', a.reg(a.FramePtr))
+		a.cfiAdjust(8)
+		a.cfiSave(a.reg(a.FramePtr), 0)
+		a.op("mov", 'q', a.reg(a.FramePtr), a.reg(a.StackPtr))
+		a.cfiDefFrame(a.reg(a.FramePtr), 0)
 	}
-	a.printf("")
-	a.label(name)
 }
 
-func (a *amd64) FunctionWithoutPrologue(name string) {
+func (a *amd64) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.declare(name, "@function", opts)
 	a.printf("")
 	a.printf(".align 16,0xcc") // int3
-	if global(name) {
-		a.printf(".globl %s", symbol(name))
-	}
-	a.printf("")
 	a.label(name)
+	a.cfiStartProc(8) // Return address.
 }
 
 func (a *amd64) Return() {
+	a.FunctionEpilogue()
 	a.ReturnWithoutEpilogue()
 }
 
 func (a *amd64) ReturnWithoutEpilogue() {
 	a.insn("ret")
 	a.speculationBarrier()
+	a.cfiRestoreState()
 }
 
 func (a *amd64) Address(dest Reg, name string) {
-	a.insnf("lea %s, [rip + %s]", a.reg(dest), symbol(name))
+	if a.external[name] {
+		a.op("mov", 'q', a.reg(dest), a.ripRelReloc(name, "GOTPCREL"))
+	} else {
+		a.op("lea", 'q', a.reg(dest), a.ripRel(name))
+	}
 	a.Set(dest)
 }
 
 func (a *amd64) MoveDef(dest Reg, name string) {
-	a.insnf("mov %s, %s", a.reg(dest), symbol(name))
+	a.checkAbsolute(name)
+	a.op("mov", 'q', a.reg(dest), a.immSymbol(name))
 	a.Set(dest)
 }
 
 func (a *amd64) MoveImm(dest Reg, value int) {
-	switch {
-	case value == 0:
-		a.insn("xor", a.reg4(dest), a.reg4(dest))
-	case value >= -0x80000000 && value <= 0x7fffffff:
-		a.insn("mov", a.reg4(dest), a.imm(value))
-	default:
-		a.insn("mov", a.reg(dest), a.imm(value))
-	}
-	a.Set(dest)
+	a.MoveImm64(dest, uint64(int64(value)))
 }
 
+// MoveImm64 uses 32-bit move (zero-extended) for unsigned 32-bit values, and
+// 64-bit move of sign-extended 32-bit immediate for small negative values.
 func (a *amd64) MoveImm64(dest Reg, value uint64) {
 	switch {
 	case value == 0:
-		a.insn("xor", a.reg4(dest), a.reg4(dest))
-	case int64(value) >= -0x80000000 && int64(value) <= 0x7fffffff:
-		a.insn("mov", a.reg4(dest), a.imm(int(value)))
+		a.op("xor", 'l', a.reg4(dest), a.reg4(dest))
+	case value <= 0xffffffff:
+		a.op("mov", 'l', a.reg4(dest), a.imm64(value))
+	case int64(value) >= -0x80000000:
+		a.op("mov", 'q', a.reg(dest), a.imm(int(int64(value))))
 	default:
-		a.insn("mov", a.reg(dest), a.imm64(value))
+		a.op("mov", 'q', a.reg(dest), a.imm64(value))
 	}
 	a.Set(dest)
 }
@@ -288,13 +307,13 @@ func (a *amd64) MoveImm64(dest Reg, value uint64) {
 func (a *amd64) MoveReg(dest, src Reg) {
 	a.check(src)
 	if a.reg(dest) != a.reg(src) {
-		a.insn("mov", a.reg(dest), a.reg(src))
+		a.op("mov", 'q', a.reg(dest), a.reg(src))
 	}
 	a.Set(dest)
 }
 
 func (a *amd64) MoveRegFloat(dest Reg, src FloatReg) {
-	a.insn("movq", a.reg(dest), a.floatreg(src))
+	a.op("movq", 0, a.reg(dest), a.floatreg(src))
 	a.Set(dest)
 }
 
@@ -304,12 +323,12 @@ func (a *amd64) AddImm(dest, src Reg, value int) {
 	case value == 0:
 		a.MoveReg(dest, src)
 	case a.reg(dest) == a.reg(src):
-		a.insn("add", a.reg(dest), a.imm(value))
+		a.op("add", 'q', a.reg(dest), a.imm(value))
 	case value > 0:
-		a.insnf("lea %s, [%s + %s]", a.reg(dest), a.reg(src), a.imm(value))
+		a.op("lea", 'q', a.reg(dest), a.mem(src, value))
 	default:
-		a.insn("mov", a.reg(dest), a.reg(src))
-		a.insn("add", a.reg(dest), a.imm(value))
+		a.op("mov", 'q', a.reg(dest), a.reg(src))
+		a.op("add", 'q', a.reg(dest), a.imm(value))
 	}
 	a.Set(dest)
 }
@@ -319,11 +338,11 @@ func (a *amd64) AddReg(dest, src1, src2 Reg) {
 	a.check(src2)
 	switch {
 	case a.reg(dest) == a.reg(src1):
-		a.insn("add", a.reg(dest), a.reg(src2))
+		a.op("add", 'q', a.reg(dest), a.reg(src2))
 	case a.reg(dest) == a.reg(src2):
-		a.insn("add", a.reg(dest), a.reg(src1))
+		a.op("add", 'q', a.reg(dest), a.reg(src1))
 	default:
-		a.insnf("lea %s, [%s + %s]", a.reg(dest), a.reg(src1), a.reg(src2))
+		a.op("lea", 'q', a.reg(dest), a.memIndex(src1, src2))
 	}
 	a.Set(dest)
 }
@@ -331,7 +350,7 @@ func (a *amd64) AddReg(dest, src1, src2 Reg) {
 func (a *amd64) SubtractImm(dest Reg, value int) {
 	a.check(dest)
 	if value != 0 {
-		a.insn("sub", a.reg(dest), a.imm(value))
+		a.op("sub", 'q', a.reg(dest), a.imm(value))
 	}
 	a.Set(dest)
 }
@@ -339,13 +358,13 @@ func (a *amd64) SubtractImm(dest Reg, value int) {
 func (a *amd64) SubtractReg(dest, src Reg) {
 	a.check(dest)
 	a.check(src)
-	a.insn("sub", a.reg(dest), a.reg(src))
+	a.op("sub", 'q', a.reg(dest), a.reg(src))
 	a.Set(dest)
 }
 
 func (a *amd64) MultiplyImm(dest, src Reg, value int, temp Reg) {
 	a.check(src)
-	a.insn("imul", a.reg(dest), a.reg(src), a.imm(value))
+	a.op("imul", 'q', a.reg(dest), a.reg(src), a.imm(value))
 	a.Set(dest)
 	a.Set(temp.As(""))
 }
@@ -354,11 +373,11 @@ func (a *amd64) AndImm(dest Reg, value int) {
 	a.check(dest)
 	switch {
 	case value == 0:
-		a.insn("xor", a.reg4(dest), a.reg4(dest))
+		a.op("xor", 'l', a.reg4(dest), a.reg4(dest))
 	case value > 0 && value <= 0x7fffffff:
-		a.insn("and", a.reg4(dest), a.imm(value))
+		a.op("and", 'l', a.reg4(dest), a.imm(value))
 	default:
-		a.insn("and", a.reg(dest), a.imm(value))
+		a.op("and", 'q', a.reg(dest), a.imm(value))
 	}
 	a.Set(dest)
 }
@@ -366,66 +385,49 @@ func (a *amd64) AndImm(dest Reg, value int) {
 func (a *amd64) AndReg(dest, src Reg) {
 	a.check(dest)
 	a.check(src)
-	a.insn("and", a.reg(dest), a.reg(src))
+	a.op("and", 'q', a.reg(dest), a.reg(src))
 	a.Set(dest)
 }
 
 func (a *amd64) OrImm(dest Reg, value int) {
 	a.check(dest)
-	a.insn("or", a.reg(dest), a.imm(value))
+	a.op("or", 'q', a.reg(dest), a.imm(value))
 	a.Set(dest)
 }
 
 func (a *amd64) OrReg(dest, src Reg) {
 	a.check(dest)
 	a.check(src)
-	a.insn("or", a.reg(dest), a.reg(src))
+	a.op("or", 'q', a.reg(dest), a.reg(src))
 	a.Set(dest)
 }
 
 func (a *amd64) ShiftImm(s Shift, r Reg, count int) {
 	a.check(r)
 	if count != 0 {
-		a.insn(a.shift(s), a.reg(r), a.imm(count))
+		a.op(a.shift(s), 'q', a.reg(r), a.imm(count))
 	}
 	a.Set(r)
 }
 
 func (a *amd64) Load(dest, base Reg, offset int) {
 	a.check(base)
-	switch {
-	case offset == 0:
-		a.insnf("mov %s, [%s]", a.reg(dest), a.reg(base))
-	case offset > 0:
-		a.insnf("mov %s, [%s + %d]", a.reg(dest), a.reg(base), offset)
-	default:
-		a.insnf("mov %s, [%s - %d]", a.reg(dest), a.reg(base), -offset)
-	}
+	a.op("mov", 'q', a.reg(dest), a.mem(base, offset))
 	a.Set(dest)
 }
 
 func (a *amd64) Load4Bytes(dest, base Reg, offset int) {
 	a.check(base)
-	switch {
-	case offset == 0:
-		a.insnf("mov %s, [%s]", a.reg4(dest), a.reg(base))
-	case offset > 0:
-		a.insnf("mov %s, [%s + %d]", a.reg4(dest), a.reg(base), offset)
-	default:
-		a.insnf("mov %s, [%s - %d]", a.reg4(dest), a.reg(base), -offset)
-	}
+	a.op("mov", 'l', a.reg4(dest), a.mem(base, offset))
 	a.Set(dest)
 }
 
 func (a *amd64) LoadByte(dest, base Reg, offset int) {
 	a.check(base)
-	switch {
-	case offset == 0:
-		a.insnf("mov %s, [%s]", a.reg1(dest), a.reg(base))
-	case offset > 0:
-		a.insnf("mov %s, [%s + %d]", a.reg1(dest), a.reg(base), offset)
-	default:
-		a.insnf("mov %s, [%s - %d]", a.reg1(dest), a.reg(base), -offset)
+	if a.att {
+		a.insn("movzbl", a.mem(base, offset), a.reg4(dest))
+	} else {
+		a.insn("movzx", a.reg4(dest), "byte ptr "+a.mem(base, offset))
 	}
 	a.Set(dest)
 }
@@ -433,86 +435,189 @@ func (a *amd64) LoadByte(dest, base Reg, offset int) {
 func (a *amd64) Store(base Reg, offset int, src Reg) {
 	a.check(base)
 	a.check(src)
-	switch {
-	case offset == 0:
-		a.insnf("mov [%s], %s", a.reg(base), a.reg(src))
-	case offset > 0:
-		a.insnf("mov [%s + %d], %s", a.reg(base), offset, a.reg(src))
-	default:
-		a.insnf("mov [%s - %d], %s", a.reg(base), -offset, a.reg(src))
-	}
+	a.op("mov", 'q', a.mem(base, offset), a.reg(src))
 }
 
 func (a *amd64) Store4Bytes(base Reg, offset int, src Reg) {
 	a.check(base)
 	a.check(src)
-	switch {
-	case offset == 0:
-		a.insnf("mov [%s], %s", a.reg(base), a.reg4(src))
-	case offset > 0:
-		a.insnf("mov [%s + %d], %s", a.reg(base), offset, a.reg4(src))
+	a.op("mov", 'l', a.mem(base, offset), a.reg4(src))
+}
+
+func (a *amd64) LoadGlobal(dest Reg, name string, offset int) {
+	a.op("mov", 'q', a.reg(dest), a.global(dest, name, offset))
+	a.Set(dest)
+}
+
+func (a *amd64) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.op("mov", 'l', a.reg4(dest), a.global(dest, name, offset))
+	a.Set(dest)
+}
+
+func (a *amd64) LoadGlobalByte(dest Reg, name string, offset int) {
+	if a.att {
+		a.insn("movzbl", a.global(dest, name, offset), a.reg4(dest))
+	} else {
+		a.insn("movzx", a.reg4(dest), "byte ptr "+a.global(dest, name, offset))
+	}
+	a.Set(dest)
+}
+
+func (a *amd64) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.op("mov", 'q', a.global(temp, name, offset), a.reg(src))
+	a.Set(temp.As(""))
+}
+
+func (a *amd64) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.op("mov", 'l', a.global(temp, name, offset), a.reg4(src))
+	a.Set(temp.As(""))
+}
+
+// global memory operand.  Address of external symbol is loaded into the
+// register.
+func (a *amd64) global(r Reg, name string, offset int) string {
+	if a.external[name] {
+		a.op("mov", 'q', a.reg(r), a.ripRelReloc(name, "GOTPCREL"))
+		return a.mem(r, offset)
+	}
+	return a.ripRelOffset(name, offset)
+}
+
+func (a *amd64) LoadThreadPointer(dest Reg) {
+	a.op("mov", 'q', a.reg(dest), a.fs("0"))
+	a.Set(dest)
+}
+
+func (a *amd64) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	a.LoadThreadPointer(dest)
+	switch model {
+	case InitialExecTLS:
+		a.op("add", 'q', a.reg(dest), a.ripRelReloc(name, "GOTTPOFF"))
+	case LocalExecTLS:
+		if a.att {
+			a.insn("leaq", symbol(name)+"@tpoff"+a.mem(dest, 0), a.reg(dest))
+		} else {
+			a.insnf("lea %s, [%s + %s@tpoff]", a.reg(dest), a.reg(dest), symbol(name))
+		}
 	default:
-		a.insnf("mov [%s - %d], %s", a.reg(base), -offset, a.reg4(src))
+		panic(model)
 	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+func (a *amd64) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	switch model {
+	case InitialExecTLS:
+		a.op("mov", 'q', a.reg(dest), a.ripRelReloc(name, "GOTTPOFF"))
+		a.op("mov", 'q', a.reg(dest), a.fs(a.mem(dest, offset)))
+	case LocalExecTLS:
+		a.op("mov", 'q', a.reg(dest), a.fs(tpoff(name, offset)))
+	default:
+		panic(model)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
 }
 
 func (a *amd64) Push(r Reg) {
 	a.check(r)
-	a.insn("push", a.reg(r))
+	a.op("push", 'q', a.reg(r))
+	a.cfiAdjust(8)
 }
 
 func (a *amd64) Pop(r Reg) {
-	a.insn("pop", a.reg(r))
+	a.op("pop", 'q', a.reg(r))
+	a.cfiAdjust(-8)
 	a.Set(r)
 }
 
 func (a *amd64) Jump(name string) {
-	a.insn("jmp", symbol(name))
+	a.insn("jmp", a.target(name))
 }
 
+// JumpRegRoutine uses a retpoline.  Internal names are generated from the
+// prefix, so it may be used many times with the same prefix.  The setup
+// routine is emitted inline, so the current symbol and call frame information
+// remain open.
 func (a *amd64) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	setup := a.internalLabel(internalNamePrefix + "_setup")
+	capture := a.internalLabel(internalNamePrefix + "_capture")
+
 	a.check(r)
-	a.Call(internalNamePrefix + "_setup")
+	a.Call(setup)
 
-	a.label(internalNamePrefix + "_capture")
+	// The labels don't restore call frame information state, so that it
+	// stays as it was after a possible epilogue.
+	a.define(capture)
+	a.printf("%s:", symbol(capture))
 	a.insn("pause")
-	a.Jump(internalNamePrefix + "_capture")
+	a.refer(capture)
+	a.Jump(capture)
 
-	a.FunctionWithoutPrologue(internalNamePrefix + "_setup")
+	a.define(setup)
+	a.printf("%s:", symbol(setup))
+	a.cfiAdjust(8) // Return address.
 	a.Store(a.StackPtr, 0, r)
 	a.MoveImm(r, 0)
-	a.Return()
+	a.insn("ret")
+	a.speculationBarrier()
+	a.cfiAdjust(-8)
 }
 
+func (a *amd64) jumpTable(index, base Reg, count, mask int, table, def string) {
+	a.op("cmp", 'q', a.reg(index), a.imm(count-1))
+	a.insn("ja", symbol(def))
+	if mask != 0 {
+		a.op("and", 'q', a.reg(index), a.imm(mask))
+	}
+	a.op("lea", 'q', a.reg(base), a.ripRel(table))
+	if a.att {
+		a.insn("movslq", fmt.Sprintf("(%s,%s,4)", a.reg(base), a.reg(index)), a.reg(index))
+	} else {
+		a.insnf("movsxd %s, dword ptr [%s + %s*4]", a.reg(index), a.reg(base), a.reg(index))
+	}
+	a.op("add", 'q', a.reg(index), a.reg(base))
+	if a.att {
+		a.insn("jmp", "*"+a.reg(index))
+	} else {
+		a.insn("jmp", a.reg(index))
+	}
+}
+
+// JumpIfBitSet uses bit test instruction for the upper bits, as they don't
+// fit in a sign-extended 32-bit test immediate.
 func (a *amd64) JumpIfBitSet(r Reg, bit uint, name string) {
 	a.check(r)
-	switch {
-	case bit < 31:
-		a.insn("test", a.reg4(r), a.imm(1<<bit))
-	default:
-		a.insn("test", a.reg(r), a.imm(1<<bit))
+	if bit < 31 {
+		a.op("test", 'l', a.reg4(r), a.imm(1<<bit))
+		a.insn("jne", symbol(name))
+	} else {
+		a.op("bt", 'q', a.reg(r), a.imm(int(bit)))
+		a.insn("jc", symbol(name))
 	}
-	a.insn("jne", symbol(name))
 }
 
 func (a *amd64) JumpIfBitNotSet(r Reg, bit uint, name string) {
 	a.check(r)
-	switch {
-	case bit < 31:
-		a.insn("test", a.reg4(r), a.imm(1<<bit))
-	default:
-		a.insn("test", a.reg(r), a.imm(1<<bit))
+	if bit < 31 {
+		a.op("test", 'l', a.reg4(r), a.imm(1<<bit))
+		a.insn("je", symbol(name))
+	} else {
+		a.op("bt", 'q', a.reg(r), a.imm(int(bit)))
+		a.insn("jnc", symbol(name))
 	}
-	a.insn("je", symbol(name))
 }
 
 func (a *amd64) JumpIfImm(c Cond, r Reg, value int, name string) {
 	a.check(r)
 	switch {
 	case value == 0 && (c == EQ || c == NE):
-		a.insn("test", a.reg(r), a.reg(r))
+		a.op("test", 'q', a.reg(r), a.reg(r))
 	default:
-		a.insn("cmp", a.reg(r), a.imm(value))
+		a.op("cmp", 'q', a.reg(r), a.imm(value))
 	}
 	a.insn("j"+a.cond(c), symbol(name))
 }
@@ -520,16 +625,17 @@ func (a *amd64) JumpIfImm(c Cond, r Reg, value int, name string) {
 func (a *amd64) JumpIfReg(c Cond, dest, src Reg, name string) {
 	a.check(dest)
 	a.check(src)
-	a.insn("cmp", a.reg(dest), a.reg(src))
+	a.op("cmp", 'q', a.reg(dest), a.reg(src))
 	a.insn("j"+a.cond(c), symbol(name))
 }
 
 func (a *amd64) Call(name string) {
-	a.insn("call", symbol(name))
+	a.refer(name)
+	a.insn("call", a.target(name))
 }
 
 func (a *amd64) Syscall(nr Syscall) {
-	a.MoveImm(a.SyscallNr, nr.AMD64)
+	a.MoveImm(a.SyscallNr, nr.num(AMD64))
 	a.insn("syscall")
 	a.Set(a.SysResult)
 }
@@ -542,28 +648,145 @@ func (a *amd64) speculationBarrier() {
 	a.insn("int3")
 }
 
+// op writes an instruction.  Operands are given in Intel order.  Size suffix
+// is used only in AT&T syntax; zero means none.
+func (a *amd64) op(mnemonic string, size byte, operands ...string) {
+	if !a.att {
+		a.insn(mnemonic, operands...)
+		return
+	}
+
+	if size != 0 {
+		mnemonic += string(size)
+	}
+	reversed := make([]string, len(operands))
+	for i, x := range operands {
+		reversed[len(operands)-1-i] = x
+	}
+	a.insn(mnemonic, reversed...)
+}
+
 func (a *amd64) imm(x int) string {
+	if a.att {
+		return fmt.Sprintf("$%d", x)
+	}
 	return fmt.Sprintf("%d", x)
 }
 
 func (a *amd64) imm64(x uint64) string {
+	if a.att {
+		return fmt.Sprintf("$%d", x)
+	}
 	return fmt.Sprintf("%d", x)
 }
 
+func (a *amd64) immSymbol(name string) string {
+	if a.att {
+		return "$" + symbol(name)
+	}
+	return symbol(name)
+}
+
+func (a *amd64) mem(base Reg, offset int) string {
+	return a.memReg(base.AMD64, offset)
+}
+
+func (a *amd64) memReg(base RegAMD64, offset int) string {
+	if a.att {
+		if offset == 0 {
+			return fmt.Sprintf("(%%%s)", base.reg())
+		}
+		return fmt.Sprintf("%d(%%%s)", offset, base.reg())
+	}
+
+	switch {
+	case offset == 0:
+		return fmt.Sprintf("[%s]", base.reg())
+	case offset > 0:
+		return fmt.Sprintf("[%s + %d]", base.reg(), offset)
+	default:
+		return fmt.Sprintf("[%s - %d]", base.reg(), -offset)
+	}
+}
+
+// ripRelReloc is a RIP-relative memory operand with relocation suffix.
+func (a *amd64) ripRelReloc(name, reloc string) string {
+	if a.att {
+		return fmt.Sprintf("%s@%s(%%rip)", symbol(name), reloc)
+	}
+	return fmt.Sprintf("[rip + %s@%s]", symbol(name), reloc)
+}
+
+// tpoff is the offset of a thread-local variable from the thread pointer.
+func tpoff(name string, offset int) string {
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("%s@tpoff+%d", symbol(name), offset)
+	case offset < 0:
+		return fmt.Sprintf("%s@tpoff-%d", symbol(name), -offset)
+	default:
+		return symbol(name) + "@tpoff"
+	}
+}
+
+// fs segment override of memory operand or absolute address.
+func (a *amd64) fs(operand string) string {
+	if a.att {
+		return "%fs:" + operand
+	}
+	return "fs:" + operand
+}
+
+// target of call or jump.
+func (a *amd64) target(name string) string {
+	if a.external[name] {
+		return symbol(name) + "@PLT"
+	}
+	return symbol(name)
+}
+
+func (a *amd64) memIndex(base, index Reg) string {
+	if a.att {
+		return fmt.Sprintf("(%s,%s)", a.reg(base), a.reg(index))
+	}
+	return fmt.Sprintf("[%s + %s]", a.reg(base), a.reg(index))
+}
+
+func (a *amd64) ripRel(name string) string {
+	return a.ripRelOffset(name, 0)
+}
+
+func (a *amd64) ripRelOffset(name string, offset int) string {
+	if a.att {
+		return symbolOffset(name, offset) + "(%rip)"
+	}
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("[rip + %s + %d]", symbol(name), offset)
+	case offset < 0:
+		return fmt.Sprintf("[rip + %s - %d]", symbol(name), -offset)
+	default:
+		return fmt.Sprintf("[rip + %s]", symbol(name))
+	}
+}
+
 func (a *amd64) reg(x Reg) string {
-	return x.AMD64.reg()
+	return a.regName(x.AMD64.reg())
 }
 
 func (a *amd64) reg4(x Reg) string {
-	return x.AMD64.reg4()
+	return a.regName(x.AMD64.reg4())
 }
 
-func (a *amd64) reg1(x Reg) string {
-	return x.AMD64.reg1()
+func (a *amd64) floatreg(x FloatReg) string {
+	return a.regName(fmt.Sprintf("xmm%d", x))
 }
 
-func (a *amd64) floatreg(x FloatReg) string {
-	return fmt.Sprintf("xmm%d", x)
+func (a *amd64) regName(s string) string {
+	if a.att {
+		return "%" + s
+	}
+	return s
 }
 
 func (a *amd64) cond(x Cond) string {
@@ -4,17 +4,86 @@
 
 package ga
 
+import (
+	"fmt"
+)
+
+// ArchID identifies CPU architecture in Reg and Specific values.  IDs of
+// architectures defined outside this package are allocated with NewArchID.
+type ArchID uint8
+
+const (
+	IDAMD64 ArchID = iota
+	IDARM64
+	IDRISCV64
+	IDPPC64LE
+	IDC
+
+	firstExternalID
+)
+
+var nextArchID = firstExternalID
+
+// NewArchID allocates an ID for an architecture defined outside this package.
+// It is not safe for concurrent use.
+func NewArchID() ArchID {
+	if nextArchID == 0 {
+		panic("too many architectures")
+	}
+	id := nextArchID
+	nextArchID++
+	return id
+}
+
 // Specific value per CPU architecture.
 type Specific struct {
-	AMD64 int
-	ARM64 int
+	AMD64   int
+	ARM64   int
+	RISCV64 int
+	PPC64LE int
+	Other   map[ArchID]int // Architectures defined outside this package.
+}
+
+// Value for the CPU architecture.  C uses the AMD64 value, as it mirrors
+// AMD64 register allocation.
+func (x Specific) Value(id ArchID) int {
+	switch id {
+	case IDAMD64, IDC:
+		return x.AMD64
+	case IDARM64:
+		return x.ARM64
+	case IDRISCV64:
+		return x.RISCV64
+	case IDPPC64LE:
+		return x.PPC64LE
+	}
+
+	if v, found := x.Other[id]; found {
+		return v
+	}
+	panic(fmt.Sprintf("no value for architecture #%d", id))
+}
+
+// With returns a copy with a value for an architecture defined outside this
+// package.
+func (x Specific) With(id ArchID, value int) Specific {
+	other := make(map[ArchID]int, len(x.Other)+1)
+	for k, v := range x.Other {
+		other[k] = v
+	}
+	other[id] = value
+	x.Other = other
+	return x
 }
 
 // Reg ister per CPU architecture.
 type Reg struct {
-	AMD64 RegAMD64
-	ARM64 RegARM64
-	Use   string
+	AMD64   RegAMD64
+	ARM64   RegARM64
+	RISCV64 RegRISCV64
+	PPC64LE RegPPC64LE
+	Use     string
+	Other   map[ArchID]uint8 // Architectures defined outside this package.
 }
 
 // As returns the same register with different usage.
@@ -23,18 +92,110 @@ func (r Reg) As(use string) Reg {
 	return r
 }
 
+// Num returns the register number for the CPU architecture.
+func (r Reg) Num(id ArchID) uint8 {
+	switch id {
+	case IDAMD64:
+		return uint8(r.AMD64)
+	case IDARM64:
+		return uint8(r.ARM64)
+	case IDRISCV64:
+		return uint8(r.RISCV64)
+	case IDPPC64LE:
+		return uint8(r.PPC64LE)
+	case IDC:
+		return uint8(r.AMD64) // C mirrors AMD64 register allocation.
+	}
+
+	if n, found := r.Other[id]; found {
+		return n
+	}
+	panic(fmt.Sprintf("register %q not defined for architecture #%d", r.Use, id))
+}
+
+// With returns a copy with a register number for an architecture defined
+// outside this package.
+func (r Reg) With(id ArchID, num uint8) Reg {
+	other := make(map[ArchID]uint8, len(r.Other)+1)
+	for k, v := range r.Other {
+		other[k] = v
+	}
+	other[id] = num
+	r.Other = other
+	return r
+}
+
+// RegSet is a bitmask of register numbers per CPU architecture.  Bit n
+// corresponds to the register which has number n.
+type RegSet Specific
+
+// Mask returns the register bits for the CPU architecture.  Architectures
+// defined outside this package have empty sets unless specified.
+func (s RegSet) Mask(id ArchID) uint32 {
+	if id >= firstExternalID {
+		return uint32(s.Other[id])
+	}
+	return uint32(Specific(s).Value(id))
+}
+
+// regNum returns a register which is defined only for the CPU architecture.
+func regNum(id ArchID, num uint8, use string) Reg {
+	switch id {
+	case IDAMD64, IDC:
+		return Reg{AMD64: RegAMD64(num), Use: use}
+	case IDARM64:
+		return Reg{ARM64: RegARM64(num), Use: use}
+	case IDRISCV64:
+		return Reg{RISCV64: RegRISCV64(num), Use: use}
+	case IDPPC64LE:
+		return Reg{PPC64LE: RegPPC64LE(num), Use: use}
+	}
+	return Reg{Use: use}.With(id, num)
+}
+
 // Syscall number per CPU architecture.
 type Syscall Specific
 
-// Arch itecture of CPU.
+// NoSyscall is the number of a syscall which doesn't exist on a CPU
+// architecture.  Making such a syscall panics.
+const NoSyscall = -1
+
+// num returns the syscall number for the CPU architecture.
+func (nr Syscall) num(arch Arch) int {
+	n := arch.Specify(Specific(nr))
+	if n == NoSyscall {
+		panic(fmt.Sprintf("syscall is not available on %s", arch.Machine()))
+	}
+	return n
+}
+
+// Arch itecture of CPU.  Architectures defined outside this package can be
+// added to Archs with RegisterArch.
 type Arch interface {
-	Machine() string      // GNU-style CPU architecture name (x86_64, aarch64).
+	ID() ArchID
+	Machine() string      // GNU-style CPU architecture name (x86_64, aarch64, riscv64, powerpc64le) or "c".
 	Specify(Specific) int // Get value for the CPU architecture.
-	newAssembly(*System, *buffer) ArchAssembly
+
+	// NewArchAssembly is called by NewAssembly.  The implementation may
+	// write a header to the output.
+	NewArchAssembly(*System, *Output) ArchAssembly
 }
 
-// Indexed by Go-style CPU architecture name (amd64, arm64).
+// Indexed by Go-style CPU architecture name (amd64, arm64, riscv64, ppc64le)
+// or "c".
 var Archs = map[string]Arch{
-	"amd64": AMD64,
-	"arm64": ARM64,
+	"amd64":   AMD64,
+	"arm64":   ARM64,
+	"riscv64": RISCV64,
+	"ppc64le": PPC64LE,
+	"c":       C,
+}
+
+// RegisterArch adds an architecture to Archs.  It panics if the name is
+// already taken.  It is not safe for concurrent use.
+func RegisterArch(name string, arch Arch) {
+	if _, exists := Archs[name]; exists {
+		panic(fmt.Sprintf("architecture already registered: %s", name))
+	}
+	Archs[name] = arch
 }
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"testing"
+
+	"gate.computer/ga"
+)
+
+// toyArch implements only a few instructions.
+type toyArch struct {
+	id ga.ArchID
+}
+
+func (arch *toyArch) ID() ga.ArchID             { return arch.id }
+func (*toyArch) Machine() string                { return "toy" }
+func (arch *toyArch) Specify(x ga.Specific) int { return x.Value(arch.id) }
+
+func (arch *toyArch) NewArchAssembly(sys *ga.System, out *ga.Output) ga.ArchAssembly {
+	out.WriteString("; toy\n")
+	return &toyAssembly{id: arch.id, out: out}
+}
+
+type toyAssembly struct {
+	ga.ArchAssembly // Unimplemented methods panic.
+	id              ga.ArchID
+	out             *ga.Output
+}
+
+func (a *toyAssembly) Set(r ga.Reg) {
+	a.out.SetUsage(r.Num(a.id), r.Use)
+}
+
+func (a *toyAssembly) Function(name string, opts ...ga.SymbolOptions) {
+	a.out.Label(name)
+}
+
+func (a *toyAssembly) MoveImm(dest ga.Reg, value int) {
+	a.out.Insnf("mov r%d, %d", dest.Num(a.id), value)
+	a.Set(dest)
+}
+
+func (a *toyAssembly) Return() {
+	a.out.Insn("ret")
+}
+
+func TestExternalArch(t *testing.T) {
+	arch := &toyArch{ga.NewArchID()}
+	ga.RegisterArch("toy", arch)
+	defer delete(ga.Archs, "toy")
+
+	sys := ga.Linux()
+	sys.StackPtr = sys.StackPtr.With(arch.id, 31)
+	acc := ga.Reg{AMD64: ga.RAX, ARM64: ga.X0, Use: "acc"}.With(arch.id, 10)
+	for i, r := range sys.LibParams {
+		sys.LibParams[i] = r.With(arch.id, uint8(i))
+	}
+
+	a := ga.NewAssembly(ga.Archs["toy"], sys)
+	a.Function("f")
+	a.MoveImm(acc, 1)
+	a.MoveImm(sys.LibParams[0], 2)
+	a.Return()
+
+	if s, want := a.String(), "; toy\n\"f\":\n\tmov\tr10, 1\n\tmov\tr0, 2\n\tret\n"; s != want {
+		t.Errorf("output:\n%s\nwant:\n%s", s, want)
+	}
+	if use := a.Usage()[10]; use != "acc" {
+		t.Errorf("usage: %q", use)
+	}
+
+	if n := sys.LibParams[0].Num(ga.AMD64.ID()); n != uint8(ga.RDI) {
+		t.Errorf("amd64 register: %d", n)
+	}
+	if acc.Num(ga.ARM64.ID()) != uint8(ga.X0) || acc.RISCV64 != ga.ZERO {
+		t.Errorf("acc: %#v", acc)
+	}
+}
+
+func TestRegisterArchTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+
+	ga.RegisterArch("amd64", ga.AMD64)
+}
+
+func TestRegWith(t *testing.T) {
+	id := ga.NewArchID()
+
+	x := ga.Reg{AMD64: ga.RAX, ARM64: ga.X0, Use: "x"}
+	y := x.With(id, 1)
+	z := y.With(id, 2)
+
+	if n := y.Num(id); n != 1 {
+		t.Errorf("y: %d", n)
+	}
+	if n := z.Num(id); n != 2 {
+		t.Errorf("z: %d", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+	x.Num(id)
+}
+
+func TestSpecificWith(t *testing.T) {
+	id := ga.NewArchID()
+
+	x := ga.Specific{AMD64: 1, ARM64: 2, RISCV64: 3, PPC64LE: 4}.With(id, 5)
+	for i, arch := range []ga.Arch{ga.AMD64, ga.ARM64, ga.RISCV64, ga.PPC64LE} {
+		if v := x.Value(arch.ID()); v != i+1 {
+			t.Errorf("%s: %d", arch.Machine(), v)
+		}
+	}
+	if v := x.Value(id); v != 5 {
+		t.Errorf("external: %d", v)
+	}
+}
@@ -112,6 +112,10 @@ type ArchARM64 struct {
 	ClearableRegs []RegARM64
 }
 
+func (*ArchARM64) ID() ArchID {
+	return IDARM64
+}
+
 func (*ArchARM64) Machine() string {
 	return "aarch64"
 }
@@ -124,14 +128,30 @@ func (arch *ArchARM64) ClearReg(a *Assembly, r RegARM64) {
 	if a.Arch != arch {
 		panic(a.Arch)
 	}
-	a.insn("mov", r.reg(), "0")
+	if _, ok := a.ArchAssembly.(*goARM64); ok {
+		a.insn("MOVD", "ZR", r.goReg())
+	} else {
+		a.insn("mov", r.reg(), "0")
+	}
 }
 
-func (*ArchARM64) newAssembly(sys *System, buf *buffer) ArchAssembly {
-	buf.WriteString(headerARM64)
+func (*ArchARM64) NewArchAssembly(sys *System, out *Output) ArchAssembly {
+	switch out.Syntax() {
+	case GoSyntax:
+		out.buf.WriteString(headerGo)
+		return &goARM64{
+			System: sys,
+			buffer: out.buf,
+		}
+
+	case ATTSyntax:
+		panic("unsupported syntax for arm64")
+	}
+
+	out.buf.WriteString(headerARM64)
 	return &arm64{
 		System: sys,
-		buffer: buf,
+		buffer: out.buf,
 	}
 }
 
@@ -148,38 +168,56 @@ func (a *arm64) Set(r Reg) {
 	a.buffer.regUsage[r.ARM64] = r.Use
 }
 
-func (a *arm64) Label(name string) {
+func (a *arm64) Label(name string, opts ...SymbolOptions) {
 	if global(name) {
-		a.printf("")
-		a.printf(".globl %s", symbol(name))
-		a.printf(".type  %s,@function", symbol(name))
+		a.declare(name, "@function", opts)
+	} else {
+		symbolOptions(name, opts)
 	}
 	a.printf("")
 	a.label(name)
+	if global(name) {
+		a.cfiStartProc(0)
+	}
 }
 
 func (a *arm64) FunctionEpilogue() {
-	a.insnf("ldr lr, [%s], 8", a.reg(a.StackPtr))
+	a.cfiRememberState()
+	if a.framePtr {
+		a.insnf("ldp %s, lr, [%s], 16", a.reg(a.FramePtr), a.reg(a.StackPtr))
+		a.cfiRestore(a.reg(a.FramePtr), 0)
+		a.cfiRestore(XLR.reg(), 8)
+		a.cfiAdjust(-16)
+		a.cfiDefStack(a.reg(a.StackPtr))
+	} else {
+		a.insnf("ldr lr, [%s], 16", a.reg(a.StackPtr))
+		a.cfiRestore(XLR.reg(), 0)
+		a.cfiAdjust(-16)
+	}
 }
 
-func (a *arm64) Function(name string) {
-	if global(name) {
-		a.printf("")
-		a.printf(".globl %s", symbol(name))
-		a.printf(".type  %s,@function", symbol(name))
+// Function prologue keeps the stack pointer aligned to 16 bytes.
+func (a *arm64) Function(name string, opts ...SymbolOptions) {
+	a.FunctionWithoutPrologue(name, opts...)
+	if a.framePtr {
+		a.insnf("stp %s, lr, [%s, -16]!", a.reg(a.FramePtr), a.reg(a.StackPtr))
+		a.cfiAdjust(16)
+		a.cfiSave(a.reg(a.FramePtr), 0)
+		a.cfiSave(XLR.reg(), 8)
+		a.insn("mov", a.reg(a.FramePtr), a.reg(a.StackPtr))
+		a.cfiDefFrame(a.reg(a.FramePtr), 0)
+	} else {
+		a.insnf("str lr, [%s, -16]!", a.reg(a.StackPtr))
+		a.cfiAdjust(16)
+		a.cfiSave(XLR.reg(), 0)
 	}
-	a.printf("")
-	a.label(name)
-	a.insnf("str lr, [%s, -8]!", a.reg(a.StackPtr))
 }
 
-func (a *arm64) FunctionWithoutPrologue(name string) {
-	if global(name) {
-		a.printf("")
-		a.printf(".globl %s", symbol(name))
-	}
+func (a *arm64) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.declare(name, "@function", opts)
 	a.printf("")
 	a.label(name)
+	a.cfiStartProc(0)
 }
 
 func (a *arm64) Return() {
@@ -190,14 +228,25 @@ func (a *arm64) Return() {
 func (a *arm64) ReturnWithoutEpilogue() {
 	a.insn("ret")
 	a.speculationBarrier()
+	a.cfiRestoreState()
 }
 
 func (a *arm64) Address(dest Reg, name string) {
-	a.insn("adr", a.reg(dest), symbol(name))
+	switch {
+	case a.external[name]:
+		a.insn("adrp", a.reg(dest), ":got:"+symbol(name))
+		a.insnf("ldr %s, [%s, :got_lo12:%s]", a.reg(dest), a.reg(dest), symbol(name))
+	case a.farAddress:
+		a.insn("adrp", a.reg(dest), symbol(name))
+		a.insn("add", a.reg(dest), a.reg(dest), ":lo12:"+symbol(name))
+	default:
+		a.insn("adr", a.reg(dest), symbol(name))
+	}
 	a.Set(dest)
 }
 
 func (a *arm64) MoveDef(dest Reg, name string) {
+	a.checkAbsolute(name)
 	a.insn("mov", a.reg(dest), symbol(name))
 	a.Set(dest)
 }
@@ -349,7 +398,7 @@ func (a *arm64) Load4Bytes(dest, base Reg, offset int) {
 
 func (a *arm64) LoadByte(dest, base Reg, offset int) {
 	a.check(base)
-	a.insnf("ldurb %s, [%s, %d]", a.reg4(dest), a.reg(base), offset)
+	a.insnf("ldrb %s, [%s, %d]", a.reg4(dest), a.reg(base), offset)
 	a.Set(dest)
 }
 
@@ -365,16 +414,117 @@ func (a *arm64) Store4Bytes(base Reg, offset int, src Reg) {
 	a.insnf("str %s, [%s, %d]", a.reg4(src), a.reg(base), offset)
 }
 
+// LoadGlobal uses :lo12: relocation, so the address must be aligned.
+func (a *arm64) LoadGlobal(dest Reg, name string, offset int) {
+	a.insnf("ldr %s, %s", a.reg(dest), a.global(dest, name, offset))
+	a.Set(dest)
+}
+
+// LoadGlobal4Bytes uses :lo12: relocation, so the address must be aligned.
+func (a *arm64) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.insnf("ldr %s, %s", a.reg4(dest), a.global(dest, name, offset))
+	a.Set(dest)
+}
+
+func (a *arm64) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.insnf("ldrb %s, %s", a.reg4(dest), a.global(dest, name, offset))
+	a.Set(dest)
+}
+
+// StoreGlobal uses :lo12: relocation, so the address must be aligned.
+func (a *arm64) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insnf("str %s, %s", a.reg(src), a.global(temp, name, offset))
+	a.Set(temp.As(""))
+}
+
+// StoreGlobal4Bytes uses :lo12: relocation, so the address must be aligned.
+func (a *arm64) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insnf("str %s, %s", a.reg4(src), a.global(temp, name, offset))
+	a.Set(temp.As(""))
+}
+
+// global memory operand.  Page address, or address of external symbol, is
+// loaded into the register.
+func (a *arm64) global(r Reg, name string, offset int) string {
+	if a.external[name] {
+		a.insn("adrp", a.reg(r), ":got:"+symbol(name))
+		a.insnf("ldr %s, [%s, :got_lo12:%s]", a.reg(r), a.reg(r), symbol(name))
+		return fmt.Sprintf("[%s, %d]", a.reg(r), offset)
+	}
+	a.insn("adrp", a.reg(r), symbolOffset(name, offset))
+	return fmt.Sprintf("[%s, :lo12:%s]", a.reg(r), symbolOffset(name, offset))
+}
+
+func (a *arm64) LoadThreadPointer(dest Reg) {
+	a.insn("mrs", a.reg(dest), "tpidr_el0")
+	a.Set(dest)
+}
+
+func (a *arm64) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	switch model {
+	case InitialExecTLS:
+		a.insn("adrp", a.reg(temp), ":gottprel:"+symbol(name))
+		a.insnf("ldr %s, [%s, :gottprel_lo12:%s]", a.reg(temp), a.reg(temp), symbol(name))
+		a.insn("mrs", a.reg(dest), "tpidr_el0")
+		a.insn("add", a.reg(dest), a.reg(dest), a.reg(temp))
+	case LocalExecTLS:
+		a.insn("mrs", a.reg(dest), "tpidr_el0")
+		a.insn("add", a.reg(dest), a.reg(dest), "#:tprel_hi12:"+symbol(name), "lsl #12")
+		a.insn("add", a.reg(dest), a.reg(dest), ":tprel_lo12_nc:"+symbol(name))
+	default:
+		panic(model)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+// LoadThreadLocal uses :tprel_lo12_nc: relocation with LocalExecTLS, so the
+// address must be aligned.
+func (a *arm64) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	switch model {
+	case InitialExecTLS:
+		a.ThreadLocalAddress(dest, name, model, temp)
+		a.insnf("ldr %s, [%s, %d]", a.reg(dest), a.reg(dest), offset)
+	case LocalExecTLS:
+		a.insn("mrs", a.reg(dest), "tpidr_el0")
+		a.insn("add", a.reg(dest), a.reg(dest), "#:tprel_hi12:"+symbolOffset(name, offset), "lsl #12")
+		a.insnf("ldr %s, [%s, :tprel_lo12_nc:%s]", a.reg(dest), a.reg(dest), symbolOffset(name, offset))
+	default:
+		panic(model)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+// Push uses a 16-byte stack slot to keep the stack pointer aligned.
 func (a *arm64) Push(r Reg) {
 	a.check(r)
-	a.insnf("str %s, [%s, -8]!", a.reg(r), a.reg(a.StackPtr))
+	a.insnf("str %s, [%s, -16]!", a.reg(r), a.reg(a.StackPtr))
+	a.cfiAdjust(16)
 }
 
 func (a *arm64) Pop(r Reg) {
-	a.insnf("ldr %s, [%s], 8", a.reg(r), a.reg(a.StackPtr))
+	a.insnf("ldr %s, [%s], 16", a.reg(r), a.reg(a.StackPtr))
+	a.cfiAdjust(-16)
 	a.Set(r)
 }
 
+func (a *arm64) pushPair(r1, r2 Reg) {
+	a.check(r1)
+	a.check(r2)
+	a.insnf("stp %s, %s, [%s, -16]!", a.reg(r2), a.reg(r1), a.reg(a.StackPtr))
+	a.cfiAdjust(16)
+}
+
+func (a *arm64) popPair(r1, r2 Reg) {
+	a.insnf("ldp %s, %s, [%s], 16", a.reg(r2), a.reg(r1), a.reg(a.StackPtr))
+	a.cfiAdjust(-16)
+	a.Set(r1)
+	a.Set(r2)
+}
+
 func (a *arm64) Jump(name string) {
 	a.insn("b", symbol(name))
 }
@@ -395,6 +545,19 @@ func (a *arm64) JumpIfBitNotSet(r Reg, bit uint, name string) {
 	a.insn("tbz", a.reg(r), a.imm(int(bit)), symbol(name))
 }
 
+// jumpTable addresses the table with adr regardless of WithFarAddress.
+func (a *arm64) jumpTable(index, base Reg, count, mask int, table, def string) {
+	a.insn("cmp", a.reg(index), a.imm(count-1))
+	a.insn("b.hi", symbol(def))
+	if mask != 0 {
+		a.insn("and", a.reg(index), a.reg(index), a.imm(mask))
+	}
+	a.insn("adr", a.reg(base), symbol(table))
+	a.insnf("ldrsw %s, [%s, %s, lsl 2]", a.reg(index), a.reg(base), a.reg(index))
+	a.insn("add", a.reg(index), a.reg(base), a.reg(index))
+	a.insn("br", a.reg(index))
+}
+
 func (a *arm64) JumpIfImm(c Cond, r Reg, value int, name string) {
 	a.check(r)
 	a.insn("cmp", a.reg(r), a.imm(value))
@@ -409,11 +572,12 @@ func (a *arm64) JumpIfReg(c Cond, dest, src Reg, name string) {
 }
 
 func (a *arm64) Call(name string) {
+	a.refer(name)
 	a.insn("bl", symbol(name))
 }
 
 func (a *arm64) Syscall(nr Syscall) {
-	a.insn("mov", a.SyscallNr.ARM64.reg4(), a.imm(nr.ARM64))
+	a.insn("mov", a.SyscallNr.ARM64.reg4(), a.imm(nr.num(ARM64)))
 	a.insn("svc", a.imm(0))
 	a.Set(a.SysResult)
 }
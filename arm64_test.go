@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestARM64StackAlignment(t *testing.T) {
+	a := ga.NewAssembly(ga.ARM64, sys)
+	a.Function("f")
+	a.Set(r0)
+	a.Set(r1)
+	a.Push(r0)
+	a.PushPair(r0, r1)
+	if n := a.StackDepth(); n != 32 {
+		t.Errorf("stack depth: %d", n)
+	}
+	a.PopPair(r0, r1)
+	a.Pop(r0)
+	if n := a.StackDepth(); n != 0 {
+		t.Errorf("stack depth: %d", n)
+	}
+	a.Return()
+
+	s := a.String()
+	for _, insn := range []string{
+		"\tstr\tlr, [sp, -16]!\n",
+		"\tstr\tx19, [sp, -16]!\n",
+		"\tstp\tx20, x19, [sp, -16]!\n",
+		"\tldp\tx20, x19, [sp], 16\n",
+		"\tldr\tx19, [sp], 16\n",
+		"\tldr\tlr, [sp], 16\n",
+	} {
+		if !strings.Contains(s, insn) {
+			t.Errorf("%q not found in:\n%s", insn, s)
+		}
+	}
+	assembleCheck(t, ga.ARM64, s)
+}
+
+func TestStackDepth(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			a.Function("f")
+			a.Set(r0)
+			a.Set(r1)
+			a.Push(r0)
+			a.PushPair(r0, r1)
+			want := map[ga.Arch]int{ga.ARM64: 32, ga.RISCV64: 48, ga.PPC64LE: 96}[arch]
+			if want == 0 {
+				want = 24
+			}
+			if n := a.StackDepth(); n != want {
+				t.Errorf("stack depth: %d", n)
+			}
+			a.Function("g")
+			if n := a.StackDepth(); n != 0 {
+				t.Errorf("stack depth after Function: %d", n)
+			}
+		})
+	}
+}
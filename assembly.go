@@ -39,6 +39,29 @@ const (
 
 type FloatReg uint8
 
+// Global reports if the name is not local to the assembly (i.e. it doesn't
+// start with ".").
+func Global(name string) bool {
+	return global(name)
+}
+
+// Symbol reference in assembly source.  Names which start with "." are local.
+func Symbol(name string) string {
+	return symbol(name)
+}
+
+// symbolOffset is an assembler expression: symbol plus offset.
+func symbolOffset(name string, offset int) string {
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("%s+%d", symbol(name), offset)
+	case offset < 0:
+		return fmt.Sprintf("%s-%d", symbol(name), -offset)
+	default:
+		return symbol(name)
+	}
+}
+
 func global(name string) bool {
 	return !strings.HasPrefix(name, ".")
 }
@@ -57,11 +80,31 @@ type Assembly struct {
 	*buffer
 }
 
-func NewAssembly(arch Arch, sys *System) *Assembly {
+func NewAssembly(arch Arch, sys *System, opts ...Option) *Assembly {
 	buf := new(buffer)
+	for _, o := range opts {
+		o(&buf.options)
+	}
+	if buf.syntax == GoSyntax && (buf.pic || len(buf.external) > 0) {
+		panic("position-independent code and external symbols are supported only with GNU assembler")
+	}
+	if buf.framePtr {
+		switch id := arch.ID(); {
+		case buf.syntax == GoSyntax, id != IDAMD64 && id != IDARM64 && id != IDRISCV64:
+			panic("frame pointer is not supported with this syntax or architecture")
+		}
+	}
+	if buf.callFrameInfo && (buf.syntax == GoSyntax || arch.ID() == IDC) {
+		panic("call frame information is not supported with this syntax or architecture")
+	}
+	if buf.sourceInfo == SourceLines && buf.syntax == GoSyntax {
+		panic("source lines are not supported with this syntax")
+	}
+	buf.comment = commentPrefix(arch.ID(), buf.syntax)
+
 	a := &Assembly{
 		Arch:         arch,
-		ArchAssembly: arch.newAssembly(sys, buf),
+		ArchAssembly: arch.NewArchAssembly(sys, &Output{buf}),
 		System:       sys,
 		buffer:       buf,
 	}
@@ -77,22 +120,226 @@ func (a *Assembly) Reset(regs ...Reg) {
 		a.Set(r)
 	}
 	a.Set(a.StackPtr)
+	if a.framePtr {
+		a.Set(a.FramePtr)
+	}
+}
+
+// Usage of registers, indexed by architecture-specific register number.
+// Unused registers have empty usage.
+func (a *Assembly) Usage() []string {
+	return append([]string(nil), a.regUsage[:]...)
+}
+
+// Function defines a function and resets stack depth.
+func (a *Assembly) Function(name string, opts ...SymbolOptions) {
+	a.define(name)
+	a.ArchAssembly.Function(name, opts...)
+	a.stack = 0
+}
+
+// FunctionWithoutPrologue defines a function and resets stack depth.
+func (a *Assembly) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.define(name)
+	a.ArchAssembly.FunctionWithoutPrologue(name, opts...)
+	a.stack = 0
+}
+
+// Push a register.  On ARM64 and RISC-V the stack slot is 16 bytes so that
+// the stack pointer stays aligned, and on PPC64LE it is a minimal ELFv2 frame;
+// on other architectures it is 8 bytes.
+func (a *Assembly) Push(r Reg) {
+	a.ArchAssembly.Push(r)
+	a.stack += a.pushSize()
+	a.cfiPush(r, 0)
+}
+
+func (a *Assembly) Pop(r Reg) {
+	a.ArchAssembly.Pop(r)
+	a.stack -= a.pushSize()
+	a.cfiPop(r, -a.pushSize())
+}
+
+func (a *Assembly) MoveReg(dest, src Reg) {
+	a.ArchAssembly.MoveReg(dest, src)
+	a.cfiStackPtrUpdate(dest, src, 0)
+}
+
+func (a *Assembly) AddImm(dest, src Reg, value int) {
+	a.ArchAssembly.AddImm(dest, src, value)
+	a.cfiStackPtrUpdate(dest, src, value)
+}
+
+func (a *Assembly) SubtractImm(dest Reg, value int) {
+	a.ArchAssembly.SubtractImm(dest, value)
+	a.cfiStackPtrUpdate(dest, dest, -value)
+}
+
+// PushPair pushes two registers.  ARM64 stores both into a single 16-byte
+// stack slot, with r2 at the lower address.  Other architectures push r1 and
+// r2 separately.
+func (a *Assembly) PushPair(r1, r2 Reg) {
+	if p, ok := a.ArchAssembly.(pairStacker); ok {
+		p.pushPair(r1, r2)
+		a.stack += 16
+		a.cfiPush(r2, 0)
+		a.cfiPush(r1, 8)
+	} else {
+		a.Push(r1)
+		a.Push(r2)
+	}
+}
+
+// PopPair restores registers pushed by PushPair.  The arguments are in the
+// same order as with PushPair.
+func (a *Assembly) PopPair(r1, r2 Reg) {
+	if p, ok := a.ArchAssembly.(pairStacker); ok {
+		p.popPair(r1, r2)
+		a.stack -= 16
+		a.cfiPop(r2, -16)
+		a.cfiPop(r1, -8)
+	} else {
+		a.Pop(r2)
+		a.Pop(r1)
+	}
+}
+
+// StackDepth is the number of bytes pushed since the start of the current
+// function, excluding the prologue.  It is not affected by explicit stack
+// pointer arithmetic.
+func (a *Assembly) StackDepth() int {
+	return a.stack
+}
+
+func (a *Assembly) pushSize() int {
+	switch a.Arch.ID() {
+	case IDARM64, IDRISCV64:
+		return 16
+	case IDPPC64LE:
+		return minFramePPC64LE
+	}
+	return 8
+}
+
+// Label definition.  If a local label is the target of preceding jumps, the
+// stack depth (and call frame information) is taken from the jump site, and
+// registers destroyed by a Switch jump table become unused.
+func (a *Assembly) Label(name string, opts ...SymbolOptions) {
+	a.define(name)
+	a.ArchAssembly.Label(name, opts...)
+	if s, found := a.jumps[name]; found {
+		a.stack = s.stack
+		a.cfiSetState(s.cfi)
+		for _, r := range s.clobbers {
+			a.Set(r.As(""))
+		}
+	}
+}
+
+func (a *Assembly) Jump(name string) {
+	a.ArchAssembly.Jump(name)
+	a.recordJump(name)
+}
+
+func (a *Assembly) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.ArchAssembly.JumpIfBitSet(r, bit, name)
+	a.recordJump(name)
+}
+
+func (a *Assembly) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.ArchAssembly.JumpIfBitNotSet(r, bit, name)
+	a.recordJump(name)
 }
 
+func (a *Assembly) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.ArchAssembly.JumpIfImm(c, r, value, name)
+	a.recordJump(name)
+}
+
+func (a *Assembly) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.ArchAssembly.JumpIfReg(c, dest, src, name)
+	a.recordJump(name)
+}
+
+// recordJump remembers the stack state at a jump to a local label.
+func (a *Assembly) recordJump(name string) {
+	a.refer(name)
+	if global(name) {
+		return
+	}
+	if a.jumps == nil {
+		a.jumps = make(map[string]jumpState)
+	}
+	a.jumps[name] = jumpState{a.stack, a.cfi.clone(), a.jumps[name].clobbers}
+}
+
+// Call a function.  Registers in System.LibClobbers become unused.
+func (a *Assembly) Call(name string) {
+	a.ArchAssembly.Call(name)
+	a.clobber(a.LibClobbers)
+}
+
+// Syscall makes a system call.  Registers in System.SysClobbers become unused,
+// and System.SysResult is set.
+func (a *Assembly) Syscall(nr Syscall) {
+	a.ArchAssembly.Syscall(nr)
+	a.clobber(a.SysClobbers)
+}
+
+func (a *Assembly) clobber(s RegSet) {
+	mask := s.Mask(a.Arch.ID())
+	for n := range a.regUsage {
+		if mask&(1<<uint(n)) != 0 {
+			a.regUsage[n] = ""
+		}
+	}
+}
+
+// Bytes of the source file.  Names are not checked; see BytesChecked.
 func (a *Assembly) Bytes() []byte {
-	return a.buffer.Bytes()
+	b := a.buffer.Bytes()
+	if s := a.symbolEnd(); s != "" {
+		b = append(b[:len(b):len(b)], s...)
+	}
+	if f, ok := a.ArchAssembly.(finisher); ok {
+		b = f.finish(b)
+	}
+	return b
+}
+
+// BytesChecked returns the source file, or an error if Check fails.  It
+// should be used when the source is going to be assembled, so that undefined
+// and duplicate names are reported in terms of the generator instead of
+// assembler errors.
+func (a *Assembly) BytesChecked() ([]byte, error) {
+	if err := a.Check(); err != nil {
+		return nil, err
+	}
+	return a.Bytes(), nil
 }
 
 func (a *Assembly) String() string {
-	return a.buffer.String()
+	return string(a.Bytes())
+}
+
+// finisher is implemented by backends which need to amend the output.
+type finisher interface {
+	finish(src []byte) []byte
+}
+
+// pairStacker is implemented by backends which can push two registers with a
+// single instruction.
+type pairStacker interface {
+	pushPair(r1, r2 Reg)
+	popPair(r1, r2 Reg)
 }
 
 type ArchAssembly interface {
 	Set(Reg)
-	Label(name string)
+	Label(name string, opts ...SymbolOptions)
 	FunctionEpilogue()
-	Function(name string)
-	FunctionWithoutPrologue(name string)
+	Function(name string, opts ...SymbolOptions)
+	FunctionWithoutPrologue(name string, opts ...SymbolOptions)
 	Return()
 	ReturnWithoutEpilogue()
 	Address(dest Reg, name string)
@@ -116,6 +363,14 @@ type ArchAssembly interface {
 	LoadByte(dest, base Reg, offset int)
 	Store(base Reg, offset int, src Reg)
 	Store4Bytes(base Reg, offset int, src Reg)
+	LoadGlobal(dest Reg, name string, offset int)
+	LoadGlobal4Bytes(dest Reg, name string, offset int)
+	LoadGlobalByte(dest Reg, name string, offset int)
+	StoreGlobal(name string, offset int, src, temp Reg)
+	StoreGlobal4Bytes(name string, offset int, src, temp Reg)
+	LoadThreadPointer(dest Reg)
+	ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg)
+	LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg)
 	Push(Reg)
 	Pop(Reg)
 	Call(name string)
@@ -129,9 +384,76 @@ type ArchAssembly interface {
 	Unreachable()
 }
 
+// Output of an architecture backend.  It is used by architectures defined
+// outside this package.
+type Output struct {
+	buf *buffer
+}
+
+// WriteString appends raw text.
+func (o *Output) WriteString(s string) {
+	o.buf.WriteString(s)
+}
+
+// Printf writes a directive or other line of text.  The first field is
+// separated from the rest by a tab.
+func (o *Output) Printf(format string, args ...interface{}) {
+	o.buf.printf(format, args...)
+}
+
+// Label definition.
+func (o *Output) Label(name string) {
+	o.buf.label(name)
+}
+
+// Insn writes an instruction with comma-separated operands.
+func (o *Output) Insn(mnemonic string, operands ...string) {
+	o.buf.insn(mnemonic, operands...)
+}
+
+// Insnf writes a formatted instruction.
+func (o *Output) Insnf(format string, args ...interface{}) {
+	o.buf.insnf(format, args...)
+}
+
+// Syntax which was selected with WithSyntax.
+func (o *Output) Syntax() Syntax {
+	return o.buf.syntax
+}
+
+// SetUsage of a register.  Register number must be less than 32.
+func (o *Output) SetUsage(reg uint8, use string) {
+	o.buf.regUsage[reg] = use
+}
+
+// CheckUsage panics if the register is not in use, or if it is used for
+// something else.
+func (o *Output) CheckUsage(reg uint8, use string) {
+	o.buf.checkUsage(reg, use)
+}
+
 type buffer struct {
 	bytes.Buffer
+	options
 	regUsage [32]string
+	sized    string // Global symbol which needs size directive.
+	section  Section
+	labels   int // Number of generated labels.
+	stack    int // Bytes pushed since function start.
+	cfi      cfiState
+	jumps    map[string]jumpState
+	defined  map[string]int // Definition counts by name.
+	referred map[string]int // Reference counts by name.
+	files    map[string]int // Source file numbers.
+	loc      sourceLocation // Current source location.
+	comment  string         // Line comment prefix.
+}
+
+// jumpState at a jump site.
+type jumpState struct {
+	stack    int
+	cfi      cfiState
+	clobbers []Reg // Registers which are destroyed on the way.
 }
 
 func (b *buffer) checkUsage(reg uint8, use string) {
@@ -144,11 +466,20 @@ func (b *buffer) checkUsage(reg uint8, use string) {
 	}
 }
 
+// checkAbsolute panics in position-independent mode.
+func (b *buffer) checkAbsolute(name string) {
+	if b.pic {
+		panic(fmt.Sprintf("absolute relocation of %s in position-independent code", name))
+	}
+}
+
 func (b *buffer) label(name string) {
+	b.cfiRestoreState()
 	b.printf("%s:", symbol(name))
 }
 
 func (b *buffer) insn(mnemonic string, operands ...string) {
+	end := b.insnSource(mnemonic)
 	b.WriteString("\t" + mnemonic)
 
 	for i, field := range operands {
@@ -162,11 +493,17 @@ func (b *buffer) insn(mnemonic string, operands ...string) {
 		b.WriteString(field)
 	}
 
-	fmt.Fprintln(&b.Buffer)
+	b.WriteString(end)
 }
 
 func (b *buffer) insnf(format string, args ...interface{}) {
-	for i, field := range strings.Fields(fmt.Sprintf(format, args...)) {
+	fields := strings.Fields(fmt.Sprintf(format, args...))
+	end := "\n"
+	if len(fields) > 0 {
+		end = b.insnSource(fields[0])
+	}
+
+	for i, field := range fields {
 		switch i {
 		case 0, 1:
 			b.WriteString("\t")
@@ -177,7 +514,7 @@ func (b *buffer) insnf(format string, args ...interface{}) {
 		b.WriteString(field)
 	}
 
-	fmt.Fprintln(&b.Buffer)
+	b.WriteString(end)
 }
 
 func (b *buffer) printf(format string, args ...interface{}) {
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/linux"
+)
+
+var (
+	sys  = ga.Linux()
+	r0   = ga.Reg{AMD64: ga.RBX, ARM64: ga.X19, RISCV64: ga.S1, PPC64LE: ga.GPR14, Use: "r0"}
+	r1   = ga.Reg{AMD64: ga.R12, ARM64: ga.X20, RISCV64: ga.S2, PPC64LE: ga.GPR15, Use: "r1"}
+	r2   = ga.Reg{AMD64: ga.R13, ARM64: ga.X21, RISCV64: ga.S3, PPC64LE: ga.GPR16, Use: "r2"}
+	temp = ga.Reg{AMD64: ga.R14, ARM64: ga.X22, RISCV64: ga.S4, PPC64LE: ga.GPR17, Use: "temp"}
+)
+
+// llvm-mc configurations by ga.Arch.Machine value.
+var mcTargets = map[string]struct {
+	options []string
+	unquote bool // Branch targets must be bare symbol names.
+}{
+	"x86_64":      {options: []string{"-triple=x86_64"}},
+	"aarch64":     {options: []string{"-triple=aarch64"}},
+	"riscv64":     {options: []string{"-triple=riscv64", "-mattr=+m,+a,+f,+d,+c"}, unquote: true},
+	"powerpc64le": {options: []string{"-triple=powerpc64le", "-mcpu=pwr8"}, unquote: true},
+}
+
+var quotedSymbol = regexp.MustCompile(`"([A-Za-z_.][A-Za-z0-9_.]*)"`)
+
+// assembleCheck verifies that an assembler accepts the source.  It does
+// nothing if llvm-mc is not installed.
+func assembleCheck(t *testing.T, arch ga.Arch, source string) {
+	t.Helper()
+
+	mc, err := exec.LookPath("llvm-mc")
+	if err != nil {
+		return
+	}
+
+	target, found := mcTargets[arch.Machine()]
+	if !found {
+		t.Fatalf("no llvm-mc configuration for %s", arch.Machine())
+	}
+	if target.unquote {
+		lines := strings.Split(source, "\n")
+		for i, line := range lines {
+			if !strings.HasPrefix(strings.TrimSpace(line), ".ascii") {
+				lines[i] = quotedSymbol.ReplaceAllString(line, "$1")
+			}
+		}
+		source = strings.Join(lines, "\n")
+	}
+
+	cmd := exec.Command(mc, append(target.options, "-filetype=obj", "-o", os.DevNull)...)
+	cmd.Stdin = strings.NewReader(source)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("llvm-mc: %v\n%s\n%s", err, out, source)
+	}
+}
+
+// everything generates every kind of instruction.  Immediate values are
+// encodable on every architecture.
+func everything(a *ga.Assembly) {
+	a.Function("everything")
+	a.MoveImm(r0, 0)
+	a.MoveImm(r1, -1)
+	a.MoveImm(r2, 0x12345678)
+	a.MoveImm64(r0, 0xfedcba9876543210)
+	a.MoveReg(r1, r0)
+	a.MoveRegFloat(r2, 1)
+	a.MoveDef(r0, "def")
+	a.Address(r1, "everything")
+	a.AddImm(r0, r1, 4095)
+	a.AddImm(r0, r0, -1)
+	a.AddReg(r0, r1, r2)
+	a.SubtractImm(r0, 100)
+	a.SubtractReg(r0, r1)
+	a.MultiplyImm(r0, r1, 1000, temp)
+	a.AndImm(r0, 0xff)
+	a.AndReg(r0, r1)
+	a.OrImm(r0, 0xf00)
+	a.OrReg(r0, r1)
+	a.ShiftImm(ga.Left, r0, 3)
+	a.ShiftImm(ga.RightLogical, r0, 63)
+	a.ShiftImm(ga.RightArithmetic, r0, 1)
+	a.Load(r1, r0, 8)
+	a.Load4Bytes(r1, r0, -4)
+	a.LoadByte(r1, r0, 1)
+	a.Store(r0, 16, r1)
+	a.Store4Bytes(r0, 0, r1)
+	a.LoadGlobal(r0, "everything_data", 8)
+	a.LoadGlobal4Bytes(r1, "everything_data", 4)
+	a.LoadGlobalByte(r1, "everything_data", -1)
+	a.StoreGlobal("everything_data", 16, r0, temp)
+	a.StoreGlobal4Bytes("everything_data", 0, r1, temp)
+	a.Push(r0)
+	a.Pop(r1)
+	a.JumpIfBitSet(r0, 0, "everything_label")
+	a.JumpIfBitNotSet(r0, 63, "everything_label")
+	for _, c := range []ga.Cond{ga.EQ, ga.NE, ga.LT, ga.LE, ga.GT, ga.GE} {
+		a.JumpIfImm(c, r0, 0, "everything_label")
+		a.JumpIfImm(c, r0, 100, "everything_label")
+		a.JumpIfReg(c, r0, r1, "everything_label")
+	}
+	a.Call("everything")
+	a.Label("everything_label")
+	a.Syscall(linux.SYS_GETPID)
+	a.JumpRegRoutine(r1, "everything_retpoline")
+	a.Return()
+	a.FunctionWithoutPrologue("leaf")
+	a.Jump("everything")
+	a.ReturnWithoutEpilogue()
+	a.Unreachable()
+}
+
+func TestAssemble(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue // See TestC.
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			everything(a)
+			assembleCheck(t, arch, ".set def, 42\n"+a.String())
+		})
+	}
+}
+
+func TestFarAddress(t *testing.T) {
+	for _, x := range []struct {
+		opts []ga.Option
+		want string
+	}{
+		{nil, "\tadr\tx19, \"x\"\n"},
+		{[]ga.Option{ga.WithFarAddress()}, "\tadrp\tx19, \"x\"\n\tadd\tx19, x19, :lo12:\"x\"\n"},
+	} {
+		a := ga.NewAssembly(ga.ARM64, sys, x.opts...)
+		a.Address(r0, "x")
+		if s := a.String(); !strings.Contains(s, x.want) {
+			t.Errorf("unexpected output:\n%s", s)
+		}
+		assembleCheck(t, ga.ARM64, a.String())
+	}
+}
+
+func TestPIC(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys, ga.WithPIC(), ga.WithExternal("ext_data", "ext_func"))
+			a.Function("pic")
+			a.Address(r0, "ext_data")
+			a.Address(r1, "pic")
+			a.LoadGlobal(r0, "ext_data", 8)
+			a.LoadGlobal4Bytes(r1, "ext_data", 4)
+			a.LoadGlobalByte(r2, "ext_data", 1)
+			a.StoreGlobal("ext_data", 16, r0, temp)
+			a.StoreGlobal4Bytes("ext_data", 0, r1, temp)
+			a.Call("ext_func")
+			a.Call("pic")
+			a.Return()
+
+			s := a.String()
+			for _, x := range map[string][]string{
+				"x86_64":      {"\"ext_data\"@GOTPCREL", "\tcall\t\"ext_func\"@PLT\n", "\tcall\t\"pic\"\n"},
+				"aarch64":     {":got:\"ext_data\"", ":got_lo12:\"ext_data\""},
+				"riscv64":     {"%got_pcrel_hi(\"ext_data\")", "\tcall\t\"ext_func\"@plt\n", "\tcall\t\"pic\"\n"},
+				"powerpc64le": {"\"ext_data\"@got@ha", "\"ext_data\"@got@l("},
+			}[arch.Machine()] {
+				if !strings.Contains(s, x) {
+					t.Errorf("%q not found in output:\n%s", x, s)
+				}
+			}
+			assembleCheck(t, arch, s)
+
+			defer func() {
+				if x := recover(); x == nil {
+					t.Error("MoveDef did not panic")
+				}
+			}()
+			a.MoveDef(r0, "def")
+		})
+	}
+}
+
+func TestPICGoSyntax(t *testing.T) {
+	for _, opt := range []ga.Option{ga.WithPIC(), ga.WithExternal("x")} {
+		func() {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Error("no panic")
+				}
+			}()
+			ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.GoSyntax), opt)
+		}()
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestATTSyntax(t *testing.T) {
+	intel := ga.NewAssembly(ga.AMD64, sys)
+	everything(intel)
+
+	att := ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.ATTSyntax))
+	everything(att)
+
+	s := att.String()
+	for _, x := range []string{
+		".att_syntax prefix\n",
+		"\tmovq\t%rbx, %r12\n",
+		"\tmovq\t$18364758544493064720, %rbx\n",
+		"\tmovq\t%r12, 16(%rbx)\n",
+		"\tmovzbl\t1(%rbx), %r12d\n",
+		"\tbtq\t$63, %rbx\n",
+	} {
+		if !strings.Contains(s, x) {
+			t.Errorf("%q not found in:\n%s", x, s)
+		}
+	}
+
+	assembleCheck(t, ga.AMD64, ".set def, 42\n"+s)
+
+	if _, err := exec.LookPath("as"); err != nil {
+		t.Skip(err)
+	}
+	if _, err := exec.LookPath("objcopy"); err != nil {
+		t.Skip(err)
+	}
+
+	if x, y := gnuSection(t, ".set def, 42\n"+intel.String(), ".text"), gnuSection(t, ".set def, 42\n"+s, ".text"); !bytes.Equal(x, y) {
+		t.Error("Intel and AT&T syntax produce different machine code")
+	}
+}
+
+func TestATTSyntaxUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+
+	ga.NewAssembly(ga.ARM64, sys, ga.WithSyntax(ga.ATTSyntax))
+}
@@ -0,0 +1,624 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const headerC = `// Generated by gate.computer/ga, DO NOT EDIT!
+
+#ifndef _GNU_SOURCE
+#define _GNU_SOURCE
+#endif
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <unistd.h>
+
+#if defined(__x86_64__)
+#define GA_SYSNR(amd64, arm64, riscv64, ppc64le) (amd64)
+#elif defined(__aarch64__)
+#define GA_SYSNR(amd64, arm64, riscv64, ppc64le) (arm64)
+#elif defined(__riscv) && __riscv_xlen == 64
+#define GA_SYSNR(amd64, arm64, riscv64, ppc64le) (riscv64)
+#elif defined(__powerpc64__) && defined(__LITTLE_ENDIAN__)
+#define GA_SYSNR(amd64, arm64, riscv64, ppc64le) (ppc64le)
+#endif
+
+#if defined(__GNUC__)
+#define GA_UNUSED __attribute__((unused))
+#else
+#define GA_UNUSED
+#endif
+
+typedef struct {
+	uint64_t r0, r1;
+} ga_pair;
+
+#ifndef GA_STACK_SIZE
+#define GA_STACK_SIZE 65536
+#endif
+
+GA_UNUSED static uint64_t rax, rcx, rdx, rbx, rsp, rbp, rsi, rdi;
+GA_UNUSED static uint64_t r8, r9, r10, r11, r12, r13, r14, r15;
+GA_UNUSED static uint64_t ga_float[32];
+static uint64_t ga_stack[GA_STACK_SIZE / 8];
+
+#define GA_ENTER() \
+	do { \
+		if (rsp == 0) \
+			rsp = (uintptr_t) (ga_stack + GA_STACK_SIZE / 8); \
+	} while (0)
+
+GA_UNUSED static uint64_t ga_syscall(uint64_t nr, uint64_t a, uint64_t b, uint64_t c, uint64_t d, uint64_t e, uint64_t f)
+{
+	long r = syscall(nr, a, b, c, d, e, f);
+	if (r == -1)
+		return -(uint64_t) errno;
+	return r;
+}
+`
+
+// C is a portable reference architecture which generates C source code
+// instead of assembly.  Registers are file-scope variables named after the
+// AMD64 registers, and register allocation mirrors AMD64.
+//
+// Functions are C functions which take no arguments, so a Jump to a global
+// name is a tail call, and a Jump to a local name is a goto within the
+// current function.  Global names which are referenced but not defined are
+// declared as external functions, or as external data if they are only used
+// with Address.  MoveDef refers to a preprocessor definition.
+//
+// CallLib calls a C function with the argument registers as parameters.  The
+// function is declared under a different name using an assembler label, so
+// that the declaration cannot conflict with the system headers.
+var C = &ArchC{}
+
+type ArchC struct{}
+
+func (*ArchC) ID() ArchID {
+	return IDC
+}
+
+func (*ArchC) Machine() string {
+	return "c"
+}
+
+func (*ArchC) Specify(x Specific) int {
+	return x.Value(IDC)
+}
+
+func (*ArchC) NewArchAssembly(sys *System, out *Output) ArchAssembly {
+	if out.Syntax() != GNUSyntax {
+		panic("unsupported syntax for c")
+	}
+
+	out.buf.WriteString(headerC)
+	return &portableC{
+		System:  sys,
+		buffer:  out.buf,
+		declPos: out.buf.Len(),
+		defined: make(map[string]bool),
+		called:  make(map[string]bool),
+		libs:    make(map[string]bool),
+	}
+}
+
+type portableC struct {
+	*System
+	*buffer
+	declPos int             // Where function and symbol declarations are inserted.
+	open    bool            // Inside function body.
+	defined map[string]bool // Functions.
+	called  map[string]bool // Undefined names are functions if true, data if false.
+	order   []string        // Defined and referenced global names.
+	libs    map[string]bool // Functions called with CallLib.
+	libList []string        // Keys of libs in call order.
+}
+
+func (a *portableC) check(r Reg) {
+	a.checkUsage(r.Num(IDC), r.Use)
+}
+
+func (a *portableC) Set(r Reg) {
+	a.buffer.regUsage[r.Num(IDC)] = r.Use
+}
+
+// finish returns the complete source without modifying the buffer.
+func (a *portableC) finish(src []byte) []byte {
+	decls := new(bytes.Buffer)
+	fmt.Fprintln(decls)
+	for _, name := range a.order {
+		switch {
+		case a.defined[name] && global(name):
+			fmt.Fprintf(decls, "void %s(void);\n", cIdent(name))
+		case a.defined[name]:
+			fmt.Fprintf(decls, "static void %s(void);\n", cIdent(name))
+		case a.called[name]:
+			fmt.Fprintf(decls, "void %s(void);\n", cIdent(name))
+		default:
+			fmt.Fprintf(decls, "extern char %s[];\n", cIdent(name))
+		}
+	}
+	for _, name := range a.libList {
+		fmt.Fprintf(decls, "void %s(void) __asm__(%s);\n", libIdent(name), quoteString(name))
+	}
+
+	b := new(bytes.Buffer)
+	b.Write(src[:a.declPos])
+	b.Write(decls.Bytes())
+	b.Write(src[a.declPos:])
+	if a.open {
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+func (a *portableC) Label(name string, opts ...SymbolOptions) {
+	o := checkNoGoSymbolOptions(name, opts)
+	if global(name) {
+		if a.open {
+			a.stmt("%s();", cIdent(name)) // Fall through.
+			a.stmt("return;")
+		}
+		a.begin(name, o)
+		a.stmt("GA_ENTER();")
+	} else {
+		a.WriteString(cIdent(name) + ":;\n")
+	}
+}
+
+func (a *portableC) FunctionEpilogue() {
+}
+
+func (a *portableC) Function(name string, opts ...SymbolOptions) {
+	a.begin(name, checkNoGoSymbolOptions(name, opts))
+	if global(name) {
+		a.stmt("GA_ENTER();")
+	}
+}
+
+func (a *portableC) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.Function(name, opts...)
+}
+
+func (a *portableC) Return() {
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *portableC) ReturnWithoutEpilogue() {
+	a.stmt("return;")
+}
+
+func (a *portableC) Address(dest Reg, name string) {
+	a.reference(name, false)
+	a.stmt("%s = (uintptr_t) %s;", a.reg(dest), cIdent(name))
+	a.Set(dest)
+}
+
+func (a *portableC) MoveDef(dest Reg, name string) {
+	a.stmt("%s = %s;", a.reg(dest), cIdent(name))
+	a.Set(dest)
+}
+
+func (a *portableC) MoveImm(dest Reg, value int) {
+	a.stmt("%s = %s;", a.reg(dest), a.imm(value))
+	a.Set(dest)
+}
+
+func (a *portableC) MoveImm64(dest Reg, value uint64) {
+	a.stmt("%s = %s;", a.reg(dest), a.imm64(value))
+	a.Set(dest)
+}
+
+func (a *portableC) MoveReg(dest, src Reg) {
+	a.check(src)
+	if a.reg(dest) != a.reg(src) {
+		a.stmt("%s = %s;", a.reg(dest), a.reg(src))
+	}
+	a.Set(dest)
+}
+
+func (a *portableC) MoveRegFloat(dest Reg, src FloatReg) {
+	a.stmt("%s = ga_float[%d];", a.reg(dest), src)
+	a.Set(dest)
+}
+
+func (a *portableC) AddImm(dest, src Reg, value int) {
+	a.check(src)
+	a.stmt("%s = %s + %s;", a.reg(dest), a.reg(src), a.imm(value))
+	a.Set(dest)
+}
+
+func (a *portableC) AddReg(dest, src1, src2 Reg) {
+	a.check(src1)
+	a.check(src2)
+	a.stmt("%s = %s + %s;", a.reg(dest), a.reg(src1), a.reg(src2))
+	a.Set(dest)
+}
+
+func (a *portableC) SubtractImm(dest Reg, value int) {
+	a.check(dest)
+	if value != 0 {
+		a.stmt("%s -= %s;", a.reg(dest), a.imm(value))
+	}
+	a.Set(dest)
+}
+
+func (a *portableC) SubtractReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.stmt("%s -= %s;", a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *portableC) MultiplyImm(dest, src Reg, value int, temp Reg) {
+	a.check(src)
+	a.stmt("%s = %s * %s;", a.reg(dest), a.reg(src), a.imm(value))
+	a.Set(dest)
+	a.Set(temp.As(""))
+}
+
+func (a *portableC) AndImm(dest Reg, value int) {
+	a.check(dest)
+	a.stmt("%s &= %s;", a.reg(dest), a.imm(value))
+	a.Set(dest)
+}
+
+func (a *portableC) AndReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.stmt("%s &= %s;", a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *portableC) OrImm(dest Reg, value int) {
+	a.check(dest)
+	a.stmt("%s |= %s;", a.reg(dest), a.imm(value))
+	a.Set(dest)
+}
+
+func (a *portableC) OrReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.stmt("%s |= %s;", a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *portableC) ShiftImm(s Shift, r Reg, count int) {
+	a.check(r)
+	if count != 0 {
+		switch s {
+		case Left:
+			a.stmt("%s <<= %d;", a.reg(r), count)
+		case RightLogical:
+			a.stmt("%s >>= %d;", a.reg(r), count)
+		case RightArithmetic:
+			a.stmt("%s = (int64_t) %s >> %d;", a.reg(r), a.reg(r), count)
+		default:
+			panic(s)
+		}
+	}
+	a.Set(r)
+}
+
+func (a *portableC) Load(dest, base Reg, offset int) {
+	a.check(base)
+	a.stmt("%s = %s;", a.reg(dest), a.mem("uint64_t", base, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) Load4Bytes(dest, base Reg, offset int) {
+	a.check(base)
+	a.stmt("%s = %s;", a.reg(dest), a.mem("uint32_t", base, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) LoadByte(dest, base Reg, offset int) {
+	a.check(base)
+	a.stmt("%s = %s;", a.reg(dest), a.mem("uint8_t", base, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) Store(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.stmt("%s = %s;", a.mem("uint64_t", base, offset), a.reg(src))
+}
+
+func (a *portableC) Store4Bytes(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.stmt("%s = %s;", a.mem("uint32_t", base, offset), a.reg(src))
+}
+
+func (a *portableC) LoadGlobal(dest Reg, name string, offset int) {
+	a.stmt("%s = %s;", a.reg(dest), a.global("uint64_t", name, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.stmt("%s = %s;", a.reg(dest), a.global("uint32_t", name, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.stmt("%s = %s;", a.reg(dest), a.global("uint8_t", name, offset))
+	a.Set(dest)
+}
+
+func (a *portableC) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.stmt("%s = %s;", a.global("uint64_t", name, offset), a.reg(src))
+	a.Set(temp.As(""))
+}
+
+func (a *portableC) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.stmt("%s = %s;", a.global("uint32_t", name, offset), a.reg(src))
+	a.Set(temp.As(""))
+}
+
+func (a *portableC) LoadThreadPointer(dest Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *portableC) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *portableC) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *portableC) Push(r Reg) {
+	a.check(r)
+	a.stmt("%s -= 8;", a.reg(a.StackPtr))
+	a.stmt("%s = %s;", a.mem("uint64_t", a.StackPtr, 0), a.reg(r))
+}
+
+func (a *portableC) Pop(r Reg) {
+	a.stmt("%s = %s;", a.reg(r), a.mem("uint64_t", a.StackPtr, 0))
+	a.stmt("%s += 8;", a.reg(a.StackPtr))
+	a.Set(r)
+}
+
+func (a *portableC) Jump(name string) {
+	a.stmt("%s", a.jump(name))
+}
+
+// JumpRegRoutine calls the function pointer and returns.  The internal names
+// are not used.
+func (a *portableC) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	a.check(r)
+	a.stmt("((void (*)(void)) (uintptr_t) %s)();", a.reg(r))
+	a.stmt("return;")
+}
+
+func (a *portableC) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.stmt("if (%s & (UINT64_C(1) << %d)) %s", a.reg(r), bit, a.jump(name))
+}
+
+func (a *portableC) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.stmt("if (!(%s & (UINT64_C(1) << %d))) %s", a.reg(r), bit, a.jump(name))
+}
+
+func (a *portableC) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.check(r)
+	a.stmt("if ((int64_t) %s %s %s) %s", a.reg(r), a.cond(c), a.imm(value), a.jump(name))
+}
+
+func (a *portableC) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.check(dest)
+	a.check(src)
+	a.stmt("if ((int64_t) %s %s (int64_t) %s) %s", a.reg(dest), a.cond(c), a.reg(src), a.jump(name))
+}
+
+func (a *portableC) Call(name string) {
+	a.refer(name)
+	a.reference(name, true)
+	a.stmt("%s();", cIdent(name))
+}
+
+func (a *portableC) callLib(name string, args, results []Reg) {
+	if !a.libs[name] {
+		a.libs[name] = true
+		a.libList = append(a.libList, name)
+	}
+
+	params := make([]string, len(args))
+	values := make([]string, len(args))
+	for i, r := range args {
+		a.check(r)
+		params[i] = "uint64_t"
+		values[i] = a.reg(r)
+	}
+	if len(args) == 0 {
+		params = []string{"void"}
+	}
+
+	result := "uint64_t"
+	if len(results) == 2 {
+		result = "ga_pair"
+	}
+	call := fmt.Sprintf("((%s (*)(%s)) ga_fn)(%s)", result, strings.Join(params, ", "), strings.Join(values, ", "))
+
+	var body string
+	switch len(results) {
+	case 0:
+		body = call + ";"
+	case 1:
+		body = fmt.Sprintf("%s = %s;", a.reg(results[0]), call)
+	case 2:
+		body = fmt.Sprintf("ga_pair ga_r = %s; %s = ga_r.r0; %s = ga_r.r1;", call, a.reg(results[0]), a.reg(results[1]))
+	}
+
+	// Calling through a variable avoids a compiler warning about the
+	// incompatible function type.
+	a.stmt("{ uintptr_t ga_fn = (uintptr_t) %s; %s }", libIdent(name), body)
+
+	for _, r := range results {
+		a.Set(r)
+	}
+}
+
+// Syscall numbers which don't exist on the build platform are -1, so the
+// syscall fails with ENOSYS.
+func (a *portableC) Syscall(nr Syscall) {
+	a.stmt("%s = GA_SYSNR(%d, %d, %d, %d);", a.reg(a.SyscallNr), nr.AMD64, nr.ARM64, nr.RISCV64, nr.PPC64LE)
+
+	args := make([]string, len(a.SysParams))
+	for i, r := range a.SysParams {
+		args[i] = a.reg(r)
+	}
+	a.stmt("%s = ga_syscall(%s, %s);", a.reg(a.SysResult), a.reg(a.SyscallNr), strings.Join(args, ", "))
+	a.Set(a.SysResult)
+}
+
+func (a *portableC) Unreachable() {
+	a.stmt("abort();")
+}
+
+func (a *portableC) begin(name string, o SymbolOptions) {
+	if a.open {
+		a.WriteString("}\n")
+	}
+
+	a.reference(name, true)
+	a.defined[name] = true
+
+	if global(name) {
+		a.WriteString("\n")
+		if o.Weak {
+			a.WriteString("__attribute__((weak))\n")
+		}
+		switch o.Visibility {
+		case DefaultVisibility:
+		case HiddenVisibility:
+			a.WriteString("__attribute__((visibility(\"hidden\")))\n")
+		case ProtectedVisibility:
+			a.WriteString("__attribute__((visibility(\"protected\")))\n")
+		default:
+			panic(o.Visibility)
+		}
+		fmt.Fprintf(a, "void %s(void)\n{\n", cIdent(name))
+	} else {
+		fmt.Fprintf(a, "\nstatic void %s(void)\n{\n", cIdent(name))
+	}
+	a.open = true
+}
+
+func (a *portableC) reference(name string, call bool) {
+	if _, found := a.called[name]; !found {
+		a.order = append(a.order, name)
+	}
+	a.called[name] = a.called[name] || call
+}
+
+// jump statement: tail call to global name or goto local label.
+func (a *portableC) jump(name string) string {
+	if global(name) {
+		a.reference(name, true)
+		return fmt.Sprintf("{ %s(); return; }", cIdent(name))
+	}
+	return fmt.Sprintf("goto %s;", cIdent(name))
+}
+
+func (a *portableC) stmt(format string, args ...interface{}) {
+	end := "\n"
+	if loc, found := a.sourceLocation(); found {
+		switch a.sourceInfo {
+		case SourceLines:
+			// Every statement needs a directive because line numbers advance.
+			fmt.Fprintf(a, "#line %d %s\n", loc.line, quoteString(loc.file))
+		case SourceComments:
+			end = "\t" + loc.comment(a.comment) + "\n"
+		}
+	}
+	fmt.Fprintf(a, "\t"+format, args...)
+	a.WriteString(end)
+}
+
+func (a *portableC) imm(x int) string {
+	if x >= -0x80000000 && x <= 0x7fffffff {
+		return fmt.Sprintf("%d", x)
+	}
+	return fmt.Sprintf("INT64_C(%d)", x)
+}
+
+func (a *portableC) imm64(x uint64) string {
+	if x <= 0x7fffffff {
+		return fmt.Sprintf("%d", x)
+	}
+	return fmt.Sprintf("UINT64_C(0x%x)", x)
+}
+
+func (a *portableC) mem(t string, base Reg, offset int) string {
+	switch {
+	case offset == 0:
+		return fmt.Sprintf("*(%s *) (uintptr_t) %s", t, a.reg(base))
+	case offset > 0:
+		return fmt.Sprintf("*(%s *) (uintptr_t) (%s + %d)", t, a.reg(base), offset)
+	default:
+		return fmt.Sprintf("*(%s *) (uintptr_t) (%s - %d)", t, a.reg(base), -offset)
+	}
+}
+
+func (a *portableC) global(t, name string, offset int) string {
+	a.reference(name, false)
+	return fmt.Sprintf("*(%s *) ((uintptr_t) %s + %d)", t, cIdent(name), offset)
+}
+
+func (a *portableC) reg(x Reg) string {
+	return RegAMD64(x.Num(IDC)).reg()
+}
+
+func (a *portableC) cond(x Cond) string {
+	switch x {
+	case EQ:
+		return "=="
+	case NE:
+		return "!="
+	case LT:
+		return "<"
+	case LE:
+		return "<="
+	case GT:
+		return ">"
+	case GE:
+		return ">="
+	}
+
+	panic(x)
+}
+
+// libIdent is the C identifier of a function called with CallLib.
+func libIdent(name string) string {
+	return "ga_lib_" + cIdent(name)
+}
+
+// cIdent converts a symbol name to a C identifier.  Local names get the "L"
+// prefix, like in assembly.
+func cIdent(name string) string {
+	if !global(name) {
+		name = "L" + name
+	}
+
+	b := []byte(name)
+	for i, c := range b {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/linux"
+)
+
+// buildC compiles the source with cc.  The test is skipped if cc is not
+// installed.
+func buildC(t *testing.T, source string, flags ...string) string {
+	t.Helper()
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "x.c")
+	out := filepath.Join(dir, "x")
+
+	if err := os.WriteFile(src, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	args := append([]string{"-Wall", "-Werror", "-o", out}, flags...)
+	if output, err := exec.Command(cc, append(args, src)...).CombinedOutput(); err != nil {
+		t.Fatalf("cc: %v\n%s\n%s", err, output, source)
+	}
+	return out
+}
+
+func TestC(t *testing.T) {
+	a := ga.NewAssembly(ga.C, sys)
+	everything(a)
+	buildC(t, "#define def 42\n"+a.String(), "-c")
+}
+
+func TestCRun(t *testing.T) {
+	a := ga.NewAssembly(ga.C, sys)
+	a.Function("compute")
+	a.MoveReg(r0, sys.StackPtr)
+	a.SubtractImm(r0, 64)
+	a.MoveImm(r1, 1000)
+	a.MultiplyImm(r1, r1, 3, temp)
+	a.Store(r0, 8, r1)
+	a.Call(".helper")
+	a.Load(r2, r0, 8)
+	a.SubtractReg(r2, r1) // 3000 - 3001
+	a.JumpIfImm(ga.LT, r2, 0, ".negative")
+	a.MoveImm(sys.SysParams[0], 1)
+	a.Jump(".exit")
+	a.Label(".negative")
+	a.MoveImm(r1, 0x1234)
+	a.AndImm(r1, 0xff)
+	a.MoveReg(sys.SysParams[0], r1) // 0x34
+	a.Label(".exit")
+	a.Syscall(linux.SYS_EXIT_GROUP)
+	a.Unreachable()
+
+	a.Function(".helper")
+	a.AddImm(r1, r1, 1)
+	a.Return()
+
+	prog := buildC(t, a.String()+"\nint main(void) { compute(); return 0; }\n")
+
+	err := exec.Command(prog).Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 0x34 {
+		t.Errorf("exit: %v", err)
+	}
+}
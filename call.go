@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+// CallLib calls a C library function.  Arguments are moved to LibParams
+// registers, and the rest are passed on the stack.  Registers which are in
+// use and destroyed by the call are preserved, except those which receive
+// results.  Up to two results are moved from LibResult and LibResult2.
+//
+// The stack pointer is aligned to 16 bytes during the call.  The original
+// stack pointer is kept in FramePtr, so it must be callee-saved, and it cannot
+// be used as an argument or a result.  With WithFramePointer on ARM64, the
+// link register is saved with FramePtr to form a frame record.  Variadic
+// functions may be called: on AMD64 the number of vector arguments is set to
+// zero.
+//
+// C calls the function directly with the arguments, and no registers are
+// destroyed.
+func (a *Assembly) CallLib(name string, args, results []Reg) {
+	id := a.Arch.ID()
+	if len(results) > 2 {
+		panic("CallLib supports at most two results")
+	}
+
+	fp := a.FramePtr.Num(id)
+	if a.LibClobbers.Mask(id)&(1<<fp) != 0 {
+		panic("frame pointer is not callee-saved")
+	}
+	for _, r := range append(args[:len(args):len(args)], results...) {
+		if n := r.Num(id); n == fp || n == a.StackPtr.Num(id) {
+			panic("frame or stack pointer cannot be passed to CallLib")
+		}
+	}
+
+	if c, ok := a.ArchAssembly.(libCaller); ok {
+		c.callLib(name, args, results)
+		return
+	}
+
+	var resultMask uint32
+	for _, r := range results {
+		resultMask |= 1 << r.Num(id)
+	}
+
+	var saved []Reg
+	clobbers := a.LibClobbers.Mask(id) &^ resultMask
+	for n, use := range a.regUsage {
+		if use != "" && clobbers&(1<<uint(n)) != 0 {
+			r := regNum(id, uint8(n), use)
+			a.Push(r)
+			saved = append(saved, r)
+		}
+	}
+
+	fpUse := a.regUsage[fp]
+	a.Set(a.FramePtr)
+
+	// ARM64 frame record consists of frame pointer and link register.
+	var lr Reg
+	record := a.framePtr && id == IDARM64
+	if record {
+		lr = Reg{ARM64: XLR, Use: a.regUsage[XLR]}
+		a.Set(lr.As("link"))
+		a.PushPair(lr.As("link"), a.FramePtr)
+	} else {
+		a.Push(a.FramePtr)
+	}
+	a.MoveReg(a.FramePtr, a.StackPtr)
+	prevCFI := a.cfiDefFramePtr()
+
+	if a.pushSize() == 8 {
+		// Only 8-byte stack slots can misalign the stack pointer.
+		aligned := a.internalLabel("aligned")
+		a.JumpIfBitNotSet(a.FramePtr, 3, aligned)
+		a.SubtractImm(a.StackPtr, 8)
+		a.Label(aligned)
+	}
+
+	numRegs := a.libArgRegs(id)
+	if numRegs > len(args) {
+		numRegs = len(args)
+	}
+
+	// Offset of first stack argument and size of the outgoing argument area.
+	offset := 0
+	size := 8 * (len(args) - numRegs)
+	if id == IDPPC64LE {
+		// ELFv2 frame header and parameter save area.
+		offset = 32 + 8*numRegs
+		size = 32 + 8*len(args)
+		if len(args) < 8 {
+			size = 32 + 8*8
+		}
+	}
+	a.SubtractImm(a.StackPtr, (size+15)&^15)
+
+	for i, r := range args[numRegs:] {
+		a.Store(a.StackPtr, offset+8*i, r)
+	}
+	a.moveRegs(a.LibParams[:numRegs], args[:numRegs])
+
+	if id == IDAMD64 {
+		a.MoveImm(Reg{AMD64: RAX, Use: "vectorargs"}, 0)
+	}
+
+	a.Call(name)
+
+	srcs := []Reg{a.LibResult, a.LibResult2}[:len(results)]
+	for _, r := range srcs {
+		a.Set(r)
+	}
+	a.moveRegs(results, srcs)
+	for _, r := range srcs {
+		if resultMask&(1<<r.Num(id)) == 0 {
+			a.Set(r.As(""))
+		}
+	}
+
+	a.MoveReg(a.StackPtr, a.FramePtr)
+	a.cfiDefStackPtr()
+	if record {
+		a.PopPair(lr, a.FramePtr.As(fpUse))
+	} else {
+		a.Pop(a.FramePtr.As(fpUse))
+	}
+	a.cfiResetFrame(prevCFI)
+
+	for i := len(saved) - 1; i >= 0; i-- {
+		a.Pop(saved[i])
+	}
+}
+
+// libCaller is implemented by backends which don't follow the native calling
+// convention.
+type libCaller interface {
+	callLib(name string, args, results []Reg)
+}
+
+// moveRegs copies source registers to destination registers as if all moves
+// were done simultaneously.  Cycles are broken via the stack.
+func (a *Assembly) moveRegs(dests, srcs []Reg) {
+	id := a.Arch.ID()
+
+	type move struct {
+		dest Reg
+		src  Reg
+	}
+
+	var pending, retained []move
+	for i, dest := range dests {
+		if dest.Num(id) == srcs[i].Num(id) {
+			retained = append(retained, move{dest, srcs[i]})
+		} else {
+			pending = append(pending, move{dest, srcs[i]})
+		}
+	}
+
+	isSource := func(n uint8) bool {
+		for _, m := range pending {
+			if m.src.Num(id) == n {
+				return true
+			}
+		}
+		return false
+	}
+
+	var stacked []Reg
+
+	for len(pending) > 0 {
+		progress := false
+
+		for i := 0; i < len(pending); i++ {
+			if m := pending[i]; !isSource(m.dest.Num(id)) {
+				a.MoveReg(m.dest, m.src)
+				pending = append(pending[:i], pending[i+1:]...)
+				i--
+				progress = true
+			}
+		}
+
+		if !progress {
+			a.Push(pending[0].src)
+			stacked = append(stacked, pending[0].dest)
+			pending = pending[1:]
+		}
+	}
+
+	for i := len(stacked) - 1; i >= 0; i-- {
+		a.Pop(stacked[i])
+	}
+
+	for _, m := range retained {
+		a.MoveReg(m.dest, m.src) // Only usage changes.
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/linux"
+)
+
+// callLib generates a function which calls sum9 and exits with the sum of
+// the results and a live register which is destroyed by the call.  Some of
+// the arguments are in parameter registers in the wrong order.
+func callLib(a *ga.Assembly) {
+	live := sys.LibParams[5].As("live")
+
+	a.Function("compute")
+	a.MoveImm(live, 3)
+	args := []ga.Reg{r0, r1, r2, temp}
+	for i := 0; i < 5; i++ {
+		args = append(args, sys.LibParams[i].As(fmt.Sprintf("arg%d", len(args))))
+	}
+	for i, r := range args {
+		a.MoveImm(r, i+1)
+	}
+	a.CallLib("sum9", args, []ga.Reg{r0, r1})
+	a.AddReg(r0, r0, r1)
+	a.AddReg(r0, r0, live)
+	a.MoveReg(sys.SysParams[0], r0)
+	a.Syscall(linux.SYS_EXIT_GROUP)
+	a.Unreachable()
+}
+
+// sum9 returns the number of arguments which match their position, and 100.
+const sum9 = `
+typedef struct { long sum, other; } pair;
+pair sum9(long a, long b, long c, long d, long e, long f, long g, long h, long i) {
+	return (pair) {(a == 1) + (b == 2) + (c == 3) + (d == 4) + (e == 5) + (f == 6) + (g == 7) + (h == 8) + (i == 9), 100};
+}
+`
+
+func TestCallLib(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			callLib(a)
+			assembleCheck(t, arch, a.String())
+		})
+	}
+}
+
+func TestCallLibRun(t *testing.T) {
+	t.Run("c", func(t *testing.T) {
+		a := ga.NewAssembly(ga.C, sys)
+		callLib(a)
+		prog := buildC(t, sum9+a.String()+"\nint main(void) { compute(); return 0; }\n")
+		checkExit(t, prog, 112)
+	})
+
+	t.Run("amd64", func(t *testing.T) {
+		if runtime.GOARCH != "amd64" || runtime.GOOS != "linux" {
+			t.Skip("not linux/amd64")
+		}
+
+		a := ga.NewAssembly(ga.AMD64, sys)
+		callLib(a)
+		asm := filepath.Join(t.TempDir(), "compute.s")
+		if err := os.WriteFile(asm, []byte(a.String()), 0666); err != nil {
+			t.Fatal(err)
+		}
+		prog := buildC(t, sum9+"void compute(void);\nint main(void) { compute(); return 0; }\n", asm)
+		checkExit(t, prog, 112)
+	})
+}
+
+func checkExit(t *testing.T, prog string, code int) {
+	t.Helper()
+
+	err := exec.Command(prog).Run()
+	if code == 0 {
+		if err != nil {
+			t.Errorf("exit: %v", err)
+		}
+		return
+	}
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != code {
+		t.Errorf("exit: %v", err)
+	}
+}
+
+func TestCallLibTooManyResults(t *testing.T) {
+	defer func() {
+		if s := fmt.Sprint(recover()); s != "CallLib supports at most two results" {
+			t.Error(s)
+		}
+	}()
+
+	a := ga.NewAssembly(ga.AMD64, sys)
+	a.CallLib("f", nil, []ga.Reg{r0, r1, r2})
+}
+
+func TestCallLibFramePtr(t *testing.T) {
+	defer func() {
+		if s := fmt.Sprint(recover()); s != "frame or stack pointer cannot be passed to CallLib" {
+			t.Error(s)
+		}
+	}()
+
+	a := ga.NewAssembly(ga.ARM64, sys)
+	a.CallLib("f", []ga.Reg{sys.FramePtr}, nil)
+}
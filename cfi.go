@@ -0,0 +1,243 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+	"sort"
+)
+
+// cfiState tracks DWARF call frame information within a procedure.
+type cfiState struct {
+	open       bool
+	offset     int            // Distance from stack pointer to CFA.
+	frame      cfiFrame       // CFA definition, unless it's relative to stack pointer.
+	saved      map[string]int // Distance from save location to CFA by register.
+	remembered *cfiState
+}
+
+func (s cfiState) clone() cfiState {
+	saved := make(map[string]int, len(s.saved))
+	for reg, off := range s.saved {
+		saved[reg] = off
+	}
+	s.saved = saved
+	s.remembered = nil
+	return s
+}
+
+// cfiFrame defines CFA relative to a register other than stack pointer.
+type cfiFrame struct {
+	reg    string // Empty means stack pointer.
+	offset int
+}
+
+// cfiStartProc starts a procedure.  Offset is the distance from stack pointer
+// to CFA at the entry point.
+func (b *buffer) cfiStartProc(offset int) {
+	if !b.callFrameInfo {
+		return
+	}
+	b.printf(".cfi_startproc")
+	b.cfi = cfiState{
+		open:   true,
+		offset: offset,
+	}
+}
+
+// cfiAdjust records a stack pointer decrement (or increment if negative).
+// Backends call it right after the instruction which updates the stack
+// pointer.
+func (b *buffer) cfiAdjust(delta int) {
+	if !b.cfi.open || delta == 0 {
+		return
+	}
+	b.cfi.offset += delta
+	if b.cfi.frame.reg == "" {
+		b.printf(".cfi_adjust_cfa_offset %d", delta)
+	}
+}
+
+// cfiSave records that a register was saved at stack pointer plus spOffset.
+// Only the first save location of a register is recorded.
+func (b *buffer) cfiSave(reg string, spOffset int) {
+	if !b.cfi.open {
+		return
+	}
+	if _, found := b.cfi.saved[reg]; found {
+		return
+	}
+	if b.cfi.saved == nil {
+		b.cfi.saved = make(map[string]int)
+	}
+	b.cfi.saved[reg] = b.cfi.offset - spOffset
+	b.printf(".cfi_offset %s, %d", reg, spOffset-b.cfi.offset)
+}
+
+// cfiRestore records that a register was restored from stack pointer plus
+// spOffset.  It has no effect unless the location matches the recorded one.
+func (b *buffer) cfiRestore(reg string, spOffset int) {
+	if !b.cfi.open {
+		return
+	}
+	if off, found := b.cfi.saved[reg]; found && off == b.cfi.offset-spOffset {
+		delete(b.cfi.saved, reg)
+		b.printf(".cfi_restore %s", reg)
+	}
+}
+
+// cfiDefFrame defines CFA relative to a register whose value is stack pointer
+// plus spOffset.  The previous definition is returned.
+func (b *buffer) cfiDefFrame(reg string, spOffset int) (prev cfiFrame) {
+	if !b.cfi.open {
+		return
+	}
+	prev = b.cfi.frame
+	b.cfi.frame = cfiFrame{reg, b.cfi.offset - spOffset}
+	b.printf(".cfi_def_cfa %s, %d", reg, b.cfi.frame.offset)
+	return
+}
+
+// cfiDefStack defines CFA relative to stack pointer.
+func (b *buffer) cfiDefStack(sp string) {
+	if !b.cfi.open {
+		return
+	}
+	b.cfi.frame = cfiFrame{}
+	b.printf(".cfi_def_cfa %s, %d", sp, b.cfi.offset)
+}
+
+// cfiResetFrame restores a CFA definition returned by cfiDefFrame.  Current
+// definition must be relative to stack pointer.
+func (b *buffer) cfiResetFrame(f cfiFrame) {
+	if !b.cfi.open || f.reg == "" {
+		return
+	}
+	b.cfi.frame = f
+	b.printf(".cfi_def_cfa %s, %d", f.reg, f.offset)
+}
+
+// cfiRememberState before an epilogue.
+func (b *buffer) cfiRememberState() {
+	if !b.cfi.open {
+		return
+	}
+	s := b.cfi.clone()
+	b.cfi.remembered = &s
+	b.printf(".cfi_remember_state")
+}
+
+// cfiRestoreState after return or jump which follows an epilogue.
+func (b *buffer) cfiRestoreState() {
+	if b.cfi.remembered == nil {
+		return
+	}
+	b.cfi = *b.cfi.remembered
+	b.printf(".cfi_restore_state")
+}
+
+// regNamer is implemented by backends which support call frame information.
+type regNamer interface {
+	reg(Reg) string
+}
+
+func (a *Assembly) regName(r Reg) string {
+	if n, ok := a.ArchAssembly.(regNamer); ok {
+		return n.reg(r)
+	}
+	panic(fmt.Sprintf("call frame information is not supported with %T", a.ArchAssembly))
+}
+
+// cfiPush records a register push to stack pointer plus spOffset.
+// Callee-saved registers are recorded as saved.
+func (a *Assembly) cfiPush(r Reg, spOffset int) {
+	if a.cfi.open && a.CalleeSaved.Mask(a.Arch.ID())&(1<<r.Num(a.Arch.ID())) != 0 {
+		a.cfiSave(a.regName(r), spOffset)
+	}
+}
+
+// cfiPop records a register pop from stack pointer plus spOffset.
+func (a *Assembly) cfiPop(r Reg, spOffset int) {
+	if a.cfi.open {
+		a.cfiRestore(a.regName(r), spOffset)
+	}
+}
+
+// cfiDefFramePtr defines CFA relative to frame pointer, which must be equal
+// to stack pointer.
+func (a *Assembly) cfiDefFramePtr() cfiFrame {
+	if !a.cfi.open {
+		return cfiFrame{}
+	}
+	return a.cfiDefFrame(a.regName(a.FramePtr), 0)
+}
+
+// cfiDefStackPtr defines CFA relative to stack pointer.
+func (a *Assembly) cfiDefStackPtr() {
+	if a.cfi.open {
+		a.cfiDefStack(a.regName(a.StackPtr))
+	}
+}
+
+// cfiStackPtrUpdate records that dest was set to src plus offset, if dest is
+// the stack pointer.  The update must be relative to the stack pointer itself
+// or to the register which defines CFA.
+func (a *Assembly) cfiStackPtrUpdate(dest, src Reg, offset int) {
+	if !a.cfi.open {
+		return
+	}
+	id := a.Arch.ID()
+	if dest.Num(id) != a.StackPtr.Num(id) {
+		return
+	}
+
+	switch {
+	case src.Num(id) == a.StackPtr.Num(id):
+		a.cfiAdjust(-offset)
+	case a.regName(src) == a.cfi.frame.reg:
+		a.cfi.offset = a.cfi.frame.offset - offset
+	default:
+		panic("stack pointer update cannot be described by call frame information")
+	}
+}
+
+// cfiSetState switches to a state which was recorded at a jump site.
+func (a *Assembly) cfiSetState(s cfiState) {
+	if !a.cfi.open {
+		return
+	}
+
+	if s.frame != a.cfi.frame || (s.frame.reg == "" && s.offset != a.cfi.offset) {
+		if s.frame.reg == "" {
+			a.printf(".cfi_def_cfa %s, %d", a.regName(a.StackPtr), s.offset)
+		} else {
+			a.printf(".cfi_def_cfa %s, %d", s.frame.reg, s.frame.offset)
+		}
+	}
+
+	for _, reg := range sortedKeys(a.cfi.saved) {
+		if off, found := s.saved[reg]; !found || off != a.cfi.saved[reg] {
+			a.printf(".cfi_restore %s", reg)
+		}
+	}
+	for _, reg := range sortedKeys(s.saved) {
+		if off, found := a.cfi.saved[reg]; !found || off != s.saved[reg] {
+			a.printf(".cfi_offset %s, %d", reg, -s.saved[reg])
+		}
+	}
+
+	remembered := a.cfi.remembered
+	a.cfi = s.clone()
+	a.cfi.remembered = remembered
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
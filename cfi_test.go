@@ -0,0 +1,301 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func callFrameInfo(a *ga.Assembly) {
+	a.Function("f")
+	a.MoveImm(r0, 1)
+	a.Push(r0)
+	f := new(ga.Frame)
+	f.Local("x", 8, 8)
+	a.AllocateFrame(f)
+	a.FreeFrame(f)
+	a.Pop(r0)
+	a.CallLib("g", []ga.Reg{r0}, nil)
+	a.Return()
+}
+
+var cfiRow = regexp.MustCompile(`(?m)^  0x[0-9a-f]+: (CFA=.*)$`)
+
+// cfiRows returns the unwind table rows without addresses.  The test is
+// skipped if llvm-mc or llvm-dwarfdump is not installed.
+func cfiRows(t *testing.T, arch ga.Arch, source string) []string {
+	t.Helper()
+
+	mc, err := exec.LookPath("llvm-mc")
+	if err != nil {
+		t.Skip(err)
+	}
+	dump, err := exec.LookPath("llvm-dwarfdump")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	target := mcTargets[arch.Machine()]
+	if target.unquote {
+		source = quotedSymbol.ReplaceAllString(source, "$1")
+	}
+
+	obj := filepath.Join(t.TempDir(), "x.o")
+	cmd := exec.Command(mc, append(target.options, "-filetype=obj", "-o", obj)...)
+	cmd.Stdin = strings.NewReader(source)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("llvm-mc: %v\n%s\n%s", err, out, source)
+	}
+
+	out, err := exec.Command(dump, "--eh-frame", obj).Output()
+	if err != nil {
+		t.Fatalf("llvm-dwarfdump: %v", err)
+	}
+
+	var rows []string
+	for _, m := range cfiRow.FindAllStringSubmatch(string(out), -1) {
+		rows = append(rows, m[1])
+	}
+	return rows
+}
+
+func TestCallFrameInfo(t *testing.T) {
+	for _, test := range []struct {
+		arch     ga.Arch
+		framePtr bool
+		rows     []string
+	}{
+		{ga.AMD64, false, []string{
+			"CFA=RSP+8: RIP=[CFA-8]",
+			"CFA=RSP+16: RBX=[CFA-16], RIP=[CFA-8]", // push rbx
+			"CFA=RSP+32: RBX=[CFA-16], RIP=[CFA-8]", // sub rsp, 16
+			"CFA=RSP+16: RBX=[CFA-16], RIP=[CFA-8]", // add rsp, 16
+			"CFA=RSP+8: RIP=[CFA-8]",                // pop rbx
+			"CFA=RSP+16: RBP=[CFA-16], RIP=[CFA-8]", // push rbp
+			"CFA=RBP+16: RBP=[CFA-16], RIP=[CFA-8]", // mov rbp, rsp
+			"CFA=RSP+16: RBP=[CFA-16], RIP=[CFA-8]", // mov rsp, rbp
+			"CFA=RSP+8: RIP=[CFA-8]",                // pop rbp
+		}},
+		{ga.ARM64, true, []string{
+			"CFA=WSP",
+			"CFA=WSP+16: W29=[CFA-16], W30=[CFA-8]",               // stp x29, lr
+			"CFA=W29+16: W29=[CFA-16], W30=[CFA-8]",               // mov x29, sp
+			"CFA=W29+16: W19=[CFA-32], W29=[CFA-16], W30=[CFA-8]", // str x19
+			"CFA=W29+16: W29=[CFA-16], W30=[CFA-8]",               // ldr x19
+			"CFA=W29+32: W29=[CFA-16], W30=[CFA-8]",               // mov x29, sp
+			"CFA=WSP+32: W29=[CFA-16], W30=[CFA-8]",               // mov sp, x29
+			"CFA=W29+16: W29=[CFA-16], W30=[CFA-8]",               // ldp x29, x30
+			"CFA=WSP",                                             // ldp x29, lr
+			"CFA=WSP: W29=[CFA-16], W30=[CFA-8]",                  // After return.
+		}},
+		{ga.RISCV64, true, []string{
+			"CFA=X2",
+			"CFA=X2+16",                                    // addi sp, sp, -16
+			"CFA=X2+16: X1=[CFA-8]",                        // sd ra
+			"CFA=X2+16: X1=[CFA-8], X8=[CFA-16]",           // sd s0
+			"CFA=X8: X1=[CFA-8], X8=[CFA-16]",              // addi s0, sp, 16
+			"CFA=X8: X1=[CFA-8], X8=[CFA-16], X9=[CFA-32]", // sd s1
+			"CFA=X8: X1=[CFA-8], X8=[CFA-16]",              // ld s1
+			"CFA=X8+32: X1=[CFA-8], X8=[CFA-16]",           // mv s0, sp
+			"CFA=X2+32: X1=[CFA-8], X8=[CFA-16]",           // mv sp, s0
+			"CFA=X2+16: X1=[CFA-8], X8=[CFA-16]",           // addi sp, sp, 16
+			"CFA=X2+16: X1=[CFA-8]",                        // ld s0
+			"CFA=X2+16",                                    // ld ra
+			"CFA=X2",                                       // addi sp, sp, 16
+			"CFA=X2: X1=[CFA-8], X8=[CFA-16]",              // After return.
+		}},
+		{ga.PPC64LE, false, []string{
+			"CFA=X1",
+			"CFA=X1: LR8=[CFA+16]",                   // std r0, 16(r1)
+			"CFA=X1+32: LR8=[CFA+16]",                // stdu r1
+			"CFA=X1+64: X14=[CFA-64], LR8=[CFA+16]",  // stdu r14
+			"CFA=X1+80: X14=[CFA-64], LR8=[CFA+16]",  // addi r1, r1, -16
+			"CFA=X1+64: X14=[CFA-64], LR8=[CFA+16]",  // addi r1, r1, 16
+			"CFA=X1+32: LR8=[CFA+16]",                // addi r1, r1, 32
+			"CFA=X1+64: X31=[CFA-64], LR8=[CFA+16]",  // stdu r31
+			"CFA=X31+64: X31=[CFA-64], LR8=[CFA+16]", // mr r31, r1
+			"CFA=X1+64: X31=[CFA-64], LR8=[CFA+16]",  // mr r1, r31
+			"CFA=X1+32: LR8=[CFA+16]",                // addi r1, r1, 32
+			"CFA=X1: LR8=[CFA+16]",                   // addi r1, r1, 32
+			"CFA=X1",                                 // mtlr r0
+			"CFA=X1: LR8=[CFA+16]",                   // After return.
+		}},
+	} {
+		t.Run(test.arch.Machine(), func(t *testing.T) {
+			opts := []ga.Option{ga.WithCallFrameInfo()}
+			if test.framePtr {
+				opts = append(opts, ga.WithFramePointer())
+			}
+			a := ga.NewAssembly(test.arch, sys, opts...)
+			callFrameInfo(a)
+			s := a.String()
+
+			rows := cfiRows(t, test.arch, s)
+			if strings.Join(rows, "\n") != strings.Join(test.rows, "\n") {
+				t.Errorf("rows:\n%s\nwant:\n%s\n%s", strings.Join(rows, "\n"), strings.Join(test.rows, "\n"), s)
+			}
+		})
+	}
+}
+
+func TestCallFrameInfoAssemble(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys, ga.WithCallFrameInfo())
+			everything(a)
+			assembleCheck(t, arch, ".set def, 42\n"+a.String())
+		})
+
+		if arch != ga.PPC64LE {
+			t.Run(name+"/FramePtr", func(t *testing.T) {
+				a := ga.NewAssembly(arch, sys, ga.WithCallFrameInfo(), ga.WithFramePointer())
+				everything(a)
+				assembleCheck(t, arch, ".set def, 42\n"+a.String())
+			})
+		}
+	}
+}
+
+// TestCallFrameInfoStackPtrUpdate checks that call frame information follows
+// the instruction which updates the stack pointer.
+func TestCallFrameInfoStackPtrUpdate(t *testing.T) {
+	for _, test := range []struct {
+		arch ga.Arch
+		want []string
+	}{
+		{ga.AMD64, []string{
+			"\tpush\trbx\n.cfi_adjust_cfa_offset\t8\n.cfi_offset\trbx, -16\n",
+			"\tsub\trsp, 32\n.cfi_adjust_cfa_offset\t32\n",
+		}},
+		{ga.ARM64, []string{
+			"\tstp\tx20, x19, [sp, -16]!\n.cfi_adjust_cfa_offset\t16\n.cfi_offset\tx20, -32\n.cfi_offset\tx19, -24\n",
+			"\tsub\tsp, sp, 32\n.cfi_adjust_cfa_offset\t32\n",
+		}},
+		{ga.RISCV64, []string{
+			"\taddi\tsp, sp, -16\n.cfi_adjust_cfa_offset\t16\n\tsd\ts1, 0(sp)\n.cfi_offset\ts1, -32\n",
+			"\taddi\tsp, sp, -16\n.cfi_adjust_cfa_offset\t16\n\tsd\ts2, 0(sp)\n.cfi_offset\ts2, -48\n",
+		}},
+	} {
+		t.Run(test.arch.Machine(), func(t *testing.T) {
+			a := ga.NewAssembly(test.arch, sys, ga.WithCallFrameInfo())
+			a.Function("f")
+			a.Set(r0)
+			a.Set(r1)
+			a.PushPair(r0, r1)
+			a.SubtractImm(sys.StackPtr, 32)
+			a.AddImm(sys.StackPtr, sys.StackPtr, 32)
+			a.PopPair(r0, r1)
+			a.Return()
+
+			s := a.String()
+			for _, x := range test.want {
+				if !strings.Contains(s, x) {
+					t.Errorf("%q not found in:\n%s", x, s)
+				}
+			}
+			assembleCheck(t, test.arch, s)
+		})
+	}
+}
+
+// TestCallFrameInfoUnwind unwinds through a generated function using libgcc.
+func TestCallFrameInfoUnwind(t *testing.T) {
+	if runtime.GOARCH != "amd64" || runtime.GOOS != "linux" {
+		t.Skip("not linux/amd64")
+	}
+
+	const main = `
+#define _GNU_SOURCE
+#include <dlfcn.h>
+#include <string.h>
+#include <unwind.h>
+
+static int found;
+
+static _Unwind_Reason_Code trace(struct _Unwind_Context *ctx, void *arg)
+{
+	Dl_info info;
+	if (dladdr((void *) _Unwind_GetIP(ctx), &info) && info.dli_sname && strcmp(info.dli_sname, "main") == 0)
+		found = 1;
+	return _URC_NO_REASON;
+}
+
+void callback(void)
+{
+	_Unwind_Backtrace(trace, 0);
+}
+
+void f(void);
+
+int main(void)
+{
+	f();
+	return found ? 0 : 1;
+}
+`
+
+	for _, framePtr := range []bool{false, true} {
+		t.Run(fmt.Sprintf("FramePtr=%v", framePtr), func(t *testing.T) {
+			opts := []ga.Option{ga.WithCallFrameInfo()}
+			if framePtr {
+				opts = append(opts, ga.WithFramePointer())
+			}
+			a := ga.NewAssembly(ga.AMD64, sys, opts...)
+			a.Function("f")
+			a.MoveImm(r0, 1)
+			a.Push(r0)
+			f := &ga.Frame{FramePtr: framePtr}
+			f.Local("x", 24, 8)
+			a.AllocateFrame(f)
+			a.CallLib("callback", nil, nil)
+			a.FreeFrame(f)
+			a.Pop(r0)
+			a.Return()
+
+			asm := filepath.Join(t.TempDir(), "f.s")
+			if err := os.WriteFile(asm, []byte(a.String()), 0666); err != nil {
+				t.Fatal(err)
+			}
+			prog := buildC(t, main, "-rdynamic", asm)
+			checkExit(t, prog, 0)
+		})
+	}
+}
+
+func TestCallFrameInfoPanics(t *testing.T) {
+	for want, f := range map[string]func(){
+		"call frame information is not supported with this syntax or architecture": func() {
+			ga.NewAssembly(ga.AMD64, sys, ga.WithCallFrameInfo(), ga.WithSyntax(ga.GoSyntax))
+		},
+		"stack pointer update cannot be described by call frame information": func() {
+			a := ga.NewAssembly(ga.AMD64, sys, ga.WithCallFrameInfo())
+			a.Function("f")
+			a.MoveImm(r0, 0)
+			a.MoveReg(sys.StackPtr, r0)
+		},
+	} {
+		func() {
+			defer func() {
+				if s := fmt.Sprint(recover()); s != want {
+					t.Error(s)
+				}
+			}()
+			f()
+		}()
+	}
+}
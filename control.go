@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+// inverse condition.
+func (c Cond) inverse() Cond {
+	switch c {
+	case EQ:
+		return NE
+	case NE:
+		return EQ
+	case LT:
+		return GE
+	case LE:
+		return GT
+	case GT:
+		return LE
+	case GE:
+		return LT
+	}
+
+	panic(c)
+}
+
+// joinUsage keeps the usage of registers which are used in the same way on
+// both paths.
+func joinUsage(x, y [32]string) (z [32]string) {
+	for i := range x {
+		if x[i] == y[i] {
+			z[i] = x[i]
+		}
+	}
+	return
+}
+
+// If generates code which is executed if the condition between the registers
+// holds.  Register usage afterwards is the intersection of the usage at the
+// end of the code and before it.
+func (a *Assembly) If(c Cond, x, y Reg, then func()) {
+	skip := a.NewLabel("endif")
+	a.JumpIfReg(c.inverse(), x, y, skip)
+	a.ifThen(skip, then)
+}
+
+// IfImm generates code which is executed if the condition between the
+// register and the immediate value holds.
+func (a *Assembly) IfImm(c Cond, r Reg, value int, then func()) {
+	skip := a.NewLabel("endif")
+	a.JumpIfImm(c.inverse(), r, value, skip)
+	a.ifThen(skip, then)
+}
+
+// IfElse generates code which executes the first function if the condition
+// between the registers holds, and the second one otherwise.  Register usage
+// afterwards is the intersection of the usage at the ends of both branches.
+func (a *Assembly) IfElse(c Cond, x, y Reg, then, els func()) {
+	skip := a.NewLabel("else")
+	a.JumpIfReg(c.inverse(), x, y, skip)
+	a.ifThenElse(skip, then, els)
+}
+
+// IfImmElse generates code which executes the first function if the
+// condition between the register and the immediate value holds, and the
+// second one otherwise.
+func (a *Assembly) IfImmElse(c Cond, r Reg, value int, then, els func()) {
+	skip := a.NewLabel("else")
+	a.JumpIfImm(c.inverse(), r, value, skip)
+	a.ifThenElse(skip, then, els)
+}
+
+func (a *Assembly) ifThen(skip string, then func()) {
+	jumpUsage := a.regUsage
+
+	then()
+
+	a.Label(skip)
+	a.regUsage = joinUsage(jumpUsage, a.regUsage)
+}
+
+func (a *Assembly) ifThenElse(skip string, then, els func()) {
+	jumpUsage := a.regUsage
+
+	then()
+
+	thenUsage := a.regUsage
+	end := a.NewLabel("endif")
+	a.Jump(end)
+	a.Label(skip)
+	a.regUsage = jumpUsage
+
+	els()
+
+	a.Label(end)
+	a.regUsage = joinUsage(thenUsage, a.regUsage)
+}
+
+// LoopBlock is passed to the body of Loop, WhileReg and WhileImm.
+type LoopBlock struct {
+	a          *Assembly
+	top        string
+	exit       string
+	exited     bool
+	breakUsage [32]string
+}
+
+// Loop generates code which is repeated until Break is called.  Register
+// usage after the loop is the intersection of the usage at the Break calls.
+// The body must not change the usage of registers which it reads before
+// Continue or at the end.
+func (a *Assembly) Loop(body func(l *LoopBlock)) {
+	l := a.newLoop()
+	a.Label(l.top)
+	a.loop(l, body)
+}
+
+// WhileReg generates code which is repeated while the condition between the
+// registers holds.
+func (a *Assembly) WhileReg(c Cond, x, y Reg, body func(l *LoopBlock)) {
+	l := a.newLoop()
+	a.Label(l.top)
+	l.exitUsage()
+	a.JumpIfReg(c.inverse(), x, y, l.exit)
+	a.loop(l, body)
+}
+
+// WhileImm generates code which is repeated while the condition between the
+// register and the immediate value holds.
+func (a *Assembly) WhileImm(c Cond, r Reg, value int, body func(l *LoopBlock)) {
+	l := a.newLoop()
+	a.Label(l.top)
+	l.exitUsage()
+	a.JumpIfImm(c.inverse(), r, value, l.exit)
+	a.loop(l, body)
+}
+
+func (a *Assembly) newLoop() *LoopBlock {
+	return &LoopBlock{
+		a:    a,
+		top:  a.NewLabel("loop"),
+		exit: a.NewLabel("break"),
+	}
+}
+
+func (a *Assembly) loop(l *LoopBlock, body func(l *LoopBlock)) {
+	entryUsage := a.regUsage
+
+	body(l)
+	a.Jump(l.top)
+
+	if l.exited {
+		a.Label(l.exit)
+		a.regUsage = l.breakUsage
+	} else {
+		a.regUsage = entryUsage // Unreachable.
+	}
+}
+
+// Break out of the loop.
+func (l *LoopBlock) Break() {
+	l.exitUsage()
+	l.a.Jump(l.exit)
+}
+
+// Continue with the next iteration.  WhileReg and WhileImm check the
+// condition first.
+func (l *LoopBlock) Continue() {
+	l.a.Jump(l.top)
+}
+
+func (l *LoopBlock) exitUsage() {
+	if l.exited {
+		l.breakUsage = joinUsage(l.breakUsage, l.a.regUsage)
+	} else {
+		l.breakUsage = l.a.regUsage
+		l.exited = true
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"testing"
+
+	"gate.computer/ga"
+)
+
+// control generates nested control flow structures.
+func control(a *ga.Assembly) {
+	a.Function("control")
+	a.MoveImm(r0, 0)
+	a.MoveImm(r1, 10)
+	for i := 0; i < 2; i++ {
+		a.WhileReg(ga.LT, r0, r1, func(l *ga.LoopBlock) {
+			a.AddImm(r0, r0, 1)
+			a.IfImmElse(ga.EQ, r0, 5, func() {
+				l.Continue()
+			}, func() {
+				a.If(ga.GT, r0, r1, func() {
+					l.Break()
+				})
+			})
+		})
+		a.Loop(func(l *ga.LoopBlock) {
+			a.SubtractImm(r0, 1)
+			a.IfImm(ga.LE, r0, 0, l.Break)
+		})
+	}
+	a.Return()
+}
+
+func TestControlAssemble(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			control(a)
+			b, err := a.BytesChecked()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch arch {
+			case ga.C:
+				buildC(t, string(b), "-c")
+			default:
+				assembleCheck(t, arch, string(b))
+			}
+		})
+	}
+}
+
+func TestControlUsage(t *testing.T) {
+	use := func(a *ga.Assembly, r ga.Reg) string {
+		return a.Usage()[r.Num(ga.AMD64.ID())]
+	}
+	param := sys.LibParams[0]
+
+	a := ga.NewAssembly(ga.AMD64, sys)
+	a.Function("f")
+	a.MoveImm(r0, 0)
+	a.MoveImm(param, 0)
+	a.IfImmElse(ga.EQ, r0, 0, func() {
+		a.MoveImm(r1, 1)
+		a.MoveImm(r2, 2)
+		a.Call("g")
+	}, func() {
+		a.MoveImm(r1, 3)
+	})
+	if use(a, r0) != "r0" || use(a, r1) != "r1" || use(a, r2) != "" || use(a, param) != "" {
+		t.Errorf("usage after IfImmElse: %q", a.Usage())
+	}
+
+	a.If(ga.EQ, r0, r1, func() {
+		a.MoveImm(r2, 2)
+	})
+	if use(a, r2) != "" {
+		t.Errorf("usage after If: %q", a.Usage())
+	}
+
+	a.Loop(func(l *ga.LoopBlock) {
+		a.MoveImm(r2, 2)
+		a.IfImm(ga.EQ, r0, 0, l.Break)
+		a.MoveImm(temp, 3)
+		l.Break()
+	})
+	if use(a, r2) != "r2" || use(a, temp) != "" {
+		t.Errorf("usage after Loop: %q", a.Usage())
+	}
+}
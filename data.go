@@ -0,0 +1,241 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+type Section uint8
+
+const (
+	TextSection Section = iota
+	DataSection
+	RODataSection
+	BSSSection // Only DataZero may be used.
+	TDataSection
+	TBSSSection // Only DataZero may be used.
+)
+
+func (s Section) String() string {
+	switch s {
+	case TextSection:
+		return ".text"
+	case DataSection:
+		return ".data"
+	case RODataSection:
+		return ".rodata"
+	case BSSSection:
+		return ".bss"
+	case TDataSection:
+		return ".tdata"
+	case TBSSSection:
+		return ".tbss"
+	}
+
+	panic(s)
+}
+
+// Section switches the output section.  Code must be generated in
+// TextSection, which is the initial section.  Data methods are supported only
+// with GNU assembler.
+func (a *Assembly) Section(s Section) {
+	a.checkData()
+	a.endSymbol()
+	a.printf("")
+	a.printf(".section %s", s)
+	a.section = s
+	a.loc = sourceLocation{} // Line info is per section.
+}
+
+// DataSymbol defines an aligned symbol at the current position of a data
+// section.  Names which start with "." are local.  Global names have object
+// type (or TLS object type in TDataSection and TBSSSection) and their size is
+// emitted automatically.
+func (a *Assembly) DataSymbol(name string, align int, opts ...SymbolOptions) {
+	a.checkData()
+	a.define(name)
+	typ := "@object"
+	if a.section == TDataSection || a.section == TBSSSection {
+		typ = "@tls_object"
+	}
+	a.declare(name, typ, opts)
+	a.printf("")
+	if align > 1 {
+		a.printf(".balign %d", align)
+	}
+	a.label(name)
+}
+
+func (a *Assembly) DataAlign(align int) {
+	a.checkData()
+	a.printf(".balign %d", align)
+}
+
+func (a *Assembly) DataBytes(b []byte) {
+	a.checkData()
+	for len(b) > 0 {
+		n := len(b)
+		if n > 16 {
+			n = 16
+		}
+
+		values := make([]string, n)
+		for i, x := range b[:n] {
+			values[i] = fmt.Sprint(x)
+		}
+		a.insn(".byte", values...)
+
+		b = b[n:]
+	}
+}
+
+// DataString without terminator.
+func (a *Assembly) DataString(s string) {
+	a.checkData()
+	a.insn(".ascii", quoteString(s))
+}
+
+func (a *Assembly) DataWord4(value uint32) {
+	a.checkData()
+	a.insn(".4byte", fmt.Sprint(value))
+}
+
+func (a *Assembly) DataWord8(value uint64) {
+	a.checkData()
+	a.insn(".8byte", fmt.Sprint(value))
+}
+
+// DataAddress emits an 8-byte absolute address of a symbol.
+func (a *Assembly) DataAddress(name string) {
+	a.checkData()
+	a.refer(name)
+	a.insn(".8byte", symbol(name))
+}
+
+// DataZero emits zero bytes.
+func (a *Assembly) DataZero(size int) {
+	a.checkData()
+	a.insn(".zero", fmt.Sprint(size))
+}
+
+// DataValue defines a symbol which contains a serialized Go value.  Integers,
+// floats, booleans, strings, arrays, slices and structs are supported.
+// Values are little-endian.  Strings are inlined without terminator, and
+// slices are inlined like arrays.  Struct fields are laid out with natural
+// alignment like in C.  The symbol is aligned according to the type.
+func (a *Assembly) DataValue(name string, value interface{}, opts ...SymbolOptions) {
+	v := reflect.ValueOf(value)
+	a.DataSymbol(name, dataAlign(v.Type()), opts...)
+	a.DataBytes(encodeData(nil, v))
+}
+
+func (a *Assembly) checkData() {
+	if a.syntax == GoSyntax || a.Arch.ID() == IDC {
+		panic("data is not supported with this syntax or architecture")
+	}
+}
+
+func dataAlign(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.String:
+		return 1
+
+	case reflect.Array, reflect.Slice:
+		return dataAlign(t.Elem())
+
+	case reflect.Struct:
+		align := 1
+		for i := 0; i < t.NumField(); i++ {
+			if n := dataAlign(t.Field(i).Type); n > align {
+				align = n
+			}
+		}
+		return align
+
+	case reflect.Bool, reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16, reflect.Int32, reflect.Uint32, reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return int(t.Size())
+	}
+
+	panic(fmt.Sprintf("unsupported data type: %s", t))
+}
+
+// encodeData appends value to b.  Padding is relative to the start of b.
+func encodeData(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return appendUint(b, uint64(v.Int()), int(v.Type().Size()))
+
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		return appendUint(b, v.Uint(), int(v.Type().Size()))
+
+	case reflect.Float32:
+		return appendUint(b, uint64(math.Float32bits(float32(v.Float()))), 4)
+
+	case reflect.Float64:
+		return appendUint(b, math.Float64bits(v.Float()), 8)
+
+	case reflect.String:
+		return append(b, v.String()...)
+
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			b = encodeData(b, v.Index(i))
+		}
+		return b
+
+	case reflect.Struct:
+		start := len(b)
+		for i := 0; i < v.NumField(); i++ {
+			b = padData(b, start, dataAlign(v.Field(i).Type()))
+			b = encodeData(b, v.Field(i))
+		}
+		return padData(b, start, dataAlign(v.Type()))
+	}
+
+	panic(fmt.Sprintf("unsupported data type: %s", v.Type()))
+}
+
+func appendUint(b []byte, x uint64, size int) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(b, buf[:size]...)
+}
+
+func padData(b []byte, start, align int) []byte {
+	for (len(b)-start)%align != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// quoteString for GNU assembler.
+func quoteString(s string) string {
+	b := new(strings.Builder)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(b, "\\%03o", c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
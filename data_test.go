@@ -0,0 +1,163 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+type dataRecord struct {
+	A uint8
+	B uint32
+	C bool
+	D int16
+	E [2]float32
+	F string
+	G []uint64
+}
+
+var dataRecordValue = dataRecord{
+	A: 1,
+	B: 0x02030405,
+	C: true,
+	D: -2,
+	E: [2]float32{1, -1},
+	F: "xyz",
+	G: []uint64{0x0807060504030201},
+}
+
+var dataRecordBytes = []byte{
+	1, 0, 0, 0, // A, padding
+	5, 4, 3, 2, // B
+	1, 0, // C, padding
+	0xfe, 0xff, // D
+	0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x80, 0xbf, // E
+	'x', 'y', 'z', 0, // F, padding
+	1, 2, 3, 4, 5, 6, 7, 8, // G
+}
+
+func data(a *ga.Assembly) {
+	a.Section(ga.RODataSection)
+	a.DataValue("record", dataRecordValue)
+
+	a.Section(ga.DataSection)
+	a.DataSymbol(".local", 4)
+	a.DataWord4(0x11223344)
+	a.DataString("a\"\\\x00\xff")
+	a.DataAlign(8)
+	a.DataWord8(0x5566778899aabbcc)
+	a.DataAddress("record")
+
+	a.Section(ga.BSSSection)
+	a.DataSymbol("zero", 16)
+	a.DataZero(100)
+
+	a.Section(ga.TextSection)
+	a.Function("data_user")
+	a.Address(r0, "record")
+	a.Return()
+}
+
+// gnuObject assembles x86-64 source with GNU as and returns the object file
+// name.  The test is skipped if as is not installed.
+func gnuObject(t *testing.T, source string) string {
+	t.Helper()
+
+	as, err := exec.LookPath("as")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "x.S")
+	obj := filepath.Join(dir, "x.o")
+
+	if err := os.WriteFile(src, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(as, "-o", obj, src).CombinedOutput(); err != nil {
+		t.Fatalf("as: %v\n%s\n%s", err, out, source)
+	}
+	return obj
+}
+
+// gnuSection assembles x86-64 source with GNU as and returns the contents of
+// a section.
+func gnuSection(t *testing.T, source, section string) []byte {
+	t.Helper()
+
+	obj := gnuObject(t, source)
+	bin := obj + ".bin"
+
+	if out, err := exec.Command("objcopy", "-O", "binary", "-j", section, obj, bin).CombinedOutput(); err != nil {
+		t.Fatalf("objcopy: %v\n%s", err, out)
+	}
+
+	b, err := os.ReadFile(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestData(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			data(a)
+			assembleCheck(t, arch, a.String())
+		})
+	}
+}
+
+func TestDataContents(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+	data(a)
+	source := a.String()
+
+	if b := gnuSection(t, source, ".rodata"); !bytes.Equal(b, dataRecordBytes) {
+		t.Errorf("rodata: %x", b)
+	}
+
+	want := []byte{
+		0x44, 0x33, 0x22, 0x11,
+		'a', '"', '\\', 0x00, 0xff, 0, 0, 0, 0, 0, 0, 0, // Aligned to 8.
+		0xcc, 0xbb, 0xaa, 0x99, 0x88, 0x77, 0x66, 0x55,
+		0, 0, 0, 0, 0, 0, 0, 0, // Relocated.
+	}
+	if b := gnuSection(t, source, ".data"); !bytes.Equal(b, want) {
+		t.Errorf("data: %x", b)
+	}
+}
+
+func TestDataUnsupported(t *testing.T) {
+	for _, a := range []*ga.Assembly{
+		ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.GoSyntax)),
+		ga.NewAssembly(ga.ARM64, sys, ga.WithSyntax(ga.GoSyntax)),
+		ga.NewAssembly(ga.C, sys),
+	} {
+		func() {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Error("no panic")
+				} else if s := fmt.Sprint(x); s != "data is not supported with this syntax or architecture" {
+					t.Error(s)
+				}
+			}()
+			a.Section(ga.DataSection)
+		}()
+	}
+}
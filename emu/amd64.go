@@ -0,0 +1,804 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package emu
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type regAMD64 struct {
+	n    int // 0-15 in encoding order.
+	size int // 1, 2, 4 or 8 bytes.
+}
+
+var namesAMD64 = map[string]regAMD64{}
+
+func init() {
+	for n, names := range [][4]string{
+		{"rax", "eax", "ax", "al"},
+		{"rcx", "ecx", "cx", "cl"},
+		{"rdx", "edx", "dx", "dl"},
+		{"rbx", "ebx", "bx", "bl"},
+		{"rsp", "esp", "sp", "spl"},
+		{"rbp", "ebp", "bp", "bpl"},
+		{"rsi", "esi", "si", "sil"},
+		{"rdi", "edi", "di", "dil"},
+	} {
+		namesAMD64[names[0]] = regAMD64{n, 8}
+		namesAMD64[names[1]] = regAMD64{n, 4}
+		namesAMD64[names[2]] = regAMD64{n, 2}
+		namesAMD64[names[3]] = regAMD64{n, 1}
+	}
+
+	for n := 8; n < 16; n++ {
+		namesAMD64[fmt.Sprintf("r%d", n)] = regAMD64{n, 8}
+		namesAMD64[fmt.Sprintf("r%dd", n)] = regAMD64{n, 4}
+		namesAMD64[fmt.Sprintf("r%dw", n)] = regAMD64{n, 2}
+		namesAMD64[fmt.Sprintf("r%db", n)] = regAMD64{n, 1}
+		namesAMD64[fmt.Sprintf("r%dl", n)] = regAMD64{n, 1}
+	}
+}
+
+func parseFloatRegAMD64(s string) (int, bool) {
+	if strings.HasPrefix(s, "xmm") {
+		if n, err := strconv.Atoi(s[3:]); err == nil && n >= 0 && n < 16 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// AMD64 interpreter.  It implements the x86-64 user-mode instructions emitted
+// by the ga.AMD64 backend (in Intel syntax), and Linux syscalls via the
+// syscall instruction.
+type AMD64 struct {
+	Machine
+
+	R   [16]uint64 // General-purpose registers in encoding order.
+	XMM [16]uint64 // Low halves of SSE registers.
+	PC  uint64
+
+	CF, ZF, SF, OF bool
+
+	prog *Program
+}
+
+// NewAMD64 parses assembly source and maps the stack.
+func NewAMD64(source string) (*AMD64, error) {
+	p, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &AMD64{prog: p}
+	c.R[4] = StackAddr + StackSize
+	c.Map(StackAddr, StackSize)
+	return c, nil
+}
+
+// Program which is being executed.
+func (c *AMD64) Program() *Program {
+	return c.prog
+}
+
+// Reg returns the value of a general-purpose register.
+func (c *AMD64) Reg(n int) uint64 {
+	return c.R[n]
+}
+
+// SetReg sets the value of a general-purpose register.
+func (c *AMD64) SetReg(n int, x uint64) {
+	c.R[n] = x
+}
+
+// Run from entry symbol (or the first instruction if empty) until the entry
+// routine returns, or a syscall handler requests exit.
+func (c *AMD64) Run(entrySymbol string) error {
+	addr, err := entry(c.prog, entrySymbol)
+	if err != nil {
+		return err
+	}
+	if err := c.loadData(c.prog); err != nil {
+		return err
+	}
+
+	if err := c.push(ReturnAddr); err != nil {
+		return err
+	}
+	c.PC = addr
+	return run(c)
+}
+
+func (c *AMD64) machine() *Machine { return &c.Machine }
+func (c *AMD64) program() *Program { return c.prog }
+func (c *AMD64) pc() *uint64       { return &c.PC }
+
+func (c *AMD64) get(r regAMD64) uint64 {
+	return c.R[r.n] & sizeMask(r.size)
+}
+
+func (c *AMD64) set(r regAMD64, x uint64) {
+	switch r.size {
+	case 8:
+		c.R[r.n] = x
+	case 4:
+		c.R[r.n] = uint64(uint32(x)) // Zero-extended.
+	default:
+		m := sizeMask(r.size)
+		c.R[r.n] = c.R[r.n]&^m | x&m // Merged.
+	}
+}
+
+func sizeMask(size int) uint64 {
+	if size == 8 {
+		return ^uint64(0)
+	}
+	return 1<<(8*uint(size)) - 1
+}
+
+func signExtend(x uint64, size int) uint64 {
+	shift := 64 - 8*uint(size)
+	return uint64(int64(x<<shift) >> shift)
+}
+
+func (c *AMD64) push(x uint64) error {
+	c.R[4] -= 8
+	return c.Store(c.R[4], 8, x)
+}
+
+func (c *AMD64) pop() (uint64, error) {
+	x, err := c.Load(c.R[4], 8)
+	c.R[4] += 8
+	return x, err
+}
+
+// operandAMD64 is a register, memory or immediate operand.
+type operandAMD64 struct {
+	reg  regAMD64
+	mem  bool
+	addr uint64
+	size int // Zero if unknown (memory operand without ptr prefix).
+	imm  bool
+	val  uint64
+}
+
+func (c *AMD64) operand(s string) (o operandAMD64, err error) {
+	if r, ok := namesAMD64[s]; ok {
+		o.reg = r
+		o.size = r.size
+		return
+	}
+
+	for prefix, size := range map[string]int{
+		"byte ptr ":  1,
+		"word ptr ":  2,
+		"dword ptr ": 4,
+		"qword ptr ": 8,
+	} {
+		if strings.HasPrefix(s, prefix) {
+			s = strings.TrimSpace(s[len(prefix):])
+			o.size = size
+		}
+	}
+
+	if strings.HasPrefix(s, "fs:") {
+		s = s[3:]
+		o.mem = true
+		if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+			o.addr, err = c.effectiveAddress(s[1 : len(s)-1])
+		} else {
+			o.addr, err = c.effectiveAddress(s)
+		}
+		o.addr += c.ThreadPointer
+		return
+	}
+
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		o.mem = true
+		o.addr, err = c.effectiveAddress(s[1 : len(s)-1])
+		return
+	}
+
+	if x, e := parseImm(s); e == nil {
+		o.imm = true
+		o.val = uint64(x)
+		return
+	}
+
+	if x, e := c.symbol(c.prog, s); e == nil {
+		o.imm = true
+		o.val = x
+		return
+	}
+
+	err = fmt.Errorf("invalid operand: %s", s)
+	return
+}
+
+func (c *AMD64) effectiveAddress(expr string) (uint64, error) {
+	var (
+		addr uint64
+		neg  bool
+	)
+
+	for _, term := range strings.Fields(expr) {
+		switch term {
+		case "+":
+			neg = false
+			continue
+		case "-":
+			neg = true
+			continue
+		}
+
+		var x uint64
+
+		if n := strings.Index(term, "*"); n > 0 {
+			r, ok := namesAMD64[term[:n]]
+			if !ok {
+				return 0, fmt.Errorf("invalid index register: %s", term)
+			}
+			scale, err := parseImm(term[n+1:])
+			if err != nil {
+				return 0, err
+			}
+			x = c.get(r) * uint64(scale)
+		} else if term == "rip" {
+			// Symbols are absolute in the interpreter.
+		} else if r, ok := namesAMD64[term]; ok {
+			x = c.get(r)
+		} else if v, err := parseImm(term); err == nil {
+			disp := v
+			if neg {
+				disp = -v
+			}
+			if disp < math.MinInt32 || disp > math.MaxInt32 {
+				return 0, fmt.Errorf("displacement cannot be encoded: %s", term)
+			}
+			x = uint64(v)
+		} else if strings.HasSuffix(term, "@GOTPCREL") {
+			if x, err = c.gotEntry(c.prog, strings.TrimSuffix(term, "@GOTPCREL")); err != nil {
+				return 0, err
+			}
+		} else if strings.HasSuffix(term, "@GOTTPOFF") {
+			if x, err = c.gotTLSEntry(c.prog, strings.TrimSuffix(term, "@GOTTPOFF")); err != nil {
+				return 0, err
+			}
+		} else if strings.Contains(term, "@tpoff") {
+			if x, err = c.tlsOffset(c.prog, strings.Replace(term, "@tpoff", "", 1)); err != nil {
+				return 0, err
+			}
+		} else if v, err := c.symbol(c.prog, term); err == nil {
+			x = v
+		} else {
+			return 0, fmt.Errorf("invalid address term: %s", term)
+		}
+
+		if neg {
+			addr -= x
+		} else {
+			addr += x
+		}
+	}
+
+	return addr, nil
+}
+
+func (c *AMD64) read(o operandAMD64) (uint64, error) {
+	switch {
+	case o.imm:
+		return o.val, nil
+	case o.mem:
+		if o.size == 0 {
+			return 0, errors.New("ambiguous memory operand size")
+		}
+		return c.Load(o.addr, o.size)
+	default:
+		return c.get(o.reg), nil
+	}
+}
+
+func (c *AMD64) write(o operandAMD64, x uint64) error {
+	switch {
+	case o.imm:
+		return errors.New("immediate destination operand")
+	case o.mem:
+		if o.size == 0 {
+			return errors.New("ambiguous memory operand size")
+		}
+		return c.Store(o.addr, o.size, x)
+	default:
+		c.set(o.reg, x)
+		return nil
+	}
+}
+
+// operands parses a destination and source.  Size of memory operand is
+// inferred from the other operand.  Integer source operand must be encodable
+// for the instruction.
+func (c *AMD64) operands(mnemonic string, ops []string) (d, s operandAMD64, err error) {
+	if len(ops) != 2 {
+		err = fmt.Errorf("expected 2 operands")
+		return
+	}
+	if d, err = c.operand(ops[0]); err != nil {
+		return
+	}
+	if s, err = c.operand(ops[1]); err != nil {
+		return
+	}
+	if d.size == 0 {
+		d.size = s.size
+	}
+	if s.size == 0 {
+		s.size = d.size
+	}
+	if s.imm {
+		if x, e := parseImm(ops[1]); e == nil && !immEncodableAMD64(mnemonic, x, d) {
+			err = fmt.Errorf("immediate operand cannot be encoded: %s", ops[1])
+			return
+		}
+		s.val &= sizeMask(d.size)
+	}
+	return
+}
+
+// immEncodableAMD64 reports if an integer can be encoded as the source
+// operand of an instruction with destination d.  Only mov to a 64-bit
+// register takes a 64-bit immediate; other 64-bit operations sign-extend a
+// 32-bit immediate.
+func immEncodableAMD64(mnemonic string, x int64, d operandAMD64) bool {
+	switch {
+	case d.size == 8 && !d.mem && (mnemonic == "mov" || mnemonic == "movabs"):
+		return true
+	case d.size == 8:
+		return x >= math.MinInt32 && x <= math.MaxInt32
+	default:
+		bits := 8 * uint(d.size)
+		return x >= -1<<(bits-1) && x < 1<<bits
+	}
+}
+
+func (c *AMD64) flags(r uint64, size int) {
+	shift := 64 - 8*uint(size)
+	r <<= shift
+	c.ZF = r == 0
+	c.SF = int64(r) < 0
+}
+
+func (c *AMD64) arith(op string, x, y uint64, size int) uint64 {
+	shift := 64 - 8*uint(size)
+	a, b := x<<shift, y<<shift
+	var r uint64
+
+	switch op {
+	case "add":
+		r = a + b
+		c.CF = r < a
+		c.OF = (^(a^b)&(a^r))>>63 != 0
+	case "sub", "cmp":
+		r = a - b
+		c.CF = a < b
+		c.OF = ((a^b)&(a^r))>>63 != 0
+	default:
+		switch op {
+		case "and", "test":
+			r = a & b
+		case "or":
+			r = a | b
+		case "xor":
+			r = a ^ b
+		}
+		c.CF = false
+		c.OF = false
+	}
+
+	c.ZF = r == 0
+	c.SF = int64(r) < 0
+	return r >> shift
+}
+
+func (c *AMD64) cond(s string) (bool, error) {
+	switch s {
+	case "e", "z":
+		return c.ZF, nil
+	case "ne", "nz":
+		return !c.ZF, nil
+	case "l":
+		return c.SF != c.OF, nil
+	case "le":
+		return c.ZF || c.SF != c.OF, nil
+	case "g":
+		return !c.ZF && c.SF == c.OF, nil
+	case "ge":
+		return c.SF == c.OF, nil
+	case "b", "c":
+		return c.CF, nil
+	case "be":
+		return c.CF || c.ZF, nil
+	case "a":
+		return !c.CF && !c.ZF, nil
+	case "ae", "nc":
+		return !c.CF, nil
+	case "s":
+		return c.SF, nil
+	case "ns":
+		return !c.SF, nil
+	case "o":
+		return c.OF, nil
+	case "no":
+		return !c.OF, nil
+	}
+	return false, fmt.Errorf("unknown condition: %s", s)
+}
+
+func (c *AMD64) target(s string) (uint64, error) {
+	if r, ok := namesAMD64[s]; ok && r.size == 8 {
+		return c.get(r), nil
+	}
+	if strings.HasPrefix(s, "qword ptr ") || strings.HasPrefix(s, "[") {
+		o, err := c.operand(s)
+		if err != nil {
+			return 0, err
+		}
+		o.size = 8
+		return c.read(o)
+	}
+	return c.symbol(c.prog, s)
+}
+
+func (c *AMD64) step(x *insn) (err error) {
+	ops := x.operands
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid operands: %v", r)
+		}
+	}()
+
+	switch x.mnemonic {
+	case "nop", "pause", "lfence", "mfence", "sfence":
+
+	case "int3", "ud2":
+		return errors.New("breakpoint (unreachable code)")
+
+	case "syscall":
+		args := [6]uint64{c.R[7], c.R[6], c.R[2], c.R[10], c.R[8], c.R[9]}
+		result, exit := c.syscall(int(c.R[0]), args)
+		c.R[0] = result
+		c.R[1] = c.PC // Return address.
+		c.R[11] = 0x202
+		if exit {
+			return ErrExit
+		}
+
+	case "mov", "movabs":
+		d, s, err := c.operands(x.mnemonic, ops)
+		if err != nil {
+			return err
+		}
+		v, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		return c.write(d, v)
+
+	case "movzx", "movsx", "movsxd":
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		s, err := c.operand(ops[1])
+		if err != nil {
+			return err
+		}
+		if s.size == 0 {
+			if x.mnemonic == "movsxd" {
+				s.size = 4
+			} else {
+				return errors.New("ambiguous memory operand size")
+			}
+		}
+		v, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		if x.mnemonic != "movzx" {
+			v = signExtend(v, s.size)
+		}
+		return c.write(d, v)
+
+	case "movq":
+		if n, ok := parseFloatRegAMD64(ops[1]); ok {
+			d, err := c.operand(ops[0])
+			if err != nil {
+				return err
+			}
+			d.size = 8
+			return c.write(d, c.XMM[n])
+		}
+		if n, ok := parseFloatRegAMD64(ops[0]); ok {
+			s, err := c.operand(ops[1])
+			if err != nil {
+				return err
+			}
+			s.size = 8
+			v, err := c.read(s)
+			if err != nil {
+				return err
+			}
+			c.XMM[n] = v
+			return nil
+		}
+		return fmt.Errorf("invalid operands")
+
+	case "lea":
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		s := strings.TrimSpace(ops[1])
+		if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+			return fmt.Errorf("invalid operand: %s", s)
+		}
+		addr, err := c.effectiveAddress(s[1 : len(s)-1])
+		if err != nil {
+			return err
+		}
+		return c.write(d, addr)
+
+	case "add", "sub", "and", "or", "xor":
+		d, s, err := c.operands(x.mnemonic, ops)
+		if err != nil {
+			return err
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		b, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		return c.write(d, c.arith(x.mnemonic, a, b, d.size))
+
+	case "cmp", "test":
+		d, s, err := c.operands(x.mnemonic, ops)
+		if err != nil {
+			return err
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		b, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		c.arith(x.mnemonic, a, b, d.size)
+
+	case "bt":
+		d, s, err := c.operands(x.mnemonic, ops)
+		if err != nil {
+			return err
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		b, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		if s.imm && b > math.MaxUint8 {
+			return fmt.Errorf("bit offset cannot be encoded: %s", ops[1])
+		}
+		c.CF = a>>(b&uint64(8*d.size-1))&1 != 0
+
+	case "neg", "not", "inc", "dec":
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		switch x.mnemonic {
+		case "neg":
+			a = c.arith("sub", 0, a, d.size)
+		case "not":
+			a = ^a
+		case "inc":
+			cf := c.CF
+			a = c.arith("add", a, 1, d.size)
+			c.CF = cf
+		case "dec":
+			cf := c.CF
+			a = c.arith("sub", a, 1, d.size)
+			c.CF = cf
+		}
+		return c.write(d, a)
+
+	case "imul":
+		if len(ops) != 3 {
+			return errors.New("unsupported imul form")
+		}
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		s, err := c.operand(ops[1])
+		if err != nil {
+			return err
+		}
+		if s.size == 0 {
+			s.size = d.size
+		}
+		a, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		b, err := parseImm(ops[2])
+		if err != nil {
+			return err
+		}
+		if b < math.MinInt32 || b > math.MaxInt32 {
+			return fmt.Errorf("immediate operand cannot be encoded: %s", ops[2])
+		}
+		return c.write(d, a*uint64(b))
+
+	case "shl", "shr", "sar":
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		var count uint64
+		if r, ok := namesAMD64[ops[1]]; ok && r.n == 1 && r.size == 1 {
+			count = c.get(r)
+		} else {
+			n, err := parseImm(ops[1])
+			if err != nil {
+				return err
+			}
+			if n < 0 || n > math.MaxUint8 {
+				return fmt.Errorf("shift count cannot be encoded: %s", ops[1])
+			}
+			count = uint64(n)
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		if d.size == 8 {
+			count &= 63
+		} else {
+			count &= 31
+		}
+		switch x.mnemonic {
+		case "shl":
+			a <<= count
+		case "shr":
+			a >>= count
+		case "sar":
+			a = uint64(int64(signExtend(a, d.size)) >> count)
+		}
+		if count != 0 {
+			c.flags(a, d.size)
+		}
+		return c.write(d, a)
+
+	case "xchg":
+		d, s, err := c.operands(x.mnemonic, ops)
+		if err != nil {
+			return err
+		}
+		a, err := c.read(d)
+		if err != nil {
+			return err
+		}
+		b, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		if err := c.write(d, b); err != nil {
+			return err
+		}
+		return c.write(s, a)
+
+	case "push":
+		s, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		if s.size == 0 {
+			s.size = 8
+		}
+		v, err := c.read(s)
+		if err != nil {
+			return err
+		}
+		return c.push(v)
+
+	case "pop":
+		d, err := c.operand(ops[0])
+		if err != nil {
+			return err
+		}
+		v, err := c.pop()
+		if err != nil {
+			return err
+		}
+		if d.size == 0 {
+			d.size = 8
+		}
+		return c.write(d, v)
+
+	case "jmp":
+		addr, err := c.target(ops[0])
+		if err != nil {
+			return err
+		}
+		c.PC = addr
+
+	case "call":
+		addr, err := c.target(ops[0])
+		if err != nil {
+			return err
+		}
+		if err := c.push(c.PC); err != nil {
+			return err
+		}
+		c.PC = addr
+
+	case "ret":
+		addr, err := c.pop()
+		if err != nil {
+			return err
+		}
+		c.PC = addr
+
+	default:
+		if strings.HasPrefix(x.mnemonic, "j") {
+			ok, err := c.cond(x.mnemonic[1:])
+			if err != nil {
+				return err
+			}
+			if ok {
+				addr, err := c.target(ops[0])
+				if err != nil {
+					return err
+				}
+				c.PC = addr
+			}
+			return nil
+		}
+		if strings.HasPrefix(x.mnemonic, "cmov") {
+			ok, err := c.cond(x.mnemonic[4:])
+			if err != nil {
+				return err
+			}
+			d, s, err := c.operands(x.mnemonic, ops)
+			if err != nil {
+				return err
+			}
+			v, err := c.read(s)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				v, _ = c.read(d)
+			}
+			return c.write(d, v)
+		}
+		return fmt.Errorf("unsupported instruction: %s", x.mnemonic)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package emu_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gate.computer/ga/emu"
+)
+
+var encodingTestsAMD64 = []struct {
+	insn  string
+	valid bool
+}{
+	{"mov rax, 0x123456789", true},
+	{"movabs rax, -1", true},
+	{"mov eax, 0xffffffff", true},
+	{"mov eax, -1", true},
+	{"mov eax, 0x100000000", false},
+	{"mov qword ptr [rsp - 8], -1", true},
+	{"mov qword ptr [rsp - 8], 0x80000000", false},
+	{"mov byte ptr [rsp - 1], 255", true},
+	{"mov byte ptr [rsp - 1], -128", true},
+	{"mov byte ptr [rsp - 1], 256", false},
+
+	{"add rax, 2147483647", true},
+	{"add rax, -2147483648", true},
+	{"add rax, 2147483648", false},
+	{"and eax, 0xffffffff", true},
+	{"and rax, 0xffffffff", false},
+	{"or r12, 4294967296", false},
+	{"cmp rax, -1", true},
+	{"cmp rax, 0x80000000", false},
+	{"test rax, 0x7fffffff", true},
+	{"test rax, 0x80000000", false},
+	{"bt rax, 63", true},
+	{"bt rax, 256", false},
+
+	{"shl rax, 63", true},
+	{"sar rax, 255", true},
+	{"shr rax, 256", false},
+	{"imul rax, rbx, -2147483648", true},
+	{"imul rax, rbx, 2147483648", false},
+
+	{"mov rax, [rsp - 8]", true},
+	{"lea rax, [rsp - 2147483648]", true},
+	{"lea rax, [rsp + 2147483647]", true},
+	{"lea rax, [rsp + 2147483648]", false},
+	{"lea rax, [rsp - 2147483649]", false},
+}
+
+func TestEncodingAMD64(t *testing.T) {
+	for _, test := range encodingTestsAMD64 {
+		c, err := emu.NewAMD64(test.insn + "\nret\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.Run("")
+		if invalid := err != nil && strings.Contains(err.Error(), "cannot be encoded"); invalid == test.valid {
+			t.Errorf("%s: %v", test.insn, err)
+		}
+	}
+}
+
+func TestUnsupportedAMD64(t *testing.T) {
+	for _, insn := range []string{
+		"foo rax",
+		"imul rax, rbx",
+		"jxx l",
+		"mov [rsp], 1",
+		"mov rax, xyz",
+		"lea rax, rsp",
+	} {
+		c, err := emu.NewAMD64(insn + "\nl:\n\tret\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var fault *emu.Fault
+		if err := c.Run(""); !errors.As(err, &fault) || fault.Line != 1 || fault.Insn != insn {
+			t.Errorf("%s: %v", insn, err)
+		}
+	}
+}
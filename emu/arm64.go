@@ -0,0 +1,816 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package emu
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const (
+	arm64SP = 31
+	arm64ZR = 32
+)
+
+type regARM64 struct {
+	n int  // 0-30, arm64SP or arm64ZR.
+	w bool // 32-bit view.
+}
+
+func parseRegARM64(s string) (regARM64, bool) {
+	switch s {
+	case "sp":
+		return regARM64{arm64SP, false}, true
+	case "wsp":
+		return regARM64{arm64SP, true}, true
+	case "lr":
+		return regARM64{30, false}, true
+	case "xzr":
+		return regARM64{arm64ZR, false}, true
+	case "wzr":
+		return regARM64{arm64ZR, true}, true
+	}
+
+	if len(s) >= 2 && (s[0] == 'x' || s[0] == 'w') {
+		if n, err := strconv.Atoi(s[1:]); err == nil && n >= 0 && n <= 30 {
+			return regARM64{n, s[0] == 'w'}, true
+		}
+	}
+
+	return regARM64{}, false
+}
+
+func parseFloatRegARM64(s string) (int, bool) {
+	if len(s) >= 2 && s[0] == 'd' {
+		if n, err := strconv.Atoi(s[1:]); err == nil && n >= 0 && n < 32 {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// ARM64 interpreter.  It implements the AArch64 user-mode instructions
+// emitted by the ga.ARM64 backend, and Linux syscalls via the svc instruction.
+type ARM64 struct {
+	Machine
+
+	X  [31]uint64 // General-purpose registers; X[30] is the link register.
+	SP uint64
+	D  [32]uint64 // Floating-point registers as raw bits.
+	PC uint64
+
+	N, Z, C, V bool
+
+	prog *Program
+}
+
+// NewARM64 parses assembly source and maps the stack.
+func NewARM64(source string) (*ARM64, error) {
+	p, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ARM64{
+		SP:   StackAddr + StackSize,
+		prog: p,
+	}
+	c.Map(StackAddr, StackSize)
+	return c, nil
+}
+
+// Program which is being executed.
+func (c *ARM64) Program() *Program {
+	return c.prog
+}
+
+// Reg returns the value of a general-purpose register.  Register 31 is SP.
+func (c *ARM64) Reg(n int) uint64 {
+	if n == arm64SP {
+		return c.SP
+	}
+	return c.X[n]
+}
+
+// SetReg sets the value of a general-purpose register.  Register 31 is SP.
+func (c *ARM64) SetReg(n int, x uint64) {
+	if n == arm64SP {
+		c.SP = x
+	} else {
+		c.X[n] = x
+	}
+}
+
+// Run from entry symbol (or the first instruction if empty) until the entry
+// routine returns, or a syscall handler requests exit.
+func (c *ARM64) Run(entrySymbol string) error {
+	addr, err := entry(c.prog, entrySymbol)
+	if err != nil {
+		return err
+	}
+	if err := c.loadData(c.prog); err != nil {
+		return err
+	}
+
+	c.PC = addr
+	c.X[30] = ReturnAddr
+	return run(c)
+}
+
+func (c *ARM64) machine() *Machine { return &c.Machine }
+func (c *ARM64) program() *Program { return c.prog }
+func (c *ARM64) pc() *uint64       { return &c.PC }
+
+func (c *ARM64) get(r regARM64) uint64 {
+	var x uint64
+	switch r.n {
+	case arm64SP:
+		x = c.SP
+	case arm64ZR:
+	default:
+		x = c.X[r.n]
+	}
+	if r.w {
+		x = uint64(uint32(x))
+	}
+	return x
+}
+
+func (c *ARM64) set(r regARM64, x uint64) {
+	if r.w {
+		x = uint64(uint32(x))
+	}
+	switch r.n {
+	case arm64SP:
+		c.SP = x
+	case arm64ZR:
+	default:
+		c.X[r.n] = x
+	}
+}
+
+func (c *ARM64) reg(s string) (regARM64, error) {
+	r, ok := parseRegARM64(s)
+	if !ok {
+		return r, fmt.Errorf("invalid register operand: %s", s)
+	}
+	return r, nil
+}
+
+// value of register or immediate operand.
+func (c *ARM64) value(s string) (uint64, error) {
+	if r, ok := parseRegARM64(s); ok {
+		return c.get(r), nil
+	}
+	return c.imm(s)
+}
+
+// imm parses an immediate or a relocation operand.
+func (c *ARM64) imm(s string) (uint64, error) {
+	s = strings.TrimPrefix(s, "#")
+	if strings.HasPrefix(s, ":tprel_hi12:") {
+		off, err := c.tlsOffset(c.prog, s[12:])
+		return off >> 12 & 0xfff, err
+	}
+	if strings.HasPrefix(s, ":tprel_lo12_nc:") {
+		off, err := c.tlsOffset(c.prog, s[15:])
+		return off & 0xfff, err
+	}
+	if strings.HasPrefix(s, ":gottprel_lo12:") {
+		addr, err := c.gotTLSEntry(c.prog, s[15:])
+		return addr & 0xfff, err
+	}
+	if strings.HasPrefix(s, ":lo12:") {
+		addr, err := c.symbol(c.prog, s[6:])
+		return addr & 0xfff, err
+	}
+	if strings.HasPrefix(s, ":got_lo12:") {
+		addr, err := c.gotEntry(c.prog, s[10:])
+		return addr & 0xfff, err
+	}
+	x, err := parseImm(s)
+	return uint64(x), err
+}
+
+func (c *ARM64) shiftAmount(operands []string, i int) (uint, error) {
+	if len(operands) <= i {
+		return 0, nil
+	}
+	s := operands[i]
+	if !strings.HasPrefix(s, "lsl ") {
+		return 0, fmt.Errorf("unsupported shift operand: %s", s)
+	}
+	n, err := parseImm(strings.TrimSpace(s[4:]))
+	return uint(n), err
+}
+
+// address of memory operand which begins at operands[0].  Writeback is
+// returned for pre- and post-indexed addressing modes.  The instruction
+// accesses size bytes per register; it determines which offsets can be
+// encoded.
+func (c *ARM64) address(mnemonic string, size int, operands []string) (addr uint64, writeback func(), err error) {
+	s := operands[0]
+	pre := strings.HasSuffix(s, "!")
+	s = strings.TrimSuffix(s, "!")
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		err = fmt.Errorf("invalid memory operand: %s", operands[0])
+		return
+	}
+
+	fields := splitOperands(s[1 : len(s)-1])
+	base, err := c.reg(fields[0])
+	if err != nil {
+		return
+	}
+
+	var offset uint64
+	if len(fields) > 1 {
+		if index, ok := parseRegARM64(fields[1]); ok {
+			var shift uint
+			if shift, err = c.shiftAmount(fields, 2); err != nil {
+				return
+			}
+			offset = c.get(index) << shift
+		} else if offset, err = c.imm(fields[1]); err != nil {
+			return
+		}
+		if x, ok := literalImm(fields[1]); ok {
+			if !offsetEncodableARM64(mnemonic, size, x, pre) {
+				err = fmt.Errorf("offset cannot be encoded: %s", fields[1])
+				return
+			}
+		} else if strings.HasPrefix(strings.TrimPrefix(fields[1], "#"), ":lo12:") && offset%uint64(size) != 0 {
+			err = fmt.Errorf("misaligned offset relocation: %s", fields[1])
+			return
+		}
+	}
+
+	addr = c.get(base) + offset
+
+	switch {
+	case pre:
+		writeback = func() { c.set(base, addr) }
+
+	case len(operands) > 1:
+		var post int64
+		if post, err = parseImm(operands[1]); err != nil {
+			return
+		}
+		if !offsetEncodableARM64(mnemonic, size, post, true) {
+			err = fmt.Errorf("offset cannot be encoded: %s", operands[1])
+			return
+		}
+		writeback = func() { c.set(base, addr+uint64(post)) }
+	}
+
+	return
+}
+
+// offsetEncodableARM64 reports if an immediate offset can be encoded in a
+// load or store instruction.  Pre- and post-indexed offsets are indexed.
+func offsetEncodableARM64(mnemonic string, size int, offset int64, indexed bool) bool {
+	var (
+		n      = int64(size)
+		simm9  = offset >= -256 && offset <= 255
+		scaled = offset%n == 0
+	)
+
+	switch {
+	case mnemonic == "ldp" || mnemonic == "stp":
+		return scaled && offset/n >= -64 && offset/n <= 63
+	case indexed, strings.HasPrefix(mnemonic, "ldu"), strings.HasPrefix(mnemonic, "stu"):
+		return simm9
+	default:
+		return simm9 || (scaled && offset >= 0 && offset/n <= 4095)
+	}
+}
+
+// literalImm parses an immediate operand which is a plain integer (not a
+// register, symbol or relocation).
+func literalImm(s string) (int64, bool) {
+	x, err := parseImm(s)
+	return x, err == nil
+}
+
+// checkImmARM64 returns an error if an integer operand of a data-processing
+// instruction cannot be encoded.  The operand is shifted left by shift.
+func checkImmARM64(mnemonic, s string, shift uint, w bool) error {
+	x, ok := literalImm(s)
+	if !ok {
+		return nil // Relocation.
+	}
+
+	size := int64(64)
+	if w {
+		size = 32
+	}
+
+	var valid bool
+	switch mnemonic {
+	case "add", "sub", "cmp":
+		if x < 0 {
+			x = -x // Encoded as the opposite operation.
+		}
+		switch shift {
+		case 0:
+			valid = x >= 0 && (x <= 0xfff || (x&0xfff == 0 && x>>12 <= 0xfff))
+		case 12:
+			valid = x >= 0 && x <= 0xfff
+		}
+
+	case "and", "orr", "eor", "tst":
+		valid = shift == 0 && bitmaskImmARM64(uint64(x), w)
+
+	case "lsl", "lsr", "asr":
+		valid = shift == 0 && x >= 0 && x < size
+
+	case "mov":
+		valid = movImmARM64(uint64(x), w)
+
+	case "movz", "movn", "movk":
+		valid = x >= 0 && x <= 0xffff && shift%16 == 0 && int64(shift) < size
+
+	case "tbz", "tbnz":
+		valid = x >= 0 && x < size
+	}
+
+	if !valid {
+		return fmt.Errorf("immediate operand cannot be encoded: %s", s)
+	}
+	return nil
+}
+
+// bitmaskImmARM64 reports if x is a logical immediate: a repeating element of
+// 2, 4, 8, 16, 32 or 64 bits which contains a rotated run of ones.
+func bitmaskImmARM64(x uint64, w bool) bool {
+	if w {
+		if hi := x >> 32; hi != 0 && hi != 0xffffffff {
+			return false
+		}
+		lo := uint64(uint32(x))
+		x = lo | lo<<32
+	}
+	if x == 0 || x == ^uint64(0) {
+		return false
+	}
+
+	size := uint(64)
+	for size > 2 {
+		half := size / 2
+		mask := uint64(1)<<half - 1
+		if x&mask != x>>half&mask {
+			break
+		}
+		size = half
+	}
+
+	mask := ^uint64(0) >> (64 - size)
+	v := x & mask
+	rotated := (v>>1 | v<<(size-1)) & mask
+	return bits.OnesCount64(v^rotated) == 2
+}
+
+// movImmARM64 reports if x can be moved with a single movz, movn or orr
+// instruction.
+func movImmARM64(x uint64, w bool) bool {
+	mask := ^uint64(0)
+	if w {
+		if hi := x >> 32; hi != 0 && hi != 0xffffffff {
+			return false
+		}
+		mask = 0xffffffff
+	}
+
+	for _, v := range []uint64{x & mask, ^x & mask} {
+		for shift := uint(0); shift < 64; shift += 16 {
+			if v&^(0xffff<<shift) == 0 {
+				return true
+			}
+		}
+	}
+
+	return bitmaskImmARM64(x, w)
+}
+
+func (c *ARM64) compare(x, y uint64, w bool) {
+	if w {
+		x <<= 32
+		y <<= 32
+	}
+	r := x - y
+	c.N = int64(r) < 0
+	c.Z = r == 0
+	c.C = x >= y
+	c.V = ((x^y)&(x^r))>>63 != 0
+}
+
+func (c *ARM64) cond(s string) (bool, error) {
+	switch s {
+	case "eq":
+		return c.Z, nil
+	case "ne":
+		return !c.Z, nil
+	case "cs", "hs":
+		return c.C, nil
+	case "cc", "lo":
+		return !c.C, nil
+	case "mi":
+		return c.N, nil
+	case "pl":
+		return !c.N, nil
+	case "vs":
+		return c.V, nil
+	case "vc":
+		return !c.V, nil
+	case "hi":
+		return c.C && !c.Z, nil
+	case "ls":
+		return !c.C || c.Z, nil
+	case "ge":
+		return c.N == c.V, nil
+	case "lt":
+		return c.N != c.V, nil
+	case "gt":
+		return !c.Z && c.N == c.V, nil
+	case "le":
+		return c.Z || c.N != c.V, nil
+	case "al":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown condition: %s", s)
+}
+
+func (c *ARM64) branch(name string) error {
+	addr, err := c.symbol(c.prog, name)
+	if err != nil {
+		return err
+	}
+	c.PC = addr
+	return nil
+}
+
+func memSizeARM64(mnemonic string, r regARM64) int {
+	switch {
+	case strings.HasSuffix(mnemonic, "b"):
+		return 1
+	case strings.HasSuffix(mnemonic, "h"):
+		return 2
+	case strings.HasSuffix(mnemonic, "sw"):
+		return 4
+	case r.w:
+		return 4
+	default:
+		return 8
+	}
+}
+
+func (c *ARM64) step(x *insn) (err error) {
+	ops := x.operands
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid operands: %v", r)
+		}
+	}()
+
+	switch x.mnemonic {
+	case "nop", "dsb", "isb":
+
+	case "brk":
+		return errors.New("breakpoint (unreachable code)")
+
+	case "svc":
+		var args [6]uint64
+		copy(args[:], c.X[:6])
+		result, exit := c.syscall(int(c.X[8]), args)
+		c.X[0] = result
+		if exit {
+			return ErrExit
+		}
+
+	case "mov":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		v, err := c.value(ops[1])
+		if err != nil {
+			if v, err = c.symbol(c.prog, ops[1]); err != nil {
+				return err
+			}
+		} else if _, ok := parseRegARM64(ops[1]); !ok {
+			if err := checkImmARM64(x.mnemonic, ops[1], 0, d.w); err != nil {
+				return err
+			}
+		}
+		c.set(d, v)
+
+	case "movz", "movn", "movk":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		imm, err := parseImm(ops[1])
+		if err != nil {
+			return err
+		}
+		shift, err := c.shiftAmount(ops, 2)
+		if err != nil {
+			return err
+		}
+		if err := checkImmARM64(x.mnemonic, ops[1], shift, d.w); err != nil {
+			return err
+		}
+		v := uint64(imm) << shift
+		switch x.mnemonic {
+		case "movn":
+			v = ^v
+		case "movk":
+			v |= c.get(d) &^ (0xffff << shift)
+		}
+		c.set(d, v)
+
+	case "fmov":
+		if d, ok := parseRegARM64(ops[0]); ok {
+			s, ok := parseFloatRegARM64(ops[1])
+			if !ok {
+				return fmt.Errorf("invalid operand: %s", ops[1])
+			}
+			c.set(d, c.D[s])
+		} else if d, ok := parseFloatRegARM64(ops[0]); ok {
+			s, err := c.reg(ops[1])
+			if err != nil {
+				return err
+			}
+			c.D[d] = c.get(s)
+		} else {
+			return fmt.Errorf("invalid operand: %s", ops[0])
+		}
+
+	case "add", "sub", "mul", "and", "orr", "eor", "lsl", "lsr", "asr":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		n, err := c.reg(ops[1])
+		if err != nil {
+			return err
+		}
+		y, err := c.value(ops[2])
+		if err != nil {
+			return err
+		}
+		shift, err := c.shiftAmount(ops, 3)
+		if err != nil {
+			return err
+		}
+		if _, ok := parseRegARM64(ops[2]); ok {
+			if shift >= 64 || (d.w && shift >= 32) {
+				return fmt.Errorf("shift cannot be encoded: lsl %d", shift)
+			}
+		} else if err := checkImmARM64(x.mnemonic, ops[2], shift, d.w); err != nil {
+			return err
+		}
+		y <<= shift
+		v := c.get(n)
+		switch x.mnemonic {
+		case "add":
+			v += y
+		case "sub":
+			v -= y
+		case "mul":
+			v *= y
+		case "and":
+			v &= y
+		case "orr":
+			v |= y
+		case "eor":
+			v ^= y
+		case "lsl":
+			v <<= y & 63
+		case "lsr":
+			v >>= y & 63
+		case "asr":
+			if d.w {
+				v = uint64(int32(v) >> (y & 31))
+			} else {
+				v = uint64(int64(v) >> (y & 63))
+			}
+		}
+		c.set(d, v)
+
+	case "cmp", "tst":
+		n, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		y, err := c.value(ops[1])
+		if err != nil {
+			return err
+		}
+		if _, ok := parseRegARM64(ops[1]); !ok {
+			if err := checkImmARM64(x.mnemonic, ops[1], 0, n.w); err != nil {
+				return err
+			}
+		}
+		if x.mnemonic == "cmp" {
+			c.compare(c.get(n), y, n.w)
+		} else {
+			r := c.get(n) & y
+			if n.w {
+				r <<= 32
+			}
+			c.N = int64(r) < 0
+			c.Z = r == 0
+			c.C = false
+			c.V = false
+		}
+
+	case "ldr", "ldur", "ldrb", "ldurb", "ldrh", "ldurh", "ldrsw":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		size := memSizeARM64(x.mnemonic, d)
+		addr, writeback, err := c.address(x.mnemonic, size, ops[1:])
+		if err != nil {
+			return err
+		}
+		v, err := c.Load(addr, size)
+		if err != nil {
+			return err
+		}
+		if x.mnemonic == "ldrsw" {
+			v = uint64(int64(int32(v)))
+		}
+		if writeback != nil {
+			writeback()
+		}
+		c.set(d, v)
+
+	case "str", "stur", "strb", "sturb", "strh", "sturh":
+		s, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		size := memSizeARM64(x.mnemonic, s)
+		addr, writeback, err := c.address(x.mnemonic, size, ops[1:])
+		if err != nil {
+			return err
+		}
+		if err := c.Store(addr, size, c.get(s)); err != nil {
+			return err
+		}
+		if writeback != nil {
+			writeback()
+		}
+
+	case "ldp", "stp":
+		r1, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		r2, err := c.reg(ops[1])
+		if err != nil {
+			return err
+		}
+		size := memSizeARM64(x.mnemonic, r1)
+		addr, writeback, err := c.address(x.mnemonic, size, ops[2:])
+		if err != nil {
+			return err
+		}
+		if x.mnemonic == "stp" {
+			if err := c.Store(addr, size, c.get(r1)); err != nil {
+				return err
+			}
+			if err := c.Store(addr+uint64(size), size, c.get(r2)); err != nil {
+				return err
+			}
+		} else {
+			v1, err := c.Load(addr, size)
+			if err != nil {
+				return err
+			}
+			v2, err := c.Load(addr+uint64(size), size)
+			if err != nil {
+				return err
+			}
+			c.set(r1, v1)
+			c.set(r2, v2)
+		}
+		if writeback != nil {
+			writeback()
+		}
+
+	case "mrs":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		if ops[1] != "tpidr_el0" {
+			return fmt.Errorf("unsupported system register: %s", ops[1])
+		}
+		c.set(d, c.ThreadPointer)
+
+	case "adr":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		addr, err := c.symbol(c.prog, ops[1])
+		if err != nil {
+			return err
+		}
+		c.set(d, addr)
+
+	case "adrp":
+		d, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		var addr uint64
+		if strings.HasPrefix(ops[1], ":got:") {
+			addr, err = c.gotEntry(c.prog, ops[1][5:])
+		} else if strings.HasPrefix(ops[1], ":gottprel:") {
+			addr, err = c.gotTLSEntry(c.prog, ops[1][10:])
+		} else {
+			addr, err = c.symbol(c.prog, ops[1])
+		}
+		if err != nil {
+			return err
+		}
+		c.set(d, addr&^0xfff)
+
+	case "b":
+		return c.branch(ops[0])
+
+	case "bl":
+		c.X[30] = c.PC
+		return c.branch(ops[0])
+
+	case "br", "blr":
+		r, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		target := c.get(r)
+		if x.mnemonic == "blr" {
+			c.X[30] = c.PC
+		}
+		c.PC = target
+
+	case "ret":
+		r := regARM64{n: 30}
+		if len(ops) > 0 {
+			if r, err = c.reg(ops[0]); err != nil {
+				return err
+			}
+		}
+		c.PC = c.get(r)
+
+	case "cbz", "cbnz":
+		r, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		if (c.get(r) == 0) == (x.mnemonic == "cbz") {
+			return c.branch(ops[1])
+		}
+
+	case "tbz", "tbnz":
+		r, err := c.reg(ops[0])
+		if err != nil {
+			return err
+		}
+		bit, err := parseImm(ops[1])
+		if err != nil {
+			return err
+		}
+		if err := checkImmARM64(x.mnemonic, ops[1], 0, r.w); err != nil {
+			return err
+		}
+		if (c.get(r)>>uint(bit)&1 == 0) == (x.mnemonic == "tbz") {
+			return c.branch(ops[2])
+		}
+
+	default:
+		if strings.HasPrefix(x.mnemonic, "b.") {
+			ok, err := c.cond(x.mnemonic[2:])
+			if err != nil {
+				return err
+			}
+			if ok {
+				return c.branch(ops[0])
+			}
+			return nil
+		}
+		return fmt.Errorf("unsupported instruction: %s", x.mnemonic)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package emu_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gate.computer/ga/emu"
+)
+
+var encodingTestsARM64 = []struct {
+	insn  string
+	valid bool
+}{
+	{"add x0, x0, 4095", true},
+	{"add x0, x0, 4096", true},
+	{"add x0, x0, 0xfff000", true},
+	{"add x0, x0, 1, lsl 12", true},
+	{"sub x0, x0, -4095", true},
+	{"cmp x0, -1", true},
+	{"cmp w0, 4095", true},
+	{"add x0, x0, 4097", false},
+	{"add x0, x0, 0x1000000", false},
+	{"add x0, x0, 4096, lsl 12", false},
+	{"cmp x0, 5000", false},
+
+	{"and x0, x0, 0xff", true},
+	{"and x0, x0, -8", true},
+	{"and w0, w0, 0xff00ff00", true},
+	{"and w0, w0, -8", true},
+	{"orr x0, x0, 0x5555555555555555", true},
+	{"eor x0, x0, 1", true},
+	{"tst x0, 0x8000000000000000", true},
+	{"and x0, x0, 5", false},
+	{"and x0, x0, 0", false},
+	{"orr x0, x0, -1", false},
+	{"and w0, w0, 0x100000000", false},
+	{"tst w0, 5", false},
+
+	{"lsl x0, x0, 63", true},
+	{"lsr w0, w0, 31", true},
+	{"asr x0, x0, 1", true},
+	{"lsl x0, x0, 64", false},
+	{"lsr w0, w0, 32", false},
+	{"add x0, x0, x1, lsl 63", true},
+	{"add x0, x0, x1, lsl 64", false},
+	{"mul x0, x0, x1", true},
+	{"mul x0, x0, 2", false},
+
+	{"mov x0, 65535", true},
+	{"mov x0, 0x12340000", true},
+	{"mov x0, -1", true},
+	{"mov x0, 0xffff0000ffff0000", true},
+	{"mov w0, 0xffff0000", true},
+	{"mov x0, 12345678", false},
+	{"mov w0, 0x100000000", false},
+	{"movz x0, 65535, lsl 48", true},
+	{"movk w0, 1, lsl 16", true},
+	{"movn x0, 0", true},
+	{"movz x0, 65536", false},
+	{"movk x0, 1, lsl 8", false},
+	{"movz w0, 1, lsl 32", false},
+	{"movn x0, -1", false},
+
+	{"ldr x0, [sp, 32760]", true},
+	{"ldr x0, [sp, -256]", true},
+	{"ldr x0, [sp, 255]", true},
+	{"ldr w0, [sp, 2]", true},
+	{"ldr w0, [sp, 16380]", true},
+	{"ldrb w0, [sp, 4095]", true},
+	{"ldurb w0, [sp, -256]", true},
+	{"str x0, [sp, -16]!", true},
+	{"ldr x0, [sp], 255", true},
+	{"stp x0, x1, [sp, -512]!", true},
+	{"ldp x0, x1, [sp, 504]", true},
+	{"ldr x0, [sp, 32768]", false},
+	{"ldr x0, [sp, 260]", false},
+	{"ldrb w0, [sp, 4096]", false},
+	{"ldurb w0, [sp, 256]", false},
+	{"str x0, [sp, -264]!", false},
+	{"ldr x0, [sp], 256", false},
+	{"stp x0, x1, [sp, -520]!", false},
+	{"ldp x0, x1, [sp, 4]", false},
+
+	{"tbz x0, 63, l", true},
+	{"tbnz w0, 31, l", true},
+	{"tbz x0, 64, l", false},
+	{"tbnz w0, 32, l", false},
+}
+
+func TestEncodingARM64(t *testing.T) {
+	for _, test := range encodingTestsARM64 {
+		c, err := emu.NewARM64(test.insn + "\nl:\n\tret\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.Run("")
+		if invalid := err != nil && strings.Contains(err.Error(), "cannot be encoded"); invalid == test.valid {
+			t.Errorf("%s: %v", test.insn, err)
+		}
+	}
+}
+
+func TestMisalignedRelocationARM64(t *testing.T) {
+	for insn, valid := range map[string]bool{
+		"ldr x1, [x0, :lo12:aligned]":     true,
+		"ldrb w1, [x0, :lo12:misaligned]": true,
+		"ldr x1, [x0, :lo12:misaligned]":  false,
+	} {
+		c, err := emu.NewARM64(`
+	adrp x0, misaligned
+	` + insn + `
+	ret
+	.section .data
+aligned:
+	.byte 1
+misaligned:
+	.8byte 2
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var fault *emu.Fault
+		if err := c.Run(""); (err == nil) != valid || (err != nil && (!errors.As(err, &fault) || fault.Line != 3)) {
+			t.Errorf("%s: %v", insn, err)
+		}
+	}
+}
+
+func TestUnsupportedARM64(t *testing.T) {
+	for _, insn := range []string{
+		"foo x0",
+		"mrs x0, nzcv",
+		"b.xx l",
+		"add x0, x0, x1, ror 2",
+		"ldr x0, x1",
+	} {
+		c, err := emu.NewARM64(insn + "\nl:\n\tret\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var fault *emu.Fault
+		if err := c.Run(""); !errors.As(err, &fault) || fault.Line != 1 || fault.Insn != insn {
+			t.Errorf("%s: %v", insn, err)
+		}
+	}
+}
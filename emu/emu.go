@@ -0,0 +1,576 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package emu interprets assembly source generated by gate.computer/ga, so
+// that the output of a backend can be checked on a host of a different CPU
+// architecture.
+//
+// It works at the text level: the source is never assembled, and machine code
+// is neither produced nor decoded.  It checks what the generated instructions
+// do, not how they are encoded.  Only the instruction subset emitted by the ga
+// backends is supported.  Integer operands which the instruction cannot encode
+// are reported as faults, like an assembler would reject them, but other
+// assembler-level errors go unnoticed.  Instruction addresses are synthetic:
+// each instruction occupies 4 bytes starting at CodeAddr, and code is not
+// readable as data.  Data sections (except thread-local ones) are laid out one
+// after another at DataAddr.
+package emu
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Default memory layout.
+const (
+	CodeAddr   uint64 = 0x400000
+	StackAddr  uint64 = 0x7ff00000 // Lowest address of the stack.
+	StackSize         = 0x10000
+	ReturnAddr uint64 = 0xfffffffffffff000 // Returning here stops execution.
+	GOTAddr    uint64 = 0x7fe00000         // Global offset table entries.
+	DataAddr   uint64 = 0x7fc00000         // Data sections.
+)
+
+const pageSize = 4096
+
+// ErrExit is returned by Run when a syscall handler has requested exit.
+var ErrExit = errors.New("exit")
+
+// Fault during execution.
+type Fault struct {
+	Line int    // Source line number (1-based), or 0 if unknown.
+	Insn string // Source text of the instruction.
+	Err  error
+}
+
+func (f *Fault) Error() string {
+	if f.Line == 0 {
+		return f.Err.Error()
+	}
+	return fmt.Sprintf("line %d: %s: %v", f.Line, f.Insn, f.Err)
+}
+
+func (f *Fault) Unwrap() error {
+	return f.Err
+}
+
+type insn struct {
+	line     int
+	text     string
+	mnemonic string
+	operands []string
+}
+
+// dataItem is an integer in a data section.
+type dataItem struct {
+	line   int
+	offset int
+	size   int
+	expr   string
+}
+
+// Program is parsed assembly source.
+type Program struct {
+	insns      []insn
+	labels     map[string]int // Instruction index.
+	data       []dataItem
+	dataSize   int
+	dataLabels map[string]int // Offset from DataAddr.
+}
+
+// Parse assembly source.  Comments and directives other than section
+// switches and data definitions are ignored.
+func Parse(source string) (*Program, error) {
+	p := &Program{
+		labels:     make(map[string]int),
+		dataLabels: make(map[string]int),
+	}
+
+	var (
+		section  = ".text"
+		sections []string // Pushed.
+	)
+
+	for i, line := range strings.Split(source, "\n") {
+		if n := strings.Index(line, "//"); n >= 0 && !strings.Contains(line[:n], `"`) {
+			line = line[:n]
+		}
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, ".") {
+			var err error
+			switch fields := strings.Fields(line); fields[0] {
+			case ".section", ".pushsection":
+				if fields[0] == ".pushsection" {
+					sections = append(sections, section)
+				}
+				if len(fields) < 2 {
+					return nil, fmt.Errorf("line %d: section name missing", i+1)
+				}
+				section = strings.Split(fields[1], ",")[0]
+
+			case ".popsection":
+				if len(sections) == 0 {
+					return nil, fmt.Errorf("line %d: section stack is empty", i+1)
+				}
+				section = sections[len(sections)-1]
+				sections = sections[:len(sections)-1]
+
+			case ".text":
+				section = ".text"
+
+			default:
+				if dataSection(section) {
+					err = p.dataDirective(i+1, fields[0], strings.TrimSpace(line[len(fields[0]):]))
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			continue
+		}
+
+		if strings.HasSuffix(line, ":") {
+			name, err := unquote(line[:len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			_, dup := p.labels[name]
+			if _, found := p.dataLabels[name]; found {
+				dup = true
+			}
+			if dup {
+				return nil, fmt.Errorf("line %d: duplicate label: %s", i+1, name)
+			}
+			switch {
+			case section == ".text":
+				p.labels[name] = len(p.insns)
+			case dataSection(section):
+				p.dataLabels[name] = p.dataSize
+			}
+			continue
+		}
+
+		x := insn{
+			line:     i + 1,
+			text:     line,
+			mnemonic: line,
+		}
+		if n := strings.IndexAny(line, " \t"); n >= 0 {
+			x.mnemonic = line[:n]
+			x.operands = splitOperands(line[n+1:])
+		}
+		p.insns = append(p.insns, x)
+	}
+
+	return p, nil
+}
+
+// dataSection reports if a section is laid out at DataAddr.
+func dataSection(name string) bool {
+	switch {
+	case name == ".text", strings.HasPrefix(name, ".text."), strings.HasPrefix(name, ".note"):
+		return false
+	case name == ".tdata", name == ".tbss":
+		return false // Thread-local symbols are defined by Machine.Symbols.
+	default:
+		return true
+	}
+}
+
+// dataDirective lays out data.  Unknown directives are ignored.
+func (p *Program) dataDirective(line int, name, args string) error {
+	var size int
+	switch name {
+	case ".balign":
+		align, err := parseImm(args)
+		if err != nil {
+			return err
+		}
+		if align > 0 {
+			p.dataSize = (p.dataSize + int(align) - 1) &^ (int(align) - 1)
+		}
+		return nil
+
+	case ".zero":
+		n, err := parseImm(args)
+		if err != nil {
+			return err
+		}
+		p.dataSize += int(n)
+		return nil
+
+	case ".ascii":
+		s, err := strconv.Unquote(args)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(s); i++ {
+			p.data = append(p.data, dataItem{line, p.dataSize, 1, strconv.Itoa(int(s[i]))})
+			p.dataSize++
+		}
+		return nil
+
+	case ".byte":
+		size = 1
+	case ".4byte":
+		size = 4
+	case ".8byte":
+		size = 8
+	default:
+		return nil
+	}
+
+	for _, expr := range splitOperands(args) {
+		p.data = append(p.data, dataItem{line, p.dataSize, size, expr})
+		p.dataSize += size
+	}
+	return nil
+}
+
+// Symbol address, or false if the label is not defined.
+func (p *Program) Symbol(name string) (uint64, bool) {
+	if i, found := p.labels[name]; found {
+		return CodeAddr + uint64(i)*4, true
+	}
+	if offset, found := p.dataLabels[name]; found {
+		return DataAddr + uint64(offset), true
+	}
+	return 0, false
+}
+
+func (p *Program) index(addr uint64) (int, bool) {
+	if addr < CodeAddr || (addr-CodeAddr)%4 != 0 {
+		return 0, false
+	}
+	i := (addr - CodeAddr) / 4
+	if i >= uint64(len(p.insns)) {
+		return 0, false
+	}
+	return int(i), true
+}
+
+func splitOperands(s string) []string {
+	var (
+		operands []string
+		depth    int
+		quoted   bool
+		start    int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted:
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				quoted = false
+			}
+		case c == '"':
+			quoted = true
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			operands = append(operands, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(operands, strings.TrimSpace(s[start:]))
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		return strconv.Unquote(s)
+	}
+	return s, nil
+}
+
+func parseImm(s string) (int64, error) {
+	s = strings.TrimPrefix(s, "#")
+	if x, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return x, nil
+	}
+	x, err := strconv.ParseUint(s, 0, 64)
+	return int64(x), err
+}
+
+// Call record.
+type Call struct {
+	Nr     int
+	Args   [6]uint64
+	Result uint64
+}
+
+// SyscallHandler implements a system call.  If exit is true, execution stops
+// and Run returns ErrExit.
+type SyscallHandler func(m *Machine, nr int, args [6]uint64) (result uint64, exit bool)
+
+// Machine state which is independent of CPU architecture.
+type Machine struct {
+	Memory
+
+	// Symbols which are not defined by the program (used by MoveDef and
+	// references to external functions or data).
+	Symbols map[string]uint64
+
+	// Syscall implementation.  If nil, every syscall returns 0.
+	Syscall SyscallHandler
+
+	// Trace of syscalls made during execution.
+	Trace []Call
+
+	// Maximum number of instructions to execute.  Zero means no limit.
+	MaxSteps int
+
+	// Thread pointer (FS base on AMD64, TPIDR_EL0 on ARM64).  Offsets of
+	// thread-local symbols are relative to it.  On AMD64 the thread pointer
+	// itself is loaded from memory at its address, so the memory must be
+	// initialized like the thread control block.
+	ThreadPointer uint64
+
+	got map[string]uint64 // Entry addresses.
+}
+
+// gotEntry address of a symbol.  Entries are allocated on demand.  PLT is not
+// emulated: calls via PLT go directly to the symbol.
+func (m *Machine) gotEntry(p *Program, operand string) (uint64, error) {
+	return m.gotSlot(operand, func() (uint64, error) {
+		return m.symbol(p, operand)
+	})
+}
+
+// gotTLSEntry address of a thread-local symbol's offset.
+func (m *Machine) gotTLSEntry(p *Program, operand string) (uint64, error) {
+	return m.gotSlot(operand+"@tprel", func() (uint64, error) {
+		return m.tlsOffset(p, operand)
+	})
+}
+
+func (m *Machine) gotSlot(key string, value func() (uint64, error)) (uint64, error) {
+	if entry, found := m.got[key]; found {
+		return entry, nil
+	}
+
+	x, err := value()
+	if err != nil {
+		return 0, err
+	}
+
+	if m.got == nil {
+		m.got = make(map[string]uint64)
+	}
+	entry := GOTAddr + uint64(len(m.got))*8
+	m.got[key] = entry
+	m.Map(entry, 8)
+	if err := m.Store(entry, 8, x); err != nil {
+		return 0, err
+	}
+	return entry, nil
+}
+
+// tlsOffset of a thread-local symbol from the thread pointer.  The operand
+// may have a constant offset.
+func (m *Machine) tlsOffset(p *Program, operand string) (uint64, error) {
+	addr, err := m.symbol(p, operand)
+	return addr - m.ThreadPointer, err
+}
+
+// symbol address.  The operand may have a constant offset ("name"+8).
+func (m *Machine) symbol(p *Program, operand string) (uint64, error) {
+	operand = strings.TrimSuffix(strings.TrimSuffix(operand, "@PLT"), "@plt")
+
+	var offset int64
+	if i := strings.LastIndexAny(operand, "+-"); i > 0 && i > strings.LastIndex(operand, `"`) {
+		x, err := parseImm(operand[i:])
+		if err != nil {
+			return 0, err
+		}
+		operand = operand[:i]
+		offset = x
+	}
+
+	name, err := unquote(operand)
+	if err != nil {
+		return 0, err
+	}
+	if addr, found := p.Symbol(name); found {
+		return addr + uint64(offset), nil
+	}
+	if addr, found := m.Symbols[name]; found {
+		return addr + uint64(offset), nil
+	}
+	return 0, fmt.Errorf("undefined symbol: %s", name)
+}
+
+// loadData maps the data sections and evaluates their contents.  An
+// expression may be an integer, a symbol, or a difference of two symbols.
+func (m *Machine) loadData(p *Program) error {
+	if p.dataSize == 0 {
+		return nil
+	}
+	m.Map(DataAddr, p.dataSize)
+
+	for _, x := range p.data {
+		var (
+			value uint64
+			err   error
+		)
+		if i := strings.Index(x.expr, " - "); i > 0 {
+			var minuend, subtrahend uint64
+			if minuend, err = m.symbol(p, x.expr[:i]); err == nil {
+				subtrahend, err = m.symbol(p, strings.TrimSpace(x.expr[i+3:]))
+				value = minuend - subtrahend
+			}
+		} else if n, e := parseImm(x.expr); e == nil {
+			value = uint64(n)
+		} else {
+			value, err = m.symbol(p, x.expr)
+		}
+		if err == nil {
+			err = m.Store(DataAddr+uint64(x.offset), x.size, value)
+		}
+		if err != nil {
+			return &Fault{Line: x.line, Insn: x.expr, Err: err}
+		}
+	}
+	return nil
+}
+
+func (m *Machine) syscall(nr int, args [6]uint64) (uint64, bool) {
+	var (
+		result uint64
+		exit   bool
+	)
+	if m.Syscall != nil {
+		result, exit = m.Syscall(m, nr, args)
+	}
+	m.Trace = append(m.Trace, Call{nr, args, result})
+	return result, exit
+}
+
+// Memory is sparse and page-granular.  Accessing unmapped memory is an error.
+type Memory struct {
+	pages map[uint64]*[pageSize]byte
+}
+
+// Map zeroed memory.  Already mapped pages retain their contents.
+func (m *Memory) Map(addr uint64, size int) {
+	if m.pages == nil {
+		m.pages = make(map[uint64]*[pageSize]byte)
+	}
+	for p := addr &^ (pageSize - 1); p < addr+uint64(size); p += pageSize {
+		if m.pages[p] == nil {
+			m.pages[p] = new([pageSize]byte)
+		}
+	}
+}
+
+// Read memory into b.
+func (m *Memory) Read(addr uint64, b []byte) error {
+	for i := range b {
+		a := addr + uint64(i)
+		p := m.pages[a&^(pageSize-1)]
+		if p == nil {
+			return fmt.Errorf("read from unmapped address 0x%x", a)
+		}
+		b[i] = p[a&(pageSize-1)]
+	}
+	return nil
+}
+
+// Write b to memory.
+func (m *Memory) Write(addr uint64, b []byte) error {
+	for i, x := range b {
+		a := addr + uint64(i)
+		p := m.pages[a&^(pageSize-1)]
+		if p == nil {
+			return fmt.Errorf("write to unmapped address 0x%x", a)
+		}
+		p[a&(pageSize-1)] = x
+	}
+	return nil
+}
+
+// Load little-endian integer of 1, 2, 4 or 8 bytes.
+func (m *Memory) Load(addr uint64, size int) (uint64, error) {
+	b := make([]byte, size)
+	if err := m.Read(addr, b); err != nil {
+		return 0, err
+	}
+	var x uint64
+	for i := size - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x, nil
+}
+
+// Store little-endian integer of 1, 2, 4 or 8 bytes.
+func (m *Memory) Store(addr uint64, size int, value uint64) error {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(value >> (8 * uint(i)))
+	}
+	return m.Write(addr, b)
+}
+
+// cpu is implemented by the architecture-specific interpreters.
+type cpu interface {
+	machine() *Machine
+	program() *Program
+	pc() *uint64
+	step(x *insn) error
+}
+
+func run(c cpu) error {
+	var (
+		m     = c.machine()
+		p     = c.program()
+		pc    = c.pc()
+		steps int
+	)
+
+	for *pc != ReturnAddr {
+		if m.MaxSteps > 0 && steps >= m.MaxSteps {
+			return fmt.Errorf("step limit %d exceeded", m.MaxSteps)
+		}
+		steps++
+
+		i, ok := p.index(*pc)
+		if !ok {
+			return &Fault{Err: fmt.Errorf("jump to invalid address 0x%x", *pc)}
+		}
+		x := &p.insns[i]
+		*pc += 4
+
+		if err := c.step(x); err != nil {
+			if err == ErrExit {
+				return err
+			}
+			return &Fault{x.line, x.text, err}
+		}
+	}
+
+	return nil
+}
+
+func entry(p *Program, name string) (uint64, error) {
+	if name == "" {
+		return CodeAddr, nil
+	}
+	addr, found := p.Symbol(name)
+	if !found {
+		return 0, fmt.Errorf("undefined entry symbol: %s", name)
+	}
+	return addr, nil
+}
@@ -0,0 +1,940 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package emu_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/emu"
+	"gate.computer/ga/linux"
+)
+
+var (
+	sys  = ga.Linux()
+	r0   = ga.Reg{AMD64: ga.RBX, ARM64: ga.X19, RISCV64: ga.S1, PPC64LE: ga.GPR14, Use: "r0"}
+	r1   = ga.Reg{AMD64: ga.R12, ARM64: ga.X20, RISCV64: ga.S2, PPC64LE: ga.GPR15, Use: "r1"}
+	r2   = ga.Reg{AMD64: ga.R13, ARM64: ga.X21, RISCV64: ga.S3, PPC64LE: ga.GPR16, Use: "r2"}
+	temp = ga.Reg{AMD64: ga.R14, ARM64: ga.X22, RISCV64: ga.S4, PPC64LE: ga.GPR17, Use: "temp"}
+)
+
+// Addresses of memory which is mapped by Machine.Map in tests.
+const (
+	externAddr uint64 = 0x20000000
+	threadAddr uint64 = 0x30000000
+)
+
+// machine is implemented by the interpreters.
+type machine interface {
+	Reg(int) uint64
+	SetReg(int, uint64)
+	Run(entry string) error
+	Program() *emu.Program
+}
+
+var arches = []struct {
+	name   string
+	arch   ga.Arch
+	triple string
+	num    func(ga.Reg) int
+	new    func(string) (machine, *emu.Machine, error)
+}{
+	{"amd64", ga.AMD64, "x86_64", func(r ga.Reg) int { return int(r.AMD64) }, func(s string) (machine, *emu.Machine, error) {
+		c, err := emu.NewAMD64(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, &c.Machine, nil
+	}},
+	{"arm64", ga.ARM64, "aarch64", func(r ga.Reg) int { return int(r.ARM64) }, func(s string) (machine, *emu.Machine, error) {
+		c, err := emu.NewARM64(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, &c.Machine, nil
+	}},
+}
+
+// Registers checked by instruction tests, indexed by usage.
+var regs = map[string]ga.Reg{
+	r0.Use: r0,
+	r1.Use: r1,
+	r2.Use: r2,
+}
+
+func assemble(t *testing.T, arch ga.Arch, gen func(*ga.Assembly), opts ...ga.Option) string {
+	t.Helper()
+
+	a := ga.NewAssembly(arch, sys, opts...)
+	gen(a)
+	return a.String()
+}
+
+// assembleCheck verifies that an assembler accepts the source, so that the
+// interpreted text is known to encode.  It does nothing if llvm-mc is not
+// installed.
+func assembleCheck(t *testing.T, triple, source string) {
+	t.Helper()
+
+	mc, err := exec.LookPath("llvm-mc")
+	if err != nil {
+		return
+	}
+
+	cmd := exec.Command(mc, "-triple="+triple, "-filetype=obj", "-o", os.DevNull)
+	cmd.Stdin = strings.NewReader(source)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("llvm-mc: %v\n%s\n%s", err, out, source)
+	}
+}
+
+// data section with a word at "data", a 4-byte value at "data"+8 and a byte
+// at "data"+12.
+func data(a *ga.Assembly) {
+	a.Section(ga.DataSection)
+	a.DataSymbol("data", 8)
+	a.DataWord8(0x1122334455667788)
+	a.DataWord4(0x99aabbcc)
+	a.DataBytes([]byte{0xdd, 0, 0, 0})
+	a.DataZero(16)
+	a.Section(ga.TextSection)
+}
+
+// thread-local data is accessed at threadAddr+16 via symbol "tls".
+func threadLocal(m *emu.Machine) {
+	m.ThreadPointer = threadAddr
+	m.Map(threadAddr, 64)
+	m.Store(threadAddr, 8, threadAddr) // Thread control block on AMD64.
+	m.Store(threadAddr+16, 8, 0x0123456789abcdef)
+	m.Symbols["tls"] = threadAddr + 16
+}
+
+// scratch points r0 to unused stack memory.
+func scratch(a *ga.Assembly) {
+	a.MoveReg(r0, sys.StackPtr)
+	a.SubtractImm(r0, 256)
+}
+
+var instructionTests = []struct {
+	name  string
+	opts  []ga.Option
+	setup func(*emu.Machine)
+	gen   func(*ga.Assembly)
+	want  map[string]uint64 // By register usage.
+}{
+	{
+		name: "MoveImm",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 0)
+			a.MoveImm(r1, 0x12345678)
+			a.MoveImm(r2, 65535)
+		},
+		want: map[string]uint64{"r0": 0, "r1": 0x12345678, "r2": 65535},
+	},
+	{
+		name: "MoveImmNegative",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, -1)
+			a.MoveImm(r1, -0x12345678)
+			a.MoveImm(r2, -65536)
+		},
+		want: map[string]uint64{"r0": 0xffffffffffffffff, "r1": 0xffffffffedcba988, "r2": 0xffffffffffff0000},
+	},
+	{
+		name: "MoveImm64",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm64(r0, 0xfedcba9876543210)
+			a.MoveImm64(r1, 0xffffffff00000000)
+			a.MoveImm64(r2, 0xffff123400005678)
+		},
+		want: map[string]uint64{"r0": 0xfedcba9876543210, "r1": 0xffffffff00000000, "r2": 0xffff123400005678},
+	},
+	{
+		name: "MoveReg",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm64(r0, 0x100000001)
+			a.MoveReg(r1, r0)
+			a.MoveReg(r2, r1)
+		},
+		want: map[string]uint64{"r0": 0x100000001, "r1": 0x100000001, "r2": 0x100000001},
+	},
+	{
+		name: "Arithmetic",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 1000)
+			a.AddImm(r1, r0, 4095)
+			a.AddImm(r0, r0, -1)
+			a.AddReg(r2, r0, r1)
+			a.SubtractImm(r2, 4095)
+			a.SubtractReg(r1, r0)
+		},
+		want: map[string]uint64{"r0": 999, "r1": 4096, "r2": 1999},
+	},
+	{
+		name: "MultiplyImm",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 123)
+			a.MultiplyImm(r1, r0, 1000, temp)
+			a.MultiplyImm(r2, r0, -2, temp)
+		},
+		want: map[string]uint64{"r0": 123, "r1": 123000, "r2": 0xffffffffffffff0a},
+	},
+	{
+		name: "Logic",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 0x1234)
+			a.MoveReg(r1, r0)
+			a.MoveReg(r2, r0)
+			a.AndImm(r0, 0xff)
+			a.OrImm(r1, 0xf0000)
+			a.AndReg(r2, r1)
+			a.OrReg(r2, r0)
+		},
+		want: map[string]uint64{"r0": 0x34, "r1": 0xf1234, "r2": 0x1234},
+	},
+	{
+		name: "Shift",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 0x1234)
+			a.MoveImm64(r1, 0x8000000000000000)
+			a.MoveReg(r2, r1)
+			a.ShiftImm(ga.Left, r0, 60)
+			a.ShiftImm(ga.RightLogical, r1, 63)
+			a.ShiftImm(ga.RightArithmetic, r2, 60)
+		},
+		want: map[string]uint64{"r0": 0x4000000000000000, "r1": 1, "r2": 0xfffffffffffffff8},
+	},
+	{
+		name: "LoadStore",
+		gen: func(a *ga.Assembly) {
+			scratch(a)
+			a.MoveImm64(r1, 0x1122334455667788)
+			a.Store(r0, 0, r1)
+			a.Store4Bytes(r0, 8, r1)
+			a.Load4Bytes(r2, r0, 8)
+			a.Load(r1, r0, 0)
+			a.Load(r0, r0, 4)
+		},
+		want: map[string]uint64{"r0": 0x55667788_11223344, "r1": 0x1122334455667788, "r2": 0x55667788},
+	},
+	{
+		name: "LoadByte",
+		gen: func(a *ga.Assembly) {
+			scratch(a)
+			a.MoveImm64(r1, 0x1122334455667788)
+			a.Store(r0, 0, r1)
+			a.LoadByte(r2, r0, 1)
+			a.LoadByte(r0, r0, 7)
+		},
+		want: map[string]uint64{"r0": 0x11, "r1": 0x1122334455667788, "r2": 0x77},
+	},
+	{
+		name: "Data",
+		gen: func(a *ga.Assembly) {
+			a.Section(ga.DataSection)
+			a.DataSymbol("data", 8)
+			a.DataWord8(0x1122334455667788)
+			a.DataWord4(0x99aabbcc)
+			a.DataString("\xdd\"")
+			a.Section(ga.TextSection)
+			a.Address(r0, "data")
+			a.Load(r1, r0, 0)
+			a.Load4Bytes(r2, r0, 8)
+			a.LoadByte(r0, r0, 13)
+		},
+		want: map[string]uint64{"r0": '"', "r1": 0x1122334455667788, "r2": 0x99aabbcc},
+	},
+	{
+		name: "Global",
+		gen: func(a *ga.Assembly) {
+			data(a)
+			a.LoadGlobal(r0, "data", 0)
+			a.LoadGlobal4Bytes(r1, "data", 8)
+			a.LoadGlobalByte(r2, "data", 12)
+			a.StoreGlobal("data", 16, r1, temp)
+			a.StoreGlobal4Bytes("data", 24, r2, temp)
+			a.LoadGlobal(r1, "data", 16)
+			a.LoadGlobal(r2, "data", 24)
+		},
+		want: map[string]uint64{"r0": 0x1122334455667788, "r1": 0x99aabbcc, "r2": 0xdd},
+	},
+	{
+		name: "GlobalFar",
+		opts: []ga.Option{ga.WithFarAddress()},
+		gen: func(a *ga.Assembly) {
+			data(a)
+			a.Address(r0, "data")
+			a.LoadGlobal(r1, "data", 8)
+			a.StoreGlobal("data", 16, r1, temp)
+			a.Load(r2, r0, 16)
+		},
+		want: map[string]uint64{"r0": emu.DataAddr, "r1": 0xdd99aabbcc, "r2": 0xdd99aabbcc},
+	},
+	{
+		name: "External",
+		opts: []ga.Option{ga.WithPIC(), ga.WithExternal("extern")},
+		setup: func(m *emu.Machine) {
+			m.Map(externAddr, 16)
+			m.Store(externAddr+8, 8, 42)
+			m.Symbols["extern"] = externAddr
+		},
+		gen: func(a *ga.Assembly) {
+			a.Address(r0, "extern")
+			a.LoadGlobal(r1, "extern", 8)
+			a.StoreGlobal("extern", 0, r1, temp)
+			a.Load(r2, r0, 0)
+		},
+		want: map[string]uint64{"r0": externAddr, "r1": 42, "r2": 42},
+	},
+	{
+		name:  "ThreadLocalInitialExec",
+		setup: threadLocal,
+		gen: func(a *ga.Assembly) {
+			a.LoadThreadPointer(r0)
+			a.ThreadLocalAddress(r1, "tls", ga.InitialExecTLS, temp)
+			a.LoadThreadLocal(r2, "tls", 0, ga.InitialExecTLS, temp)
+		},
+		want: map[string]uint64{"r0": threadAddr, "r1": threadAddr + 16, "r2": 0x0123456789abcdef},
+	},
+	{
+		name:  "ThreadLocalLocalExec",
+		setup: threadLocal,
+		gen: func(a *ga.Assembly) {
+			a.ThreadLocalAddress(r1, "tls", ga.LocalExecTLS, temp)
+			a.LoadThreadLocal(r2, "tls", 0, ga.LocalExecTLS, temp)
+		},
+		want: map[string]uint64{"r1": threadAddr + 16, "r2": 0x0123456789abcdef},
+	},
+	{
+		name: "PushPop",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 1)
+			a.MoveImm(r1, 2)
+			a.Push(r0)
+			a.Push(r1)
+			a.Pop(r0)
+			a.Pop(r2)
+		},
+		want: map[string]uint64{"r0": 2, "r1": 2, "r2": 1},
+	},
+	{
+		name: "PushPair",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 1)
+			a.MoveImm(r1, 2)
+			a.MoveImm(r2, 3)
+			a.Push(r0)
+			a.PushPair(r1, r2)
+			a.PopPair(r2, r0)
+			a.Pop(r1)
+		},
+		want: map[string]uint64{"r0": 3, "r1": 1, "r2": 2},
+	},
+	{
+		name: "Frame",
+		gen: func(a *ga.Assembly) {
+			f := new(ga.Frame)
+			f.Local("x", 8, 8)
+			f.Local("y", 8, 8)
+			a.MoveImm(r0, 5)
+			a.AllocateFrame(f)
+			a.StoreLocal(f, "y", r0)
+			a.Push(r0)
+			a.LoadLocal(r1, f, "y")
+			a.Pop(r0)
+			a.AddressOfLocal(r2, f, "x")
+			a.Load(r2, r2, 8)
+			a.FreeFrame(f)
+		},
+		want: map[string]uint64{"r0": 5, "r1": 5, "r2": 5},
+	},
+	{
+		name: "FramePtr",
+		gen: func(a *ga.Assembly) {
+			f := &ga.Frame{FramePtr: true}
+			f.Local("x", 4, 4)
+			f.Local("y", 1, 1)
+			a.MoveImm(r0, 0x1ff)
+			a.MoveImm(r2, 0)
+			a.AllocateFrame(f)
+			a.StoreLocal(f, "x", r0)
+			a.PushPair(r0, r2)
+			a.LoadLocal(r1, f, "x")
+			a.PopPair(r0, r2)
+			a.AddressOfLocal(r2, f, "x")
+			a.LoadByte(r2, r2, 1)
+			a.FreeFrame(f)
+		},
+		want: map[string]uint64{"r0": 0x1ff, "r1": 0x1ff, "r2": 1},
+	},
+	{
+		name: "CallFrameInfo",
+		opts: []ga.Option{ga.WithCallFrameInfo(), ga.WithFramePointer()},
+		gen: func(a *ga.Assembly) {
+			f := &ga.Frame{FramePtr: true}
+			f.Local("x", 8, 8)
+			a.MoveImm(r0, 5)
+			a.Push(r0)
+			a.AllocateFrame(f)
+			a.StoreLocal(f, "x", r0)
+			a.SubtractImm(sys.StackPtr, 32)
+			a.LoadLocal(r1, f, "x")
+			a.AddImm(sys.StackPtr, sys.StackPtr, 32)
+			a.FreeFrame(f)
+			a.Pop(r2)
+		},
+		want: map[string]uint64{"r0": 5, "r1": 5, "r2": 5},
+	},
+	{
+		name: "Call",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 1)
+			a.Call("f")
+			a.MoveImm(r2, 3)
+			a.Return()
+
+			a.Function("f")
+			a.MoveImm(r1, 2)
+		},
+		want: map[string]uint64{"r0": 1, "r1": 2, "r2": 3},
+	},
+	{
+		name: "CallFramePtr",
+		opts: []ga.Option{ga.WithFramePointer()},
+		gen: func(a *ga.Assembly) {
+			a.MoveReg(r0, sys.FramePtr)
+			a.Call("f")
+			a.Set(r1) // Result.
+			a.SubtractReg(r1, r0)
+			a.SubtractReg(r0, sys.FramePtr)
+			a.Return()
+
+			// Frame record is below the frame pointer on RISC-V.
+			a.Function("f")
+			offset := 0
+			if a.Arch == ga.RISCV64 {
+				offset = -16
+			}
+			a.Load(r1, sys.FramePtr, offset)
+		},
+		want: map[string]uint64{"r0": 0, "r1": 0},
+	},
+	{
+		name: "CallLib",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 40)
+			a.MoveImm(r1, 2)
+			a.CallLib("add", []ga.Reg{r0, r1}, []ga.Reg{r2})
+			a.Return()
+
+			a.FunctionWithoutPrologue("add")
+			a.Reset()
+			p0 := sys.LibParams[0]
+			p1 := sys.LibParams[1]
+			a.Set(p0)
+			a.Set(p1)
+			a.AddReg(sys.LibResult, p0, p1)
+			a.ReturnWithoutEpilogue()
+		},
+		want: map[string]uint64{"r0": 40, "r1": 2, "r2": 42},
+	},
+	{
+		name: "CallLibSpill",
+		gen: func(a *ga.Assembly) {
+			live := sys.LibParams[2].As("live")
+			a.MoveImm(live, 7)
+			a.MoveImm(r0, 1)
+			a.CallLib("swap", []ga.Reg{r0}, []ga.Reg{r1, r2})
+			a.AddReg(r2, r2, live)
+			a.Return()
+
+			a.FunctionWithoutPrologue("swap")
+			a.Reset(sys.LibParams[0])
+			a.MoveImm(sys.LibParams[2], 0) // Clobbered.
+			a.MoveReg(sys.LibResult2, sys.LibParams[0])
+			a.MoveImm(sys.LibResult, 2)
+			a.ReturnWithoutEpilogue()
+		},
+		want: map[string]uint64{"r0": 1, "r1": 2, "r2": 8},
+	},
+	{
+		name: "JumpIf",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 0)
+			a.SubtractImm(r0, 5)
+			a.MoveImm(r1, 0)
+			a.MoveImm(r2, 0)
+			for i, c := range []ga.Cond{ga.EQ, ga.NE, ga.LT, ga.LE, ga.GT, ga.GE} {
+				skip := "skip_imm_" + string(rune('a'+i))
+				a.JumpIfImm(c, r0, -4, skip)
+				a.OrImm(r1, 1<<uint(i))
+				a.Label(skip)
+			}
+			for i, c := range []ga.Cond{ga.EQ, ga.NE, ga.LT, ga.LE, ga.GT, ga.GE} {
+				skip := "skip_reg_" + string(rune('a'+i))
+				a.JumpIfReg(c, r0, r0, skip)
+				a.OrImm(r2, 1<<uint(i))
+				a.Label(skip)
+			}
+		},
+		want: map[string]uint64{"r0": 0xfffffffffffffffb, "r1": 0b110001, "r2": 0b010110},
+	},
+	{
+		name: "JumpIfBit",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm64(r0, 1<<40|1<<31)
+			a.MoveImm(r1, 0)
+			a.MoveImm(r2, 0)
+			a.JumpIfBitSet(r0, 40, "set")
+			a.MoveImm(r1, 1)
+			a.Label("set")
+			a.JumpIfBitNotSet(r0, 31, "clear")
+			a.MoveImm(r2, 1)
+			a.Label("clear")
+			a.Jump("end")
+			a.MoveImm(r0, 0)
+			a.Label("end")
+		},
+		want: map[string]uint64{"r0": 1<<40 | 1<<31, "r1": 0, "r2": 1},
+	},
+	{
+		name: "JumpRegRoutine",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 0)
+			a.MoveImm(r1, 0)
+			a.Address(r2, "target")
+			a.JumpRegRoutine(r2, "retpoline")
+			a.MoveImm(r1, 1) // Skipped.
+			a.Label("target")
+			a.MoveImm(r0, 2)
+		},
+		want: map[string]uint64{"r0": 2, "r1": 0},
+	},
+	{
+		name: "LabelScope",
+		gen: func(a *ga.Assembly) {
+			countDown := func(r ga.Reg) {
+				s := a.NewScope()
+				a.Label(s.Name("loop"))
+				a.AddImm(r1, r1, 1)
+				a.SubtractImm(r, 1)
+				a.JumpIfImm(ga.NE, r, 0, s.Name("loop"))
+			}
+			a.MoveImm(r1, 0)
+			a.MoveImm(r0, 3)
+			countDown(r0)
+			a.MoveImm(r0, 2)
+			countDown(r0)
+
+			for i := 0; i < 2; i++ {
+				target := a.NewLabel("target")
+				a.Address(r2, target)
+				a.JumpRegRoutine(r2, "retpoline")
+				a.MoveImm(r1, 0) // Skipped.
+				a.Label(target)
+			}
+		},
+		want: map[string]uint64{"r0": 0, "r1": 5},
+	},
+	{
+		name: "SwitchTable",
+		gen:  switchTable,
+		want: map[string]uint64{"r1": 0xff},
+	},
+	{
+		name: "SwitchTableMasked",
+		opts: []ga.Option{ga.WithIndexMasking()},
+		gen:  switchTable,
+		want: map[string]uint64{"r1": 0xff},
+	},
+	{
+		name: "SwitchTree",
+		gen: func(a *ga.Assembly) {
+			a.MoveImm(r0, 5000)
+			a.MoveImm(r1, 0)
+			a.Switch(r0, map[int]string{-3: "a", 100: "a", 5000: "b", 9999: "a"}, "a", temp)
+			a.Label("b")
+			a.MoveImm(r1, 1)
+			a.Label("a")
+		},
+		want: map[string]uint64{"r0": 5000, "r1": 1},
+	},
+	{
+		name: "MoveRegFloat",
+		gen: func(a *ga.Assembly) {
+			a.MoveRegFloat(r0, 3)
+		},
+		want: map[string]uint64{"r0": 0x400921fb54442d18},
+	},
+}
+
+// switchTable dispatches every value around a sparse set of cases, each of
+// which sets a distinct bit.
+func switchTable(a *ga.Assembly) {
+	a.MoveImm(r1, 0)
+	for value := -1; value < 17; value++ {
+		cases := make(map[int]string)
+		for i := 0; i < 8; i++ {
+			cases[i*2] = a.NewLabel("case")
+		}
+		end := a.NewLabel("end")
+
+		a.MoveImm(r0, value)
+		a.Switch(r0, cases, end, temp)
+		for i := 0; i < 8; i++ {
+			a.Label(cases[i*2])
+			a.AddImm(r1, r1, 1<<uint(i))
+			a.Jump(end)
+		}
+		a.Label(end)
+	}
+}
+
+func TestInstructions(t *testing.T) {
+	for _, x := range arches {
+		for _, test := range instructionTests {
+			t.Run(x.name+"/"+test.name, func(t *testing.T) {
+				source := assemble(t, x.arch, func(a *ga.Assembly) {
+					a.Function("test")
+					test.gen(a)
+					a.Return()
+				}, test.opts...)
+				assembleCheck(t, x.triple, source)
+
+				c, m, err := x.new(source)
+				if err != nil {
+					t.Fatal(err)
+				}
+				m.Symbols = make(map[string]uint64)
+				if test.setup != nil {
+					test.setup(m)
+				}
+				switch c := c.(type) {
+				case *emu.AMD64:
+					c.XMM[3] = 0x400921fb54442d18
+				case *emu.ARM64:
+					c.D[3] = 0x400921fb54442d18
+				}
+
+				if err := c.Run("test"); err != nil {
+					t.Fatalf("%v\n%s", err, source)
+				}
+
+				for use, want := range test.want {
+					if v := c.Reg(x.num(regs[use])); v != want {
+						t.Errorf("%s = 0x%x; want 0x%x\n%s", use, v, want, source)
+					}
+				}
+			})
+		}
+	}
+}
+
+func syscallNr(nr ga.Syscall, arch ga.Arch) int {
+	return arch.Specify(ga.Specific(nr))
+}
+
+func TestSyscall(t *testing.T) {
+	for _, x := range arches {
+		t.Run(x.name, func(t *testing.T) {
+			source := assemble(t, x.arch, func(a *ga.Assembly) {
+				a.MoveImm(sys.SysParams[0], 1)
+				for _, r := range sys.SysParams[1:] {
+					a.MoveImm(r, 2)
+				}
+				a.Syscall(linux.SYS_GETPID)
+				a.MoveReg(r0, sys.SysResult)
+				a.MoveImm(sys.SysParams[0], 7)
+				a.Syscall(linux.SYS_EXIT)
+				a.Unreachable()
+			})
+
+			c, m, err := x.new(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.Syscall = func(m *emu.Machine, nr int, args [6]uint64) (uint64, bool) {
+				switch nr {
+				case syscallNr(linux.SYS_GETPID, x.arch):
+					return 1234, false
+				case syscallNr(linux.SYS_EXIT, x.arch):
+					return 0, true
+				}
+				t.Errorf("unexpected syscall %d", nr)
+				return 0, false
+			}
+
+			if err := c.Run(""); err != emu.ErrExit {
+				t.Fatalf("error: %v", err)
+			}
+			if v := c.Reg(x.num(r0)); v != 1234 {
+				t.Errorf("result: %d", v)
+			}
+
+			want := []emu.Call{
+				{syscallNr(linux.SYS_GETPID, x.arch), [6]uint64{1, 2, 2, 2, 2, 2}, 1234},
+				{syscallNr(linux.SYS_EXIT, x.arch), [6]uint64{7, 2, 2, 2, 2, 2}, 0},
+			}
+			if len(m.Trace) != len(want) {
+				t.Fatalf("trace: %v", m.Trace)
+			}
+			for i, call := range m.Trace {
+				if call != want[i] {
+					t.Errorf("trace #%d: %v", i, call)
+				}
+			}
+		})
+	}
+}
+
+func TestSyscallDefault(t *testing.T) {
+	for _, x := range arches {
+		t.Run(x.name, func(t *testing.T) {
+			source := assemble(t, x.arch, func(a *ga.Assembly) {
+				a.Function("test")
+				a.Syscall(linux.SYS_GETPID)
+				a.MoveReg(r0, sys.SysResult)
+				a.Return()
+			})
+
+			c, m, err := x.new(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Run("test"); err != nil {
+				t.Fatal(err)
+			}
+			if v := c.Reg(x.num(r0)); v != 0 {
+				t.Errorf("result: %d", v)
+			}
+			if len(m.Trace) != 1 {
+				t.Errorf("trace: %v", m.Trace)
+			}
+		})
+	}
+}
+
+func TestEntry(t *testing.T) {
+	for _, x := range arches {
+		t.Run(x.name, func(t *testing.T) {
+			source := assemble(t, x.arch, func(a *ga.Assembly) {
+				a.Function("first")
+				a.MoveImm(r0, 1)
+				a.Return()
+				a.Function("second")
+				a.MoveImm(r0, 2)
+				a.Return()
+			})
+
+			c, _, err := x.new(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Run("second"); err != nil {
+				t.Fatal(err)
+			}
+			if v := c.Reg(x.num(r0)); v != 2 {
+				t.Errorf("result: %d", v)
+			}
+
+			addr, found := c.Program().Symbol("second")
+			if !found || addr <= emu.CodeAddr {
+				t.Errorf("symbol: 0x%x %v", addr, found)
+			}
+
+			if err := c.Run("third"); err == nil || !strings.Contains(err.Error(), "third") {
+				t.Errorf("undefined entry: %v", err)
+			}
+		})
+	}
+}
+
+// runError generates code and returns the source and the execution error.
+func runError(t *testing.T, arch ga.Arch, new func(string) (machine, *emu.Machine, error), gen func(*ga.Assembly), maxSteps int) (string, error) {
+	t.Helper()
+
+	source := assemble(t, arch, gen)
+	c, m, err := new(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.MaxSteps = maxSteps
+	return source, c.Run("")
+}
+
+func TestErrors(t *testing.T) {
+	for _, x := range arches {
+		t.Run(x.name, func(t *testing.T) {
+			var fault *emu.Fault
+
+			_, err := runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.MoveImm(r0, 0)
+				a.Load(r1, r0, 8)
+				a.Return()
+			}, 0)
+			if !errors.As(err, &fault) || fault.Line == 0 || !strings.Contains(err.Error(), "unmapped address 0x8") {
+				t.Errorf("unmapped memory: %v", err)
+			}
+
+			_, err = runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.Jump("nowhere")
+			}, 0)
+			if !errors.As(err, &fault) || !strings.Contains(err.Error(), "undefined symbol: nowhere") {
+				t.Errorf("undefined symbol: %v", err)
+			}
+
+			source, err := runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.MoveImm(r0, 1)
+				a.Unreachable()
+			}, 0)
+			if !errors.As(err, &fault) || fault.Line == 0 || strings.TrimSpace(strings.Split(source, "\n")[fault.Line-1]) != fault.Insn || !strings.Contains(err.Error(), "breakpoint") {
+				t.Errorf("unreachable: %v", err)
+			}
+
+			_, err = runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.Label("loop")
+				a.Jump("loop")
+			}, 100)
+			if err == nil || !strings.Contains(err.Error(), "step limit 100 exceeded") {
+				t.Errorf("step limit: %v", err)
+			}
+
+			_, err = runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.MoveImm(r0, 1)
+				a.JumpRegRoutine(r0, "retpoline")
+			}, 0)
+			if err == nil || !strings.Contains(err.Error(), "jump to invalid address 0x1") {
+				t.Errorf("invalid jump: %v", err)
+			}
+
+			_, err = runError(t, x.arch, x.new, func(a *ga.Assembly) {
+				a.MoveImm(r0, 0)
+				a.OrImm(r0, 5000000000)
+			}, 0)
+			if !errors.As(err, &fault) || !strings.Contains(err.Error(), "cannot be encoded") {
+				t.Errorf("unencodable immediate: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{
+		"x:\nx:\n",
+		".section .data\nx:\n.text\nx:\n",
+		".popsection\n",
+		".section\n",
+		".section .data\n.balign x\n",
+		".section .data\n.zero x\n",
+		".section .data\n.ascii x\n",
+		"\"x:\n",
+	} {
+		if _, err := emu.Parse(source); err == nil {
+			t.Errorf("no error: %q", source)
+		}
+	}
+}
+
+func TestData(t *testing.T) {
+	c, err := emu.NewARM64(`
+	ret
+	.section .rodata
+	.balign 8
+a:
+	.8byte b - a, 3
+b:
+	.4byte 1, 2
+	.byte 5
+	.pushsection .data
+	.ascii "x\\\"\377"
+	.popsection
+	.pushsection .tbss
+t:
+	.zero 8
+	.popsection
+	.balign 8
+c:
+	.8byte a
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(""); err != nil {
+		t.Fatal(err)
+	}
+
+	p := c.Program()
+	for name, addr := range map[string]uint64{
+		"a": emu.DataAddr,
+		"b": emu.DataAddr + 16,
+		"c": emu.DataAddr + 32,
+	} {
+		if x, found := p.Symbol(name); !found || x != addr {
+			t.Errorf("%s: 0x%x %v", name, x, found)
+		}
+	}
+
+	if _, found := p.Symbol("t"); found {
+		t.Error("thread-local symbol is laid out")
+	}
+
+	for _, x := range []struct {
+		offset uint64
+		size   int
+		value  uint64
+	}{
+		{0, 8, 16},
+		{8, 8, 3},
+		{16, 4, 1},
+		{20, 4, 2},
+		{24, 1, 5},
+		{25, 4, 0xff225c78},
+		{32, 8, emu.DataAddr},
+	} {
+		if v, err := c.Load(emu.DataAddr+x.offset, x.size); err != nil || v != x.value {
+			t.Errorf("data +%d: 0x%x %v", x.offset, v, err)
+		}
+	}
+
+	c, err = emu.NewARM64(".section .data\n.8byte undefined\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fault *emu.Fault
+	if err := c.Run(""); !errors.As(err, &fault) || fault.Line != 2 {
+		t.Errorf("undefined data symbol: %v", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	var m emu.Memory
+
+	if err := m.Store(0x1000, 8, 1); err == nil {
+		t.Error("store to unmapped memory")
+	}
+	if _, err := m.Load(0x1000, 8); err == nil {
+		t.Error("load from unmapped memory")
+	}
+
+	m.Map(0x1ffc, 8) // Two pages.
+	if err := m.Store(0x1ffc, 8, 0x1122334455667788); err != nil {
+		t.Fatal(err)
+	}
+	if x, err := m.Load(0x1ffc, 8); err != nil || x != 0x1122334455667788 {
+		t.Errorf("load: 0x%x %v", x, err)
+	}
+	if x, err := m.Load(0x2000, 1); err != nil || x != 0x44 {
+		t.Errorf("load byte: 0x%x %v", x, err)
+	}
+
+	m.Map(0x2000, 1) // Keeps contents.
+	if x, err := m.Load(0x2000, 2); err != nil || x != 0x3344 {
+		t.Errorf("load after map: 0x%x %v", x, err)
+	}
+}
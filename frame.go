@@ -0,0 +1,174 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+// Frame layout of named local variables.  Slots are declared with Local, and
+// the frame is allocated on the stack with Assembly.AllocateFrame.
+//
+// Locals are addressed relative to the stack pointer, taking the pushes and
+// pops done since the allocation into account.  If FramePtr is true, the
+// frame pointer is saved and set during allocation, and locals are addressed
+// relative to it instead.
+type Frame struct {
+	FramePtr bool
+
+	slots     map[string]frameSlot
+	size      int
+	allocated bool
+	depth     int      // Stack depth after allocation.
+	fpUse     string   // Usage of System.FramePtr before allocation.
+	prevCFI   cfiFrame // Call frame information before allocation.
+}
+
+type frameSlot struct {
+	offset int // From the lowest address of the frame.
+	size   int
+}
+
+// Local declares a named slot.  Alignment must be a power of two, at most 16.
+// It is relative to the stack pointer alignment at allocation time.
+func (f *Frame) Local(name string, size, align int) {
+	if f.allocated {
+		panic("frame is allocated")
+	}
+	if size <= 0 {
+		panic(fmt.Sprintf("invalid local %s size: %d", name, size))
+	}
+	if align <= 0 || align > 16 || align&(align-1) != 0 {
+		panic(fmt.Sprintf("invalid local %s alignment: %d", name, align))
+	}
+	if _, found := f.slots[name]; found {
+		panic(fmt.Sprintf("duplicate local: %s", name))
+	}
+	if f.slots == nil {
+		f.slots = make(map[string]frameSlot)
+	}
+
+	offset := (f.size + align - 1) &^ (align - 1)
+	f.slots[name] = frameSlot{offset, size}
+	f.size = offset + size
+}
+
+// Size of the frame, rounded up to a multiple of 16 bytes.
+func (f *Frame) Size() int {
+	return (f.size + 15) &^ 15
+}
+
+// Offset of a local from the lowest address of the frame.
+func (f *Frame) Offset(name string) int {
+	return f.slot(name).offset
+}
+
+func (f *Frame) slot(name string) frameSlot {
+	s, found := f.slots[name]
+	if !found {
+		panic(fmt.Sprintf("unknown local: %s", name))
+	}
+	return s
+}
+
+// AllocateFrame reserves stack space for the frame.
+func (a *Assembly) AllocateFrame(f *Frame) {
+	if f.allocated {
+		panic("frame is already allocated")
+	}
+
+	if f.FramePtr {
+		f.fpUse = a.regUsage[a.FramePtr.Num(a.Arch.ID())]
+		a.Set(a.FramePtr)
+		a.Push(a.FramePtr)
+		a.MoveReg(a.FramePtr, a.StackPtr)
+		f.prevCFI = a.cfiDefFramePtr()
+	}
+
+	a.SubtractImm(a.StackPtr, f.Size())
+	a.stack += f.Size()
+
+	f.allocated = true
+	f.depth = a.stack
+}
+
+// FreeFrame releases the stack space.  Registers pushed after AllocateFrame
+// must have been popped.
+func (a *Assembly) FreeFrame(f *Frame) {
+	a.checkFrame(f)
+	if a.stack != f.depth {
+		panic(fmt.Sprintf("stack depth %d differs from frame allocation depth %d", a.stack, f.depth))
+	}
+
+	if f.FramePtr {
+		a.MoveReg(a.StackPtr, a.FramePtr)
+		a.stack -= f.Size()
+		a.cfiDefStackPtr()
+		a.Pop(a.FramePtr.As(f.fpUse))
+		a.cfiResetFrame(f.prevCFI)
+	} else {
+		a.AddImm(a.StackPtr, a.StackPtr, f.Size())
+		a.stack -= f.Size()
+	}
+
+	f.allocated = false
+}
+
+// LoadLocal loads the value of an 8-, 4- or 1-byte local.  Smaller values are
+// zero-extended.
+func (a *Assembly) LoadLocal(dest Reg, f *Frame, name string) {
+	base, offset, size := a.local(f, name)
+	switch size {
+	case 8:
+		a.Load(dest, base, offset)
+	case 4:
+		a.Load4Bytes(dest, base, offset)
+	case 1:
+		a.LoadByte(dest, base, offset)
+	default:
+		panic(fmt.Sprintf("local %s size %d is not supported by LoadLocal", name, size))
+	}
+}
+
+// StoreLocal stores a value into an 8- or 4-byte local.
+func (a *Assembly) StoreLocal(f *Frame, name string, src Reg) {
+	base, offset, size := a.local(f, name)
+	switch size {
+	case 8:
+		a.Store(base, offset, src)
+	case 4:
+		a.Store4Bytes(base, offset, src)
+	default:
+		panic(fmt.Sprintf("local %s size %d is not supported by StoreLocal", name, size))
+	}
+}
+
+// AddressOfLocal computes the address of a local.
+func (a *Assembly) AddressOfLocal(dest Reg, f *Frame, name string) {
+	base, offset, _ := a.local(f, name)
+	a.AddImm(dest, base, offset)
+}
+
+// local returns base register, offset and size of a local.
+func (a *Assembly) local(f *Frame, name string) (base Reg, offset, size int) {
+	a.checkFrame(f)
+	s := f.slot(name)
+
+	if f.FramePtr {
+		// Frame pointer points just above the frame.
+		return a.FramePtr, s.offset - f.Size(), s.size
+	}
+
+	if a.stack < f.depth {
+		panic(fmt.Sprintf("stack depth %d is below frame allocation depth %d", a.stack, f.depth))
+	}
+	return a.StackPtr, s.offset + a.stack - f.depth, s.size
+}
+
+func (a *Assembly) checkFrame(f *Frame) {
+	if !f.allocated {
+		panic("frame is not allocated")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestFrameLayout(t *testing.T) {
+	f := new(ga.Frame)
+	f.Local("a", 1, 1)
+	f.Local("b", 8, 8)
+	f.Local("c", 4, 4)
+	f.Local("d", 16, 16)
+
+	for name, offset := range map[string]int{"a": 0, "b": 8, "c": 16, "d": 32} {
+		if n := f.Offset(name); n != offset {
+			t.Errorf("%s offset: %d", name, n)
+		}
+	}
+	if n := f.Size(); n != 48 {
+		t.Errorf("size: %d", n)
+	}
+}
+
+func frame(a *ga.Assembly, framePtr bool) {
+	f := &ga.Frame{FramePtr: framePtr}
+	f.Local("x", 8, 8)
+	f.Local("y", 4, 4)
+	f.Local("z", 1, 1)
+
+	a.Function("frame")
+	a.MoveImm(r0, 1)
+	a.AllocateFrame(f)
+	a.StoreLocal(f, "x", r0)
+	a.StoreLocal(f, "y", r0)
+	a.Push(r0)
+	a.LoadLocal(r1, f, "x")
+	a.LoadLocal(r1, f, "y")
+	a.LoadLocal(r1, f, "z")
+	a.AddressOfLocal(r2, f, "z")
+	a.Pop(r0)
+	a.FreeFrame(f)
+	a.Return()
+}
+
+func TestFrame(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		for _, framePtr := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/FramePtr=%v", name, framePtr), func(t *testing.T) {
+				a := ga.NewAssembly(arch, sys)
+				frame(a, framePtr)
+				if n := a.StackDepth(); n != 0 {
+					t.Errorf("stack depth: %d", n)
+				}
+				assembleCheck(t, arch, a.String())
+			})
+		}
+	}
+}
+
+func TestFrameAMD64(t *testing.T) {
+	f := new(ga.Frame)
+	f.Local("x", 8, 8)
+
+	a := ga.NewAssembly(ga.AMD64, sys)
+	a.AllocateFrame(f)
+	a.Set(r0)
+	a.Push(r0)
+	a.StoreLocal(f, "x", r0)
+	a.Pop(r0)
+	a.StoreLocal(f, "x", r0)
+	a.FreeFrame(f)
+
+	want := "\tsub\trsp, 16\n" +
+		"\tpush\trbx\n" +
+		"\tmov\t[rsp + 8], rbx\n" +
+		"\tpop\trbx\n" +
+		"\tmov\t[rsp], rbx\n" +
+		"\tadd\trsp, 16\n"
+	if s := a.String(); !strings.Contains(s, want) {
+		t.Errorf("output:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestFramePanics(t *testing.T) {
+	for want, gen := range map[string]func(*ga.Assembly, *ga.Frame){
+		"unknown local: y": func(a *ga.Assembly, f *ga.Frame) {
+			a.AllocateFrame(f)
+			a.LoadLocal(r0, f, "y")
+		},
+		"frame is not allocated": func(a *ga.Assembly, f *ga.Frame) {
+			a.LoadLocal(r0, f, "x")
+		},
+		"frame is already allocated": func(a *ga.Assembly, f *ga.Frame) {
+			a.AllocateFrame(f)
+			a.AllocateFrame(f)
+		},
+		"frame is allocated": func(a *ga.Assembly, f *ga.Frame) {
+			a.AllocateFrame(f)
+			f.Local("y", 8, 8)
+		},
+		"duplicate local: x": func(a *ga.Assembly, f *ga.Frame) {
+			f.Local("x", 8, 8)
+		},
+		"invalid local y alignment: 3": func(a *ga.Assembly, f *ga.Frame) {
+			f.Local("y", 8, 3)
+		},
+		"stack depth 24 differs from frame allocation depth 16": func(a *ga.Assembly, f *ga.Frame) {
+			a.AllocateFrame(f)
+			a.Set(r0)
+			a.Push(r0)
+			a.FreeFrame(f)
+		},
+		"stack depth 8 is below frame allocation depth 24": func(a *ga.Assembly, f *ga.Frame) {
+			a.Set(r0)
+			a.Push(r0)
+			a.AllocateFrame(f)
+			a.Pop(r0)
+			a.Pop(r0)
+			a.LoadLocal(r0, f, "x")
+		},
+	} {
+		t.Run(want, func(t *testing.T) {
+			defer func() {
+				if s := fmt.Sprint(recover()); s != want {
+					t.Error(s)
+				}
+			}()
+
+			f := new(ga.Frame)
+			f.Local("x", 8, 8)
+			gen(ga.NewAssembly(ga.AMD64, sys), f)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestFramePointer(t *testing.T) {
+	for _, test := range []struct {
+		arch  ga.Arch
+		insns []string
+	}{
+		{ga.AMD64, []string{
+			"\tpush\trbp\n\tmov\trbp, rsp\n",
+			"\tpop\trbp\n\tret\n",
+		}},
+		{ga.ARM64, []string{
+			"\tstp\tx29, lr, [sp, -16]!\n\tmov\tx29, sp\n",
+			"\tldp\tx29, lr, [sp], 16\n\tret\n",
+		}},
+		{ga.RISCV64, []string{
+			"\taddi\tsp, sp, -16\n\tsd\tra, 8(sp)\n\tsd\ts0, 0(sp)\n\taddi\ts0, sp, 16\n",
+			"\tld\ts0, 0(sp)\n\tld\tra, 8(sp)\n\taddi\tsp, sp, 16\n\tret\n",
+		}},
+	} {
+		t.Run(test.arch.Machine(), func(t *testing.T) {
+			a := ga.NewAssembly(test.arch, sys, ga.WithFramePointer())
+			a.Function("f")
+			a.Reset()
+			if use := a.Usage()[sys.FramePtr.Num(test.arch.ID())]; use != "frame" {
+				t.Errorf("frame pointer usage: %q", use)
+			}
+			a.Return()
+
+			s := a.String()
+			for _, insn := range test.insns {
+				if !strings.Contains(s, insn) {
+					t.Errorf("%q not found in:\n%s", insn, s)
+				}
+			}
+			assembleCheck(t, test.arch, s)
+		})
+	}
+}
+
+func TestFramePointerCallLibARM64(t *testing.T) {
+	a := ga.NewAssembly(ga.ARM64, sys, ga.WithFramePointer())
+	a.Function("f")
+	a.Reset()
+	a.CallLib("g", nil, nil)
+	a.Return()
+
+	s := a.String()
+	for _, insn := range []string{
+		"\tstp\tx29, x30, [sp, -16]!\n\tmov\tx29, sp\n\tbl\t\"g\"\n",
+		"\tmov\tsp, x29\n\tldp\tx29, x30, [sp], 16\n",
+	} {
+		if !strings.Contains(s, insn) {
+			t.Errorf("%q not found in:\n%s", insn, s)
+		}
+	}
+	assembleCheck(t, ga.ARM64, s)
+}
+
+func TestFramePointerUnsupported(t *testing.T) {
+	for _, f := range []func(){
+		func() { ga.NewAssembly(ga.PPC64LE, sys, ga.WithFramePointer()) },
+		func() { ga.NewAssembly(ga.C, sys, ga.WithFramePointer()) },
+		func() { ga.NewAssembly(ga.AMD64, sys, ga.WithFramePointer(), ga.WithSyntax(ga.GoSyntax)) },
+	} {
+		func() {
+			defer func() {
+				if s := fmt.Sprint(recover()); s != "frame pointer is not supported with this syntax or architecture" {
+					t.Error(s)
+				}
+			}()
+			f()
+		}()
+	}
+}
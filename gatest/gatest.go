@@ -0,0 +1,333 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gatest runs a code generator for every architecture in ga.Archs and
+// compares the outcomes.  Code is executed using the interpreters of package
+// gate.computer/ga/emu.
+package gatest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/emu"
+	"gate.computer/ga/linux"
+)
+
+// MemoryAddr is the address of Config.Memory during execution.
+const MemoryAddr uint64 = 0x10000000
+
+// DefaultMaxSteps is used if Config.MaxSteps is zero.
+const DefaultMaxSteps = 1000000
+
+// Config of a test run.
+type Config struct {
+	// System defaults to ga.Linux().
+	System *ga.System
+
+	// Options for ga.NewAssembly.
+	Options []ga.Option
+
+	// Entry symbol.  If empty, execution starts at the first instruction.
+	Entry string
+
+	// Args are the initial values of System.LibParams registers.
+	Args []uint64
+
+	// Memory is mapped at MemoryAddr during execution.  Its final contents
+	// are compared.
+	Memory []byte
+
+	// Symbols which are not defined by the generated code.
+	Symbols map[string]uint64
+
+	// ThreadPointer value.  If non-zero, the word at the address is set to
+	// the address itself, like the thread control block on x86-64.
+	// Thread-local symbols should be located above it, so that their offsets
+	// are valid on every architecture.
+	ThreadPointer uint64
+
+	// Syscall implementation.  If nil, SYS_EXIT and SYS_EXIT_GROUP stop
+	// execution and other syscalls return 0.
+	Syscall func(name string, args [6]uint64) (result uint64, exit bool)
+
+	MaxSteps int
+}
+
+// Syscall record.  Name is the linux.Syscalls key.  All six parameter
+// registers are recorded, so unused ones must have deterministic values.
+type Syscall struct {
+	Name   string
+	Args   [6]uint64
+	Result uint64
+}
+
+func (s Syscall) String() string {
+	return fmt.Sprintf("%s%v = %d", s.Name, s.Args, int64(s.Result))
+}
+
+// Result of execution on one architecture.
+type Result struct {
+	Arch   string // Go-style architecture name.
+	Source string // Generated assembly.
+
+	// Unsupported is set if there is no interpreter for the architecture.  The
+	// code was not generated (it may use features which the architecture
+	// lacks), so Source and the fields below are unset.
+	Unsupported bool
+
+	Regs   map[string]uint64 // Live registers by usage (except stack pointer).
+	Memory []byte            // Final contents of Config.Memory.
+	Trace  []Syscall
+	Exited bool // Execution was stopped by a syscall.
+	Err    error
+}
+
+// cpu is implemented by the interpreters.
+type cpu interface {
+	Reg(int) uint64
+	SetReg(int, uint64)
+	Run(entry string) error
+}
+
+type runner func(source string) (cpu, *emu.Machine, error)
+
+var runners = map[string]runner{
+	"x86_64": func(source string) (cpu, *emu.Machine, error) {
+		c, err := emu.NewAMD64(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, &c.Machine, nil
+	},
+	"aarch64": func(source string) (cpu, *emu.Machine, error) {
+		c, err := emu.NewARM64(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, &c.Machine, nil
+	},
+}
+
+// Supported reports if code generated for the architecture can be executed.
+func Supported(arch ga.Arch) bool {
+	_, found := runners[arch.Machine()]
+	return found
+}
+
+// Run code generator for every architecture in ga.Archs.  Results are sorted
+// by architecture name.  Architectures which are not supported are included,
+// with Result.Unsupported set.
+func Run(gen func(*ga.Assembly), c Config) []*Result {
+	var names []string
+	for name := range ga.Archs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []*Result
+	for _, name := range names {
+		results = append(results, RunArch(name, ga.Archs[name], gen, c))
+	}
+	return results
+}
+
+// RunArch runs code generator for one architecture.
+func RunArch(name string, arch ga.Arch, gen func(*ga.Assembly), c Config) *Result {
+	res := &Result{Arch: name}
+
+	newCPU, found := runners[arch.Machine()]
+	if !found {
+		res.Unsupported = true
+		return res
+	}
+
+	sys := c.System
+	if sys == nil {
+		sys = ga.Linux()
+	}
+
+	a := ga.NewAssembly(arch, sys, c.Options...)
+	gen(a)
+	res.Source = a.String()
+	if err := a.Check(); err != nil {
+		res.Err = err
+		return res
+	}
+
+	cpu, m, err := newCPU(res.Source)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	m.Symbols = c.Symbols
+	m.ThreadPointer = c.ThreadPointer
+	m.MaxSteps = c.MaxSteps
+	if m.MaxSteps == 0 {
+		m.MaxSteps = DefaultMaxSteps
+	}
+
+	names := syscallNames(arch)
+	m.Syscall = func(_ *emu.Machine, nr int, args [6]uint64) (uint64, bool) {
+		name, found := names[nr]
+		if !found {
+			name = fmt.Sprintf("syscall#%d", nr)
+		}
+		var (
+			result uint64
+			exit   bool
+		)
+		if c.Syscall != nil {
+			result, exit = c.Syscall(name, args)
+		} else {
+			exit = name == "SYS_EXIT" || name == "SYS_EXIT_GROUP"
+		}
+		res.Trace = append(res.Trace, Syscall{name, args, result})
+		return result, exit
+	}
+
+	if len(c.Memory) > 0 {
+		m.Map(MemoryAddr, len(c.Memory))
+		if err := m.Write(MemoryAddr, c.Memory); err != nil {
+			panic(err)
+		}
+	}
+
+	if c.ThreadPointer != 0 {
+		m.Map(c.ThreadPointer, 8)
+		if err := m.Store(c.ThreadPointer, 8, c.ThreadPointer); err != nil {
+			panic(err)
+		}
+	}
+
+	for i, x := range c.Args {
+		cpu.SetReg(int(sys.LibParams[i].Num(arch.ID())), x)
+	}
+
+	switch err := cpu.Run(c.Entry); {
+	case err == nil:
+	case errors.Is(err, emu.ErrExit):
+		res.Exited = true
+	default:
+		res.Err = err
+	}
+
+	res.Regs = make(map[string]uint64)
+	for n, use := range a.Usage() {
+		if use != "" && use != sys.StackPtr.Use {
+			res.Regs[use] = cpu.Reg(n)
+		}
+	}
+
+	if len(c.Memory) > 0 {
+		res.Memory = make([]byte, len(c.Memory))
+		if err := m.Read(MemoryAddr, res.Memory); err != nil {
+			panic(err)
+		}
+	}
+
+	return res
+}
+
+func syscallNames(arch ga.Arch) map[int]string {
+	names := make(map[int]string)
+	for name, nr := range linux.Syscalls {
+		names[ga.Specific(nr).Value(arch.ID())] = name
+	}
+	return names
+}
+
+// Compare results of code generator on every architecture.  Errors and
+// differences are reported via t.  Architectures which are not supported are
+// logged.  Results are returned for further inspection.
+func Compare(t testing.TB, gen func(*ga.Assembly), c Config) []*Result {
+	t.Helper()
+
+	var ref *Result
+	results := Run(gen, c)
+	for _, res := range results {
+		switch {
+		case res.Unsupported:
+			t.Logf("%s: not executed (unsupported architecture)", res.Arch)
+		case res.Err != nil:
+			t.Errorf("%s: %v", res.Arch, res.Err)
+			t.Logf("%s source:\n%s", res.Arch, res.Source)
+		case ref == nil:
+			ref = res
+		default:
+			if diff := Diff(ref, res); diff != "" {
+				t.Errorf("%s and %s differ:\n%s", ref.Arch, res.Arch, diff)
+			}
+		}
+	}
+	if ref == nil && !t.Failed() {
+		t.Fatal("no supported architectures")
+	}
+
+	return results
+}
+
+// Diff describes differences between two results, or returns empty string.
+// A register which is live on only one side is a difference; register
+// allocation may differ between architectures (e.g. syscall parameter and
+// result may share a register), so generators should end by resetting the
+// register usage to the registers of interest.
+func Diff(x, y *Result) string {
+	b := new(bytes.Buffer)
+
+	if x.Exited != y.Exited {
+		fmt.Fprintf(b, "exited: %s=%v %s=%v\n", x.Arch, x.Exited, y.Arch, y.Exited)
+	}
+
+	var uses []string
+	for use := range x.Regs {
+		uses = append(uses, use)
+	}
+	for use := range y.Regs {
+		if _, found := x.Regs[use]; !found {
+			uses = append(uses, use)
+		}
+	}
+	sort.Strings(uses)
+
+	for _, use := range uses {
+		xv, xFound := x.Regs[use]
+		yv, yFound := y.Regs[use]
+		switch {
+		case !yFound:
+			fmt.Fprintf(b, "register %q: %s=0x%x %s=(none)\n", use, x.Arch, xv, y.Arch)
+		case !xFound:
+			fmt.Fprintf(b, "register %q: %s=(none) %s=0x%x\n", use, x.Arch, y.Arch, yv)
+		case xv != yv:
+			fmt.Fprintf(b, "register %q: %s=0x%x %s=0x%x\n", use, x.Arch, xv, y.Arch, yv)
+		}
+	}
+
+	if len(x.Memory) != len(y.Memory) {
+		fmt.Fprintf(b, "memory size: %s=%d %s=%d\n", x.Arch, len(x.Memory), y.Arch, len(y.Memory))
+	}
+	for i := range x.Memory {
+		if i < len(y.Memory) && x.Memory[i] != y.Memory[i] {
+			fmt.Fprintf(b, "memory +%d: %s=0x%02x %s=0x%02x\n", i, x.Arch, x.Memory[i], y.Arch, y.Memory[i])
+		}
+	}
+
+	for i := 0; i < len(x.Trace) || i < len(y.Trace); i++ {
+		switch {
+		case i >= len(x.Trace):
+			fmt.Fprintf(b, "syscall #%d: %s=(none) %s=%v\n", i, x.Arch, y.Arch, y.Trace[i])
+		case i >= len(y.Trace):
+			fmt.Fprintf(b, "syscall #%d: %s=%v %s=(none)\n", i, x.Arch, x.Trace[i], y.Arch)
+		case x.Trace[i] != y.Trace[i]:
+			fmt.Fprintf(b, "syscall #%d: %s=%v %s=%v\n", i, x.Arch, x.Trace[i], y.Arch, y.Trace[i])
+		}
+	}
+
+	return b.String()
+}
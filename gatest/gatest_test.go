@@ -0,0 +1,651 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gatest_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/gatest"
+	"gate.computer/ga/linux"
+)
+
+var (
+	sys  = ga.Linux()
+	mem  = ga.Reg{AMD64: ga.RBX, ARM64: ga.X19, RISCV64: ga.S1, PPC64LE: ga.GPR14, Use: "mem"}
+	x    = ga.Reg{AMD64: ga.R12, ARM64: ga.X20, RISCV64: ga.S2, PPC64LE: ga.GPR15, Use: "x"}
+	y    = ga.Reg{AMD64: ga.R13, ARM64: ga.X21, RISCV64: ga.S3, PPC64LE: ga.GPR16, Use: "y"}
+	temp = ga.Reg{AMD64: ga.R14, ARM64: ga.X22, RISCV64: ga.S4, PPC64LE: ga.GPR17, Use: "temp"}
+)
+
+const memorySize = 512
+
+// function generates a function which has the address of Config.Memory in
+// mem.  Results are stored in memory by the body.  Only mem is live at the
+// end, so that other registers are not compared.
+func function(body func(a *ga.Assembly)) func(*ga.Assembly) {
+	return func(a *ga.Assembly) {
+		a.Function("test")
+		a.MoveImm64(mem, gatest.MemoryAddr)
+		body(a)
+		a.Reset(mem)
+		a.Return()
+	}
+}
+
+// store x into the nth word of memory.
+func store(a *ga.Assembly, n int, r ga.Reg) {
+	a.Store(mem, n*8, r)
+}
+
+var compareTests = []struct {
+	name   string
+	config gatest.Config
+	gen    func(*ga.Assembly)
+	want   []uint64 // Words of memory.
+}{
+	{
+		name: "MoveImm",
+		gen: function(func(a *ga.Assembly) {
+			for i, value := range []int{0, 1, -1, -4, 0x7fffffff, -0x80000000, 0x80000000, 0xffffffff, 1 << 32, -1 << 32} {
+				a.MoveImm(x, value)
+				store(a, i, x)
+			}
+		}),
+		want: []uint64{0, 1, 0xffffffffffffffff, 0xfffffffffffffffc, 0x7fffffff, 0xffffffff80000000, 0x80000000, 0xffffffff, 1 << 32, 0xffffffff00000000},
+	},
+	{
+		name: "MoveImm64",
+		gen: function(func(a *ga.Assembly) {
+			for i, value := range []uint64{0, 0xfffffffffffffffc, 0xffffffff80000000, 0xffffffff7fffffff, 0x8000000000000000, 0xfedcba9876543210, 0xffff123400005678} {
+				a.MoveImm(x, -1) // Upper bits must be replaced.
+				a.MoveImm64(x, value)
+				store(a, i, x)
+			}
+		}),
+		want: []uint64{0, 0xfffffffffffffffc, 0xffffffff80000000, 0xffffffff7fffffff, 0x8000000000000000, 0xfedcba9876543210, 0xffff123400005678},
+	},
+	{
+		name: "MoveReg",
+		gen: function(func(a *ga.Assembly) {
+			a.MoveImm64(x, 0x123456789abcdef0)
+			a.MoveReg(y, x)
+			a.MoveReg(y, y)
+			store(a, 0, y)
+		}),
+		want: []uint64{0x123456789abcdef0},
+	},
+	{
+		name: "Arithmetic",
+		gen: function(func(a *ga.Assembly) {
+			a.MoveImm(x, 1000)
+			a.AddImm(y, x, 4095)
+			store(a, 0, y)
+			a.AddImm(y, x, -2000)
+			store(a, 1, y)
+			a.AddImm(x, x, 0)
+			a.AddImm(y, x, 0)
+			store(a, 2, y)
+			a.AddReg(y, x, y)
+			store(a, 3, y)
+			a.SubtractImm(y, 4096)
+			store(a, 4, y)
+			a.SubtractReg(y, x)
+			store(a, 5, y)
+			a.MultiplyImm(y, x, -1000, temp)
+			store(a, 6, y)
+			a.MultiplyImm(y, y, 1<<20, temp)
+			store(a, 7, y)
+		}),
+		want: []uint64{5095, 0xfffffffffffffc18, 1000, 2000, 0xfffffffffffff7d0, 0xfffffffffffff3e8, 0xfffffffffff0bdc0, 0xffffff0bdc000000},
+	},
+	{
+		name: "Logic",
+		gen: function(func(a *ga.Assembly) {
+			for i, value := range []int{0xff, 0xff0, -16, 0x3fffc000} {
+				a.MoveImm64(x, 0xf0f0f0f0f0f0f0f0)
+				a.AndImm(x, value)
+				store(a, i*2, x)
+				a.MoveImm64(x, 0xf0f0f0f0f0f0f0f0)
+				a.OrImm(x, value)
+				store(a, i*2+1, x)
+			}
+			a.MoveImm64(x, 0xf0f0f0f0f0f0f0f0)
+			a.MoveImm64(y, 0xff00ff00ff00ff00)
+			a.AndReg(y, x)
+			store(a, 8, y)
+			a.MoveImm64(y, 0x0000ffff0000ffff)
+			a.OrReg(y, x)
+			store(a, 9, y)
+		}),
+		want: []uint64{
+			0xf0, 0xf0f0f0f0f0f0f0ff,
+			0xf0, 0xf0f0f0f0f0f0fff0,
+			0xf0f0f0f0f0f0f0f0, 0xfffffffffffffff0,
+			0x30f0c000, 0xf0f0f0f0fffff0f0,
+			0xf000f000f000f000,
+			0xf0f0fffff0f0ffff,
+		},
+	},
+	{
+		name: "Shift",
+		gen: function(func(a *ga.Assembly) {
+			i := 0
+			for _, s := range []ga.Shift{ga.Left, ga.RightLogical, ga.RightArithmetic} {
+				for _, count := range []int{0, 1, 31, 32, 63} {
+					a.MoveImm64(x, 0x8421000000001248)
+					a.ShiftImm(s, x, count)
+					store(a, i, x)
+					i++
+				}
+			}
+		}),
+		want: []uint64{
+			0x8421000000001248, 0x0842000000002490, 0x0000092400000000, 0x0000124800000000, 0,
+			0x8421000000001248, 0x4210800000000924, 0x0000000108420000, 0x0000000084210000, 1,
+			0x8421000000001248, 0xc210800000000924, 0xffffffff08420000, 0xffffffff84210000, 0xffffffffffffffff,
+		},
+	},
+	{
+		name: "LoadStore",
+		config: gatest.Config{
+			Memory: func() []byte {
+				b := make([]byte, memorySize)
+				binary.LittleEndian.PutUint64(b[256:], 0x8899aabbccddeeff)
+				return b
+			}(),
+		},
+		gen: function(func(a *ga.Assembly) {
+			a.MoveImm(x, -1) // Upper bits must be replaced.
+			a.Load(x, mem, 256)
+			store(a, 0, x)
+			a.MoveImm(x, -1)
+			a.Load4Bytes(x, mem, 256)
+			store(a, 1, x)
+			a.MoveImm(x, -1)
+			a.Load4Bytes(x, mem, 260)
+			store(a, 2, x)
+			a.MoveImm(x, -1)
+			a.LoadByte(x, mem, 256)
+			store(a, 3, x)
+			a.MoveImm(x, -1)
+			a.LoadByte(x, mem, 263)
+			store(a, 4, x)
+			a.MoveImm(y, -1)
+			a.LoadByte(y, mem, 257)
+			store(a, 5, y)
+			a.MoveImm(x, -1)
+			a.Store4Bytes(mem, 48, x)
+			a.MoveImm(x, 0)
+			a.AddImm(temp, mem, 64)
+			a.Store(temp, -8, x)
+			a.Load(y, temp, -16)
+			store(a, 8, y)
+		}),
+		want: []uint64{0x8899aabbccddeeff, 0xccddeeff, 0x8899aabb, 0xff, 0x88, 0xee, 0xffffffff, 0, 0xffffffff},
+	},
+	{
+		name: "Global",
+		gen: func(a *ga.Assembly) {
+			a.Section(ga.DataSection)
+			a.DataSymbol("global", 8)
+			a.DataWord8(0x8899aabbccddeeff)
+			a.DataWord8(0)
+			a.Section(ga.TextSection)
+			function(func(a *ga.Assembly) {
+				a.MoveImm(x, -1)
+				a.LoadGlobal(x, "global", 0)
+				store(a, 0, x)
+				a.MoveImm(x, -1)
+				a.LoadGlobal4Bytes(x, "global", 4)
+				store(a, 1, x)
+				a.MoveImm(x, -1)
+				a.LoadGlobalByte(x, "global", 7)
+				store(a, 2, x)
+				a.MoveImm(x, -1)
+				a.StoreGlobal4Bytes("global", 8, x, temp)
+				a.LoadGlobal(y, "global", 8)
+				store(a, 3, y)
+				a.StoreGlobal("global", 8, y, temp)
+				a.LoadGlobal(y, "global", 8)
+				store(a, 4, y)
+			})(a)
+		},
+		want: []uint64{0x8899aabbccddeeff, 0x8899aabb, 0x88, 0xffffffff, 0xffffffff},
+	},
+	{
+		name: "ThreadLocal",
+		config: gatest.Config{
+			ThreadPointer: 0x30000000,
+			Symbols:       map[string]uint64{"tls": 0x30000000},
+		},
+		gen: function(func(a *ga.Assembly) {
+			a.LoadThreadPointer(x)
+			store(a, 0, x)
+			for i, model := range []ga.TLSModel{ga.InitialExecTLS, ga.LocalExecTLS} {
+				a.ThreadLocalAddress(y, "tls", model, temp)
+				store(a, 1+i*2, y)
+				a.LoadThreadLocal(y, "tls", 0, model, temp)
+				store(a, 2+i*2, y)
+			}
+		}),
+		want: []uint64{0x30000000, 0x30000000, 0x30000000, 0x30000000, 0x30000000},
+	},
+	{
+		name: "Stack",
+		gen: function(func(a *ga.Assembly) {
+			a.MoveImm(x, 1)
+			a.MoveImm(y, 2)
+			a.Push(x)
+			a.PushPair(x, y)
+			a.PopPair(y, x)
+			store(a, 0, x)
+			store(a, 1, y)
+			a.Pop(y)
+			store(a, 2, y)
+
+			f := new(ga.Frame)
+			f.Local("a", 8, 8)
+			f.Local("b", 4, 4)
+			a.AllocateFrame(f)
+			a.MoveImm(x, 3)
+			a.StoreLocal(f, "a", x)
+			a.Push(x)
+			a.AddressOfLocal(y, f, "a")
+			a.Load(y, y, 0)
+			store(a, 3, y)
+			a.Pop(x)
+			a.LoadLocal(y, f, "a")
+			store(a, 4, y)
+			a.FreeFrame(f)
+		}),
+		want: []uint64{2, 1, 1, 3, 3},
+	},
+	{
+		name: "JumpIf",
+		gen: function(func(a *ga.Assembly) {
+			i := 0
+			for _, value := range []int{-5, 0, 5} {
+				for _, c := range []ga.Cond{ga.EQ, ga.NE, ga.LT, ga.LE, ga.GT, ga.GE} {
+					for _, imm := range []int{-5, 0, 5} {
+						a.MoveImm(x, value)
+						a.MoveImm(y, imm)
+						a.MoveImm(temp, 0)
+						a.JumpIfImm(c, x, imm, "imm"+string(rune('a'+i)))
+						a.OrImm(temp, 1)
+						a.Label("imm" + string(rune('a'+i)))
+						a.JumpIfReg(c, x, y, "reg"+string(rune('a'+i)))
+						a.OrImm(temp, 2)
+						a.Label("reg" + string(rune('a'+i)))
+						a.Load(y, mem, 0)
+						a.ShiftImm(ga.Left, y, 2)
+						a.OrReg(y, temp)
+						store(a, 0, y)
+						i++
+					}
+				}
+			}
+		}),
+		// Each comparison contributes 2 bits (set if the jump was not taken).
+		want: []uint64{jumpIfWant()},
+	},
+	{
+		name: "JumpIfBit",
+		gen: function(func(a *ga.Assembly) {
+			i := 0
+			for _, value := range []uint64{0, 0x0000000100000001, 0x8000000080000000, 0xffffffffffffffff} {
+				for _, bit := range []uint{0, 30, 31, 32, 63} {
+					set := fmt.Sprintf("set%d", i)
+					clear := fmt.Sprintf("clear%d", i)
+					a.MoveImm64(x, value)
+					a.MoveImm(y, 0)
+					a.JumpIfBitSet(x, bit, set)
+					a.OrImm(y, 1)
+					a.Label(set)
+					a.JumpIfBitNotSet(x, bit, clear)
+					a.OrImm(y, 2)
+					a.Label(clear)
+					store(a, i, y)
+					i++
+				}
+			}
+		}),
+		want: []uint64{
+			1, 1, 1, 1, 1,
+			2, 1, 1, 2, 1,
+			1, 1, 2, 1, 2,
+			2, 2, 2, 2, 2,
+		},
+	},
+	{
+		name: "Control",
+		gen: function(func(a *ga.Assembly) {
+			a.MoveImm(x, 0)
+			a.MoveImm(y, 10)
+			a.WhileImm(ga.GT, y, 0, func(l *ga.LoopBlock) {
+				a.AddReg(x, x, y)
+				a.SubtractImm(y, 1)
+			})
+			store(a, 0, x)
+
+			a.IfImmElse(ga.LT, x, 100, func() {
+				a.MoveImm(y, 1)
+			}, func() {
+				a.MoveImm(y, 2)
+			})
+			store(a, 1, y)
+
+			a.IfImm(ga.GE, x, 55, func() {
+				a.AddImm(y, y, 10)
+			})
+			a.IfImm(ga.GT, x, 55, func() {
+				a.AddImm(y, y, 100)
+			})
+			store(a, 2, y)
+
+			a.MoveImm(y, 0)
+			a.Loop(func(l *ga.LoopBlock) {
+				a.AddImm(y, y, 3)
+				a.If(ga.GE, y, x, func() {
+					l.Break()
+				})
+			})
+			store(a, 3, y)
+
+			a.MoveImm(x, 0)
+			a.MoveImm(y, 0)
+			a.WhileImm(ga.LT, y, 10, func(l *ga.LoopBlock) {
+				a.AddImm(y, y, 1)
+				a.MoveReg(temp, y)
+				a.AndImm(temp, 1)
+				a.IfImm(ga.EQ, temp, 0, func() {
+					l.Continue()
+				})
+				a.AddReg(x, x, y)
+			})
+			store(a, 4, x)
+
+			a.MoveImm(y, 0)
+			a.WhileReg(ga.LT, y, x, func(l *ga.LoopBlock) {
+				a.AddImm(y, y, 7)
+			})
+			store(a, 5, y)
+		}),
+		want: []uint64{55, 1, 11, 57, 25, 28},
+	},
+	{
+		name: "Switch",
+		gen: function(func(a *ga.Assembly) {
+			for i, cases := range []map[int]int{
+				{-1: 10, 0: 11, 1: 12, 3: 13, 4: 14},
+				{-5000: 10, 0: 11, 7: 12, 5000: 13, 1 << 40: 14},
+			} {
+				a.MoveImm(y, 0)
+				values := []int{-5000, -2, -1, 0, 1, 2, 3, 4, 5, 7, 5000, 1 << 40}
+				for _, value := range values {
+					labels := make(map[int]string)
+					for v := range cases {
+						labels[v] = a.NewLabel("case")
+					}
+					def := a.NewLabel("default")
+					end := a.NewLabel("end")
+
+					a.MoveImm(x, value)
+					a.Switch(x, labels, def, temp)
+					for v, label := range labels {
+						a.Label(label)
+						a.AddImm(y, y, cases[v])
+						a.Jump(end)
+					}
+					a.Label(def)
+					a.AddImm(y, y, 1000)
+					a.Label(end)
+					a.ShiftImm(ga.Left, y, 4)
+				}
+				store(a, i, y)
+			}
+		}),
+		want: []uint64{switchWant([]int{1000, 1000, 10, 11, 12, 1000, 13, 14, 1000, 1000, 1000, 1000}), switchWant([]int{10, 1000, 1000, 11, 1000, 1000, 1000, 1000, 1000, 12, 13, 14})},
+	},
+	{
+		name: "Call",
+		gen: func(a *ga.Assembly) {
+			function(func(a *ga.Assembly) {
+				a.MoveImm(x, 42)
+				a.Call("inc")
+				store(a, 0, x)
+			})(a)
+
+			a.Function("inc")
+			a.Reset(mem, x)
+			a.AddImm(x, x, 1)
+			store(a, 1, x)
+			a.Return()
+		},
+		want: []uint64{43, 43},
+	},
+	{
+		name:   "CallLib",
+		config: gatest.Config{Entry: "test"},
+		gen: func(a *ga.Assembly) {
+			// The library function comes first so that only mem is live at
+			// the end.
+			a.FunctionWithoutPrologue("add")
+			a.Reset()
+			a.Set(sys.LibParams[0])
+			a.Set(sys.LibParams[1])
+			a.AddReg(sys.LibResult, sys.LibParams[0], sys.LibParams[1])
+			a.ReturnWithoutEpilogue()
+
+			function(func(a *ga.Assembly) {
+				a.MoveImm(x, 40)
+				a.MoveImm(y, 2)
+				a.CallLib("add", []ga.Reg{x, y}, []ga.Reg{x})
+				store(a, 0, x)
+				store(a, 1, y)
+			})(a)
+		},
+		want: []uint64{42, 2},
+	},
+	{
+		name: "Syscall",
+		config: gatest.Config{
+			Syscall: func(name string, args [6]uint64) (uint64, bool) {
+				switch name {
+				case "SYS_WRITE":
+					return args[2], false
+				case "SYS_EXIT":
+					return 0, true
+				}
+				return 0, false
+			},
+		},
+		gen: function(func(a *ga.Assembly) {
+			for i, r := range sys.SysParams {
+				a.MoveImm(r, i+1)
+			}
+			a.Syscall(linux.SYS_WRITE)
+			a.MoveReg(x, sys.SysResult)
+			store(a, 0, x)
+			for i, r := range sys.SysParams {
+				a.MoveImm(r, -i)
+			}
+			a.Syscall(linux.SYS_EXIT)
+			a.Unreachable()
+		}),
+		want: []uint64{3},
+	},
+}
+
+// switchWant computes the expected result of the Switch test.
+func switchWant(values []int) uint64 {
+	var result uint64
+	for _, v := range values {
+		result = (result + uint64(v)) << 4
+	}
+	return result
+}
+
+// jumpIfWant computes the expected result of the JumpIf test.
+func jumpIfWant() uint64 {
+	var result uint64
+	for _, value := range []int{-5, 0, 5} {
+		for _, c := range []ga.Cond{ga.EQ, ga.NE, ga.LT, ga.LE, ga.GT, ga.GE} {
+			for _, imm := range []int{-5, 0, 5} {
+				var taken bool
+				switch c {
+				case ga.EQ:
+					taken = value == imm
+				case ga.NE:
+					taken = value != imm
+				case ga.LT:
+					taken = value < imm
+				case ga.LE:
+					taken = value <= imm
+				case ga.GT:
+					taken = value > imm
+				case ga.GE:
+					taken = value >= imm
+				}
+				result <<= 2
+				if !taken {
+					result |= 3
+				}
+			}
+		}
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range compareTests {
+		t.Run(test.name, func(t *testing.T) {
+			c := test.config
+			if c.Memory == nil {
+				c.Memory = make([]byte, memorySize)
+			}
+
+			results := gatest.Compare(t, test.gen, c)
+			if t.Failed() {
+				return
+			}
+
+			for _, res := range results {
+				if res.Unsupported {
+					continue
+				}
+				for i, want := range test.want {
+					if v := binary.LittleEndian.Uint64(res.Memory[i*8:]); v != want {
+						t.Errorf("%s: word %d = 0x%x; want 0x%x", res.Arch, i, v, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSyscallTrace(t *testing.T) {
+	results := gatest.Compare(t, function(func(a *ga.Assembly) {
+		for i, r := range sys.SysParams {
+			a.MoveImm(r, i*10)
+		}
+		a.Syscall(linux.SYS_GETPID)
+		a.MoveImm(sys.SysParams[0], 7)
+		a.Syscall(linux.SYS_EXIT)
+		a.Unreachable()
+	}), gatest.Config{})
+
+	for _, res := range results {
+		if res.Unsupported {
+			continue
+		}
+		if !res.Exited {
+			t.Errorf("%s: not exited", res.Arch)
+		}
+		want := []gatest.Syscall{
+			{"SYS_GETPID", [6]uint64{0, 10, 20, 30, 40, 50}, 0},
+			{"SYS_EXIT", [6]uint64{7, 10, 20, 30, 40, 50}, 0},
+		}
+		if len(res.Trace) != len(want) {
+			t.Fatalf("%s: trace: %v", res.Arch, res.Trace)
+		}
+		for i, call := range res.Trace {
+			if call != want[i] {
+				t.Errorf("%s: syscall #%d: %v", res.Arch, i, call)
+			}
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	x := &gatest.Result{
+		Arch:   "x",
+		Regs:   map[string]uint64{"a": 1, "b": 2, "c": 3},
+		Memory: []byte{1, 2},
+		Trace:  []gatest.Syscall{{"SYS_GETPID", [6]uint64{}, 1}},
+	}
+	y := &gatest.Result{
+		Arch:   "y",
+		Regs:   map[string]uint64{"a": 1, "b": 3, "d": 4},
+		Memory: []byte{1, 3, 0},
+		Exited: true,
+	}
+
+	if diff := gatest.Diff(x, x); diff != "" {
+		t.Errorf("identical: %s", diff)
+	}
+
+	diff := gatest.Diff(x, y)
+	for _, s := range []string{
+		"exited: x=false y=true",
+		`register "b": x=0x2 y=0x3`,
+		`register "c": x=0x3 y=(none)`,
+		`register "d": x=(none) y=0x4`,
+		"memory size: x=2 y=3",
+		"memory +1: x=0x02 y=0x03",
+		"syscall #0: x=SYS_GETPID[0 0 0 0 0 0] = 1 y=(none)",
+	} {
+		if !strings.Contains(diff, s) {
+			t.Errorf("diff lacks %q:\n%s", s, diff)
+		}
+	}
+}
+
+func TestRunArchError(t *testing.T) {
+	res := gatest.RunArch("amd64", ga.AMD64, function(func(a *ga.Assembly) {
+		a.Jump("undefined")
+	}), gatest.Config{})
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "undefined") {
+		t.Errorf("error: %v", res.Err)
+	}
+}
+
+func TestRunArchCheck(t *testing.T) {
+	res := gatest.RunArch("amd64", ga.AMD64, function(func(a *ga.Assembly) {
+		a.Jump(".nowhere")
+	}), gatest.Config{})
+	if res.Err == nil || res.Err.Error() != "undefined local name .nowhere" || res.Source == "" {
+		t.Errorf("error: %v", res.Err)
+	}
+}
+
+func TestRunUnsupported(t *testing.T) {
+	for _, res := range gatest.Run(function(func(a *ga.Assembly) {}), gatest.Config{}) {
+		if res.Unsupported != !gatest.Supported(ga.Archs[res.Arch]) {
+			t.Errorf("%s: unsupported: %v", res.Arch, res.Unsupported)
+		}
+		if (res.Source == "") != res.Unsupported {
+			t.Errorf("%s: source: %q", res.Arch, res.Source)
+		}
+	}
+
+	res := gatest.RunArch("riscv64", ga.RISCV64, function(func(a *ga.Assembly) {}), gatest.Config{})
+	if !res.Unsupported || res.Regs != nil {
+		t.Errorf("riscv64: %#v", res)
+	}
+}
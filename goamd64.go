@@ -0,0 +1,450 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+func (r RegAMD64) goReg() string {
+	switch r {
+	case RAX:
+		return "AX"
+	case RCX:
+		return "CX"
+	case RDX:
+		return "DX"
+	case RBX:
+		return "BX"
+	case RSP:
+		return "SP"
+	case RBP:
+		return "BP"
+	case RSI:
+		return "SI"
+	case RDI:
+		return "DI"
+	}
+
+	if r < 16 {
+		return fmt.Sprintf("R%d", r)
+	}
+
+	panic(r)
+}
+
+// goAMD64 emits Go assembler syntax.  Every Function and global Label starts
+// a separate TEXT symbol, so execution must not fall through to them.  Jumps
+// to local names refer to labels within the current TEXT symbol, and calls to
+// local names refer to file-private TEXT symbols.
+type goAMD64 struct {
+	*System
+	*buffer
+}
+
+func (a *goAMD64) check(r Reg) {
+	a.checkUsage(uint8(r.AMD64), r.Use)
+}
+
+func (a *goAMD64) Set(r Reg) {
+	a.buffer.regUsage[r.AMD64] = r.Use
+}
+
+func (a *goAMD64) Label(name string, opts ...SymbolOptions) {
+	o := checkGoSymbolOptions(name, opts)
+	if global(name) {
+		a.goText(name, o)
+	} else {
+		a.goLabel(name)
+	}
+}
+
+func (a *goAMD64) FunctionEpilogue() {
+}
+
+func (a *goAMD64) Function(name string, opts ...SymbolOptions) {
+	a.goText(name, checkGoSymbolOptions(name, opts))
+}
+
+func (a *goAMD64) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.goText(name, checkGoSymbolOptions(name, opts))
+}
+
+func (a *goAMD64) Return() {
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *goAMD64) ReturnWithoutEpilogue() {
+	a.insn("RET")
+	a.speculationBarrier()
+}
+
+func (a *goAMD64) Address(dest Reg, name string) {
+	a.insn("LEAQ", goSymbol(name), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) MoveDef(dest Reg, name string) {
+	a.insn("MOVQ", "$"+goIdent(name), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) MoveImm(dest Reg, value int) {
+	a.MoveImm64(dest, uint64(int64(value)))
+}
+
+func (a *goAMD64) MoveImm64(dest Reg, value uint64) {
+	if value == 0 {
+		a.insn("XORL", a.reg(dest), a.reg(dest))
+	} else {
+		a.insn("MOVQ", a.imm(int(value)), a.reg(dest)) // Encoded optimally.
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) MoveReg(dest, src Reg) {
+	a.check(src)
+	if a.reg(dest) != a.reg(src) {
+		a.insn("MOVQ", a.reg(src), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) MoveRegFloat(dest Reg, src FloatReg) {
+	a.insn("MOVQ", a.floatreg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) AddImm(dest, src Reg, value int) {
+	a.check(src)
+	switch {
+	case value == 0:
+		a.MoveReg(dest, src)
+	case a.reg(dest) == a.reg(src):
+		a.insn("ADDQ", a.imm(value), a.reg(dest))
+	case value > 0:
+		a.insn("LEAQ", a.mem(src, value), a.reg(dest))
+	default:
+		a.insn("MOVQ", a.reg(src), a.reg(dest))
+		a.insn("ADDQ", a.imm(value), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) AddReg(dest, src1, src2 Reg) {
+	a.check(src1)
+	a.check(src2)
+	switch {
+	case a.reg(dest) == a.reg(src1):
+		a.insn("ADDQ", a.reg(src2), a.reg(dest))
+	case a.reg(dest) == a.reg(src2):
+		a.insn("ADDQ", a.reg(src1), a.reg(dest))
+	default:
+		a.insnf("LEAQ (%s)(%s*1), %s", a.reg(src1), a.reg(src2), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) SubtractImm(dest Reg, value int) {
+	a.check(dest)
+	if value != 0 {
+		a.insn("SUBQ", a.imm(value), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) SubtractReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("SUBQ", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) MultiplyImm(dest, src Reg, value int, temp Reg) {
+	a.check(src)
+	a.insn("IMUL3Q", a.imm(value), a.reg(src), a.reg(dest))
+	a.Set(dest)
+	a.Set(temp.As(""))
+}
+
+func (a *goAMD64) AndImm(dest Reg, value int) {
+	a.check(dest)
+	switch {
+	case value == 0:
+		a.insn("XORL", a.reg(dest), a.reg(dest))
+	case value > 0 && value <= 0x7fffffff:
+		a.insn("ANDL", a.imm(value), a.reg(dest))
+	default:
+		a.insn("ANDQ", a.imm(value), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goAMD64) AndReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("ANDQ", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) OrImm(dest Reg, value int) {
+	a.check(dest)
+	a.insn("ORQ", a.imm(value), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) OrReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("ORQ", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) ShiftImm(s Shift, r Reg, count int) {
+	a.check(r)
+	if count != 0 {
+		a.insn(a.shift(s), a.imm(count), a.reg(r))
+	}
+	a.Set(r)
+}
+
+func (a *goAMD64) Load(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVQ", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) Load4Bytes(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVL", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) LoadByte(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVBLZX", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) Store(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("MOVQ", a.reg(src), a.mem(base, offset))
+}
+
+func (a *goAMD64) Store4Bytes(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("MOVL", a.reg(src), a.mem(base, offset))
+}
+
+func (a *goAMD64) LoadGlobal(dest Reg, name string, offset int) {
+	a.insn("MOVQ", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.insn("MOVL", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.insn("MOVBLZX", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goAMD64) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insn("MOVQ", a.reg(src), goSymbolOffset(name, offset))
+	a.Set(temp.As(""))
+}
+
+func (a *goAMD64) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insn("MOVL", a.reg(src), goSymbolOffset(name, offset))
+	a.Set(temp.As(""))
+}
+
+func (a *goAMD64) LoadThreadPointer(dest Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goAMD64) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goAMD64) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goAMD64) Push(r Reg) {
+	a.check(r)
+	a.insn("PUSHQ", a.reg(r))
+}
+
+func (a *goAMD64) Pop(r Reg) {
+	a.insn("POPQ", a.reg(r))
+	a.Set(r)
+}
+
+func (a *goAMD64) Jump(name string) {
+	a.insn("JMP", a.target(name))
+}
+
+// JumpRegRoutine uses a retpoline.  Internal names are generated from the
+// prefix, so it may be used many times with the same prefix.
+func (a *goAMD64) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	setup := a.internalLabel(internalNamePrefix + "_setup")
+	capture := a.internalLabel(internalNamePrefix + "_capture")
+
+	a.check(r)
+	a.Call(setup)
+
+	a.define(capture)
+	a.goLabel(capture)
+	a.insn("PAUSE")
+	a.refer(capture)
+	a.Jump(capture)
+
+	a.define(setup)
+	a.FunctionWithoutPrologue(setup)
+	a.Store(a.StackPtr, 0, r)
+	a.MoveImm(r, 0)
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *goAMD64) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.check(r)
+	if bit < 31 {
+		a.insn("TESTL", a.imm(1<<bit), a.reg(r))
+		a.jumpIf("JNE", "JEQ", name)
+	} else {
+		a.insn("BTQ", a.imm(int(bit)), a.reg(r))
+		a.jumpIf("JCS", "JCC", name)
+	}
+}
+
+func (a *goAMD64) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.check(r)
+	if bit < 31 {
+		a.insn("TESTL", a.imm(1<<bit), a.reg(r))
+		a.jumpIf("JEQ", "JNE", name)
+	} else {
+		a.insn("BTQ", a.imm(int(bit)), a.reg(r))
+		a.jumpIf("JCC", "JCS", name)
+	}
+}
+
+func (a *goAMD64) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.check(r)
+	switch {
+	case value == 0 && (c == EQ || c == NE):
+		a.insn("TESTQ", a.reg(r), a.reg(r))
+	default:
+		a.insn("CMPQ", a.reg(r), a.imm(value))
+	}
+	a.jumpIf("J"+a.cond(c), "J"+a.cond(inverse(c)), name)
+}
+
+func (a *goAMD64) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.check(dest)
+	a.check(src)
+	a.insn("CMPQ", a.reg(dest), a.reg(src))
+	a.jumpIf("J"+a.cond(c), "J"+a.cond(inverse(c)), name)
+}
+
+func (a *goAMD64) Call(name string) {
+	a.refer(name)
+	a.insn("CALL", goSymbol(name))
+}
+
+func (a *goAMD64) Syscall(nr Syscall) {
+	a.MoveImm(a.SyscallNr, nr.num(AMD64))
+	a.insn("SYSCALL")
+	a.Set(a.SysResult)
+}
+
+func (a *goAMD64) Unreachable() {
+	a.insn("INT", "$3")
+}
+
+func (a *goAMD64) speculationBarrier() {
+	a.insn("INT", "$3")
+}
+
+// target of jump: global names are TEXT symbols, local names are labels.
+func (a *goAMD64) target(name string) string {
+	if global(name) {
+		return goSymbol(name)
+	}
+	return goLabel(name)
+}
+
+// jumpIf emits a conditional jump.  The Go assembler doesn't support
+// conditional jumps to TEXT symbols, so a global name is reached via an
+// unconditional jump which is skipped using the inverse condition.
+func (a *goAMD64) jumpIf(mnemonic, inverse, name string) {
+	if global(name) {
+		a.insn(inverse, "2(PC)")
+		a.insn("JMP", goSymbol(name))
+	} else {
+		a.insn(mnemonic, goLabel(name))
+	}
+}
+
+func (a *goAMD64) imm(x int) string {
+	return fmt.Sprintf("$%d", x)
+}
+
+func (a *goAMD64) mem(base Reg, offset int) string {
+	return a.memReg(base.AMD64, offset)
+}
+
+func (a *goAMD64) memReg(base RegAMD64, offset int) string {
+	if offset == 0 {
+		return fmt.Sprintf("(%s)", base.goReg())
+	}
+	return fmt.Sprintf("%d(%s)", offset, base.goReg())
+}
+
+func (a *goAMD64) reg(x Reg) string {
+	return x.AMD64.goReg()
+}
+
+func (a *goAMD64) floatreg(x FloatReg) string {
+	return fmt.Sprintf("X%d", x)
+}
+
+func (a *goAMD64) cond(x Cond) string {
+	switch x {
+	case EQ:
+		return "EQ"
+	case NE:
+		return "NE"
+	case LT:
+		return "LT"
+	case LE:
+		return "LE"
+	case GT:
+		return "GT"
+	case GE:
+		return "GE"
+	}
+
+	panic(x)
+}
+
+func (a *goAMD64) shift(x Shift) string {
+	switch x {
+	case Left:
+		return "SHLQ"
+	case RightLogical:
+		return "SHRQ"
+	case RightArithmetic:
+		return "SARQ"
+	}
+
+	panic(x)
+}
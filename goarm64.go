@@ -0,0 +1,391 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+func (r RegARM64) goReg() string {
+	if r == 31 {
+		return "RSP"
+	}
+	if r < 31 {
+		return fmt.Sprintf("R%d", r)
+	}
+	panic(r)
+}
+
+// goARM64 emits Go assembler syntax.  Every Function and global Label starts
+// a separate TEXT symbol, so execution must not fall through to them.  Jumps
+// to local names refer to labels within the current TEXT symbol, and calls to
+// local names refer to file-private TEXT symbols.
+type goARM64 struct {
+	*System
+	*buffer
+	goFrame bool // Current TEXT symbol's frame is managed by the Go assembler.
+}
+
+func (a *goARM64) check(r Reg) {
+	a.checkUsage(uint8(r.ARM64), r.Use)
+}
+
+func (a *goARM64) Set(r Reg) {
+	a.buffer.regUsage[r.ARM64] = r.Use
+}
+
+func (a *goARM64) Label(name string, opts ...SymbolOptions) {
+	o := checkGoSymbolOptions(name, opts)
+	if global(name) {
+		a.text(name, o)
+	} else {
+		a.goLabel(name)
+	}
+}
+
+// FunctionEpilogue doesn't do anything if the Go assembler manages the frame:
+// it restores the link register at RET.
+func (a *goARM64) FunctionEpilogue() {
+	if !a.goFrame {
+		a.insn("MOVD.P", a.mem(a.StackPtr, 16), XLR.goReg())
+	}
+}
+
+// Function saves the link register unless the Go assembler manages the frame.
+func (a *goARM64) Function(name string, opts ...SymbolOptions) {
+	a.text(name, checkGoSymbolOptions(name, opts))
+	if !a.goFrame {
+		a.insn("MOVD.W", XLR.goReg(), a.mem(a.StackPtr, -16))
+	}
+}
+
+func (a *goARM64) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.text(name, checkGoSymbolOptions(name, opts))
+}
+
+func (a *goARM64) text(name string, o SymbolOptions) {
+	a.goText(name, o)
+	a.goFrame = o.GoFrameSize != 0
+}
+
+func (a *goARM64) Return() {
+	a.FunctionEpilogue()
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *goARM64) ReturnWithoutEpilogue() {
+	a.insn("RET")
+	a.speculationBarrier()
+}
+
+func (a *goARM64) Address(dest Reg, name string) {
+	a.insn("MOVD", "$"+goSymbol(name), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) MoveDef(dest Reg, name string) {
+	a.insn("MOVD", "$"+goIdent(name), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) MoveImm(dest Reg, value int) {
+	a.MoveImm64(dest, uint64(int64(value)))
+}
+
+func (a *goARM64) MoveImm64(dest Reg, value uint64) {
+	a.insn("MOVD", a.imm(int(value)), a.reg(dest)) // Encoded optimally.
+	a.Set(dest)
+}
+
+func (a *goARM64) MoveReg(dest, src Reg) {
+	a.check(src)
+	if a.reg(dest) != a.reg(src) {
+		a.insn("MOVD", a.reg(src), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goARM64) MoveRegFloat(dest Reg, src FloatReg) {
+	a.insn("FMOVD", a.floatreg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) AddImm(dest, src Reg, value int) {
+	a.check(src)
+	a.insn("ADD", a.imm(value), a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) AddReg(dest, src1, src2 Reg) {
+	a.check(src1)
+	a.check(src2)
+	a.insn("ADD", a.reg(src2), a.reg(src1), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) SubtractImm(dest Reg, value int) {
+	a.check(dest)
+	if value != 0 {
+		a.insn("SUB", a.imm(value), a.reg(dest))
+	}
+	a.Set(dest)
+}
+
+func (a *goARM64) SubtractReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("SUB", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) MultiplyImm(dest, src Reg, value int, temp Reg) {
+	a.check(src)
+	a.MoveImm(temp, value)
+	a.insn("MUL", a.reg(temp), a.reg(src), a.reg(dest))
+	a.Set(dest)
+	a.Set(temp.As(""))
+}
+
+func (a *goARM64) AndImm(dest Reg, value int) {
+	a.check(dest)
+	a.insn("AND", a.imm(value), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) AndReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("AND", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) OrImm(dest Reg, value int) {
+	a.check(dest)
+	a.insn("ORR", a.imm(value), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) OrReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("ORR", a.reg(src), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) ShiftImm(s Shift, r Reg, count int) {
+	a.check(r)
+	if count != 0 {
+		a.insn(a.shift(s), a.imm(count), a.reg(r))
+	}
+	a.Set(r)
+}
+
+func (a *goARM64) Load(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVD", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) Load4Bytes(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVWU", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) LoadByte(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("MOVBU", a.mem(base, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) Store(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("MOVD", a.reg(src), a.mem(base, offset))
+}
+
+func (a *goARM64) Store4Bytes(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("MOVW", a.reg(src), a.mem(base, offset))
+}
+
+func (a *goARM64) LoadGlobal(dest Reg, name string, offset int) {
+	a.insn("MOVD", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.insn("MOVWU", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.insn("MOVBU", goSymbolOffset(name, offset), a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *goARM64) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insn("MOVD", a.reg(src), goSymbolOffset(name, offset))
+	a.Set(temp.As(""))
+}
+
+func (a *goARM64) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.check(src)
+	a.insn("MOVW", a.reg(src), goSymbolOffset(name, offset))
+	a.Set(temp.As(""))
+}
+
+func (a *goARM64) LoadThreadPointer(dest Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goARM64) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goARM64) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	panic(tlsUnsupported)
+}
+
+func (a *goARM64) Push(r Reg) {
+	a.check(r)
+	a.insn("MOVD.W", a.reg(r), a.mem(a.StackPtr, -16))
+}
+
+func (a *goARM64) Pop(r Reg) {
+	a.insn("MOVD.P", a.mem(a.StackPtr, 16), a.reg(r))
+	a.Set(r)
+}
+
+func (a *goARM64) pushPair(r1, r2 Reg) {
+	a.check(r1)
+	a.check(r2)
+	a.insn("STP.W", fmt.Sprintf("(%s, %s)", a.reg(r2), a.reg(r1)), a.mem(a.StackPtr, -16))
+}
+
+func (a *goARM64) popPair(r1, r2 Reg) {
+	a.insn("LDP.P", a.mem(a.StackPtr, 16), fmt.Sprintf("(%s, %s)", a.reg(r2), a.reg(r1)))
+	a.Set(r1)
+	a.Set(r2)
+}
+
+func (a *goARM64) Jump(name string) {
+	if global(name) {
+		a.insn("JMP", goSymbol(name))
+	} else {
+		a.insn("B", goLabel(name))
+	}
+}
+
+func (a *goARM64) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	a.check(r)
+	a.insn("JMP", "("+a.reg(r)+")")
+	a.speculationBarrier()
+}
+
+func (a *goARM64) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.jumpIf("TBNZ", "TBZ", name, a.imm(int(bit)), a.reg(r))
+}
+
+func (a *goARM64) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.jumpIf("TBZ", "TBNZ", name, a.imm(int(bit)), a.reg(r))
+}
+
+func (a *goARM64) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.check(r)
+	a.insn("CMP", a.imm(value), a.reg(r))
+	a.jumpIf("B"+a.cond(c), "B"+a.cond(inverse(c)), name)
+}
+
+func (a *goARM64) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.check(dest)
+	a.check(src)
+	a.insn("CMP", a.reg(src), a.reg(dest))
+	a.jumpIf("B"+a.cond(c), "B"+a.cond(inverse(c)), name)
+}
+
+func (a *goARM64) Call(name string) {
+	a.refer(name)
+	a.insn("BL", goSymbol(name))
+}
+
+func (a *goARM64) Syscall(nr Syscall) {
+	a.insn("MOVW", a.imm(nr.num(ARM64)), a.reg(a.SyscallNr))
+	a.insn("SVC")
+	a.Set(a.SysResult)
+}
+
+func (a *goARM64) Unreachable() {
+	a.insn("BRK")
+}
+
+func (a *goARM64) speculationBarrier() {
+	a.insn("DSB", "$15") // SY
+	a.insn("ISB", "$15")
+}
+
+func (a *goARM64) imm(x int) string {
+	return fmt.Sprintf("$%d", x)
+}
+
+func (a *goARM64) mem(base Reg, offset int) string {
+	return fmt.Sprintf("%d(%s)", offset, a.reg(base))
+}
+
+func (a *goARM64) reg(x Reg) string {
+	return x.ARM64.goReg()
+}
+
+func (a *goARM64) floatreg(x FloatReg) string {
+	return fmt.Sprintf("F%d", x)
+}
+
+// jumpIf emits a conditional branch.  The Go assembler doesn't support
+// conditional branches to TEXT symbols, so a global name is reached via an
+// unconditional jump which is skipped using the inverse condition.
+func (a *goARM64) jumpIf(mnemonic, inverse, name string, operands ...string) {
+	if global(name) {
+		a.insn(inverse, append(operands, "2(PC)")...)
+		a.insn("JMP", goSymbol(name))
+	} else {
+		a.insn(mnemonic, append(operands, goLabel(name))...)
+	}
+}
+
+func (a *goARM64) cond(x Cond) string {
+	switch x {
+	case EQ:
+		return "EQ"
+	case NE:
+		return "NE"
+	case LT:
+		return "LT"
+	case LE:
+		return "LE"
+	case GT:
+		return "GT"
+	case GE:
+		return "GE"
+	}
+
+	panic(x)
+}
+
+func (a *goARM64) shift(x Shift) string {
+	switch x {
+	case Left:
+		return "LSL"
+	case RightLogical:
+		return "LSR"
+	case RightArithmetic:
+		return "ASR"
+	}
+
+	panic(x)
+}
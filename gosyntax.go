@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+	"strings"
+)
+
+const headerGo = `// Code generated by gate.computer/ga. DO NOT EDIT.
+
+#include "textflag.h"
+`
+
+// goIdent converts name to Go identifier by replacing invalid characters.
+func goIdent(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r > 0x7f:
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// goSymbol refers to a TEXT or DATA symbol.  Global names are qualified by the
+// package; local names are private to the file.
+func goSymbol(name string) string {
+	return goSymbolOffset(name, 0)
+}
+
+func goSymbolOffset(name string, offset int) string {
+	var s string
+	if global(name) {
+		s = "·" + goIdent(name)
+	} else {
+		s = goIdent(name[1:]) + "<>"
+	}
+	if offset != 0 {
+		s += fmt.Sprintf("%+d", offset)
+	}
+	return s + "(SB)"
+}
+
+// goLabel refers to a label within the current TEXT symbol.
+func goLabel(name string) string {
+	if global(name) {
+		return goIdent(name)
+	}
+	return goIdent(name[1:])
+}
+
+// goText starts a TEXT symbol.  The stack frame is managed by the Go
+// assembler only if frame size is specified.
+func (b *buffer) goText(name string, o SymbolOptions) {
+	if o.GoFrameSize < 0 || o.GoArgSize < 0 {
+		panic(fmt.Sprintf("invalid Go frame or argument size: $%d-%d", o.GoFrameSize, o.GoArgSize))
+	}
+
+	flags := "NOSPLIT|NOFRAME"
+	if o.GoFrameSize != 0 {
+		flags = "NOSPLIT"
+	}
+
+	size := fmt.Sprintf("$%d", o.GoFrameSize)
+	if o.GoArgSize != 0 {
+		size += fmt.Sprintf("-%d", o.GoArgSize)
+	}
+
+	b.printf("")
+	b.printf("TEXT %s, %s, %s", goSymbol(name), flags, size)
+}
+
+// inverse condition.
+func inverse(c Cond) Cond {
+	switch c {
+	case EQ:
+		return NE
+	case NE:
+		return EQ
+	case LT:
+		return GE
+	case LE:
+		return GT
+	case GT:
+		return LE
+	case GE:
+		return LT
+	}
+
+	panic(c)
+}
+
+func (b *buffer) goLabel(name string) {
+	b.printf("%s:", goLabel(name))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+// goAssembleCheck verifies that the Go assembler accepts the source.
+func goAssembleCheck(t *testing.T, goarch, source string) {
+	t.Helper()
+
+	goroot, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "x_"+goarch+".s")
+	if err := os.WriteFile(filename, []byte(source), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	include := filepath.Join(strings.TrimSpace(string(goroot)), "pkg", "include")
+	cmd := exec.Command("go", "tool", "asm", "-I", include, "-p", "x", "-o", filepath.Join(dir, "x.o"), filename)
+	cmd.Env = append(os.Environ(), "GOOS=linux", "GOARCH="+goarch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go tool asm: %v\n%s\n%s", err, out, source)
+	}
+}
+
+func TestGoSyntaxAssemble(t *testing.T) {
+	for _, goarch := range []string{"amd64", "arm64"} {
+		t.Run(goarch, func(t *testing.T) {
+			a := ga.NewAssembly(ga.Archs[goarch], sys, ga.WithSyntax(ga.GoSyntax))
+			everything(a)
+			goAssembleCheck(t, goarch, "#define def 42\n"+a.String())
+		})
+	}
+}
+
+func TestGoSyntaxText(t *testing.T) {
+	for _, test := range []struct {
+		goarch string
+		want   []string
+	}{
+		{
+			goarch: "amd64",
+			want: []string{
+				"TEXT\t·f(SB), NOSPLIT|NOFRAME, $0\n",
+				"TEXT\t·g(SB), NOSPLIT, $16-8\n",
+				"JNE\t2(PC)\n\tJMP\t·f(SB)\n",
+				"JEQ\tskip\n",
+				"skip:\n",
+			},
+		},
+		{
+			goarch: "arm64",
+			want: []string{
+				"TEXT\t·f(SB), NOSPLIT|NOFRAME, $0\n\tMOVD.W\tR30, -16(RSP)\n",
+				"TEXT\t·g(SB), NOSPLIT, $16-8\n\tMOVD\t$0, R19\n",
+				"BNE\t2(PC)\n\tJMP\t·f(SB)\n",
+				"TBZ\t$3, R19, 2(PC)\n\tJMP\t·f(SB)\n",
+				"BEQ\tskip\n",
+				"skip:\n\tRET\n",
+			},
+		},
+	} {
+		t.Run(test.goarch, func(t *testing.T) {
+			a := ga.NewAssembly(ga.Archs[test.goarch], sys, ga.WithSyntax(ga.GoSyntax))
+			a.Function("f")
+			a.Return()
+			a.Function("g", ga.SymbolOptions{GoFrameSize: 16, GoArgSize: 8})
+			a.MoveImm(r0, 0)
+			a.JumpIfImm(ga.EQ, r0, 0, "f")
+			a.JumpIfBitSet(r0, 3, "f")
+			a.JumpIfImm(ga.EQ, r0, 0, ".skip")
+			a.Label(".skip")
+			a.Return()
+
+			s := a.String()
+			for _, x := range test.want {
+				if !strings.Contains(s, x) {
+					t.Errorf("%q not found in:\n%s", x, s)
+				}
+			}
+
+			goAssembleCheck(t, test.goarch, s)
+		})
+	}
+}
+
+func TestGoFrameSizeWithGNUSyntax(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic")
+		}
+	}()
+
+	a := ga.NewAssembly(ga.AMD64, sys)
+	a.Function("f", ga.SymbolOptions{GoFrameSize: 8})
+}
@@ -29,13 +29,19 @@ import (
 var (
 `
 
-const footer = `)
+const middle = `)
+
+// Syscalls by name.
+var Syscalls = map[string]ga.Syscall{
+`
+
+const footer = `}
 `
 
 func main() {
 	archSyms := make(map[string]map[string]int)
 
-	for _, arch := range []string{"AMD64", "ARM64"} {
+	for _, arch := range []string{"AMD64", "ARM64", "PPC64LE", "RISCV64"} {
 		lowarch := strings.ToLower(arch)
 		filename := path.Join(runtime.GOROOT(), "src/cmd/vendor/golang.org/x/sys/unix", fmt.Sprintf("zsysnum_linux_%s.go", lowarch))
 		syms, err := parse(filename)
@@ -80,29 +86,38 @@ func main() {
 	b := bytes.NewBuffer(nil)
 	b.WriteString(header)
 
-	for _, sym := range syms {
-		archs := symArchs[sym]
-		if len(archs) != 2 {
-			continue
-		}
+	var names []string
+	for name := range archSyms {
+		names = append(names, name)
+	}
 
-		var names []string
-		for name := range archs {
-			names = append(names, name)
-		}
+	sort.Strings(names)
 
-		sort.Strings(names)
+	for _, sym := range syms {
+		archs := symArchs[sym]
 
 		fmt.Fprintf(b, symFormat, sym)
 		sep := ""
 		for _, name := range names {
 			fmt.Fprint(b, sep)
-			fmt.Fprintf(b, "%s: %d", name, archs[name])
+			if nr, found := archs[name]; found {
+				fmt.Fprintf(b, "%s: %d", name, nr)
+			} else {
+				fmt.Fprintf(b, "%s: ga.NoSyscall", name)
+			}
 			sep = ", "
 		}
 		fmt.Fprintln(b, "}")
 	}
 
+	b.WriteString(middle)
+
+	keyFormat := fmt.Sprintf("\t%%-%ds %%s,\n", symWidth+3)
+
+	for _, sym := range syms {
+		fmt.Fprintf(b, keyFormat, fmt.Sprintf("%q:", sym), sym)
+	}
+
 	b.WriteString(footer)
 
 	if err := ioutil.WriteFile("linux/syscall.go", b.Bytes(), 0666); err != nil {
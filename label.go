@@ -0,0 +1,150 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewLabel returns a unique local name.  The hint becomes part of the name.
+// Generated names have the form ".hint.N", where N is a number.
+func (a *Assembly) NewLabel(hint string) string {
+	return a.internalLabel(hint)
+}
+
+// LabelScope makes local names unique to one invocation of a code generator
+// function, so that the function can be called any number of times:
+//
+//	func emitLoop(a *ga.Assembly, r ga.Reg) {
+//		s := a.NewScope()
+//		a.Label(s.Name("loop"))
+//		a.SubtractImm(r, 1)
+//		a.JumpIfImm(ga.NE, r, 0, s.Name("loop"))
+//	}
+type LabelScope struct {
+	id int
+}
+
+// NewScope allocates a label scope.
+func (a *Assembly) NewScope() LabelScope {
+	a.labels++
+	return LabelScope{a.labels}
+}
+
+// Name returns the same local name every time it's called with the same hint
+// and scope.  Names don't conflict with other scopes or NewLabel.
+func (s LabelScope) Name(hint string) string {
+	if s.id == 0 {
+		panic("label scope was not allocated with NewScope")
+	}
+	return fmt.Sprintf(".%s.%d", labelHint(hint), s.id)
+}
+
+// internalLabel generates a unique local name.
+func (b *buffer) internalLabel(hint string) string {
+	b.labels++
+	return fmt.Sprintf(".%s.%d", labelHint(hint), b.labels)
+}
+
+func labelHint(hint string) string {
+	if hint = strings.TrimPrefix(hint, "."); hint == "" {
+		hint = "label"
+	}
+	return hint
+}
+
+// Check for duplicate definitions and references to undefined local names.
+func (a *Assembly) Check() error {
+	var problems []string
+	for _, name := range sortedKeys(a.defined) {
+		if a.defined[name] > 1 {
+			problems = append(problems, fmt.Sprintf("duplicate definition of %s", name))
+		}
+	}
+	for _, name := range sortedKeys(a.referred) {
+		if !global(name) && a.defined[name] == 0 {
+			problems = append(problems, fmt.Sprintf("undefined local name %s", name))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Imports are the global names which are referenced but not defined.
+func (a *Assembly) Imports() (names []string) {
+	for _, name := range sortedKeys(a.referred) {
+		if global(name) && a.defined[name] == 0 {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+// define records a name definition.  Backends call it for labels which they
+// emit internally.
+func (b *buffer) define(name string) {
+	if b.defined == nil {
+		b.defined = make(map[string]int)
+	}
+	b.defined[name]++
+}
+
+// refer records a name reference.  Backends call it for calls, and for jumps
+// which they emit internally.
+func (b *buffer) refer(name string) {
+	if b.referred == nil {
+		b.referred = make(map[string]int)
+	}
+	b.referred[name]++
+}
+
+func (a *Assembly) Address(dest Reg, name string) {
+	a.refer(name)
+	a.ArchAssembly.Address(dest, name)
+}
+
+func (a *Assembly) MoveDef(dest Reg, name string) {
+	a.refer(name)
+	a.ArchAssembly.MoveDef(dest, name)
+}
+
+func (a *Assembly) LoadGlobal(dest Reg, name string, offset int) {
+	a.refer(name)
+	a.ArchAssembly.LoadGlobal(dest, name, offset)
+}
+
+func (a *Assembly) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.refer(name)
+	a.ArchAssembly.LoadGlobal4Bytes(dest, name, offset)
+}
+
+func (a *Assembly) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.refer(name)
+	a.ArchAssembly.LoadGlobalByte(dest, name, offset)
+}
+
+func (a *Assembly) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.refer(name)
+	a.ArchAssembly.StoreGlobal(name, offset, src, temp)
+}
+
+func (a *Assembly) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.refer(name)
+	a.ArchAssembly.StoreGlobal4Bytes(name, offset, src, temp)
+}
+
+func (a *Assembly) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	a.refer(name)
+	a.ArchAssembly.ThreadLocalAddress(dest, name, model, temp)
+}
+
+func (a *Assembly) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	a.refer(name)
+	a.ArchAssembly.LoadThreadLocal(dest, name, offset, model, temp)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestNewLabel(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+
+	names := make(map[string]bool)
+	for _, hint := range []string{"x", "x", ".x", "", ""} {
+		name := a.NewLabel(hint)
+		if !strings.HasPrefix(name, ".") {
+			t.Errorf("%q is not local", name)
+		}
+		if names[name] {
+			t.Errorf("duplicate name %q", name)
+		}
+		names[name] = true
+	}
+
+	if name := a.NewLabel(".hint"); !strings.HasPrefix(name, ".hint.") {
+		t.Errorf("hint is not part of %q", name)
+	}
+}
+
+func TestLabelScope(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+	s1 := a.NewScope()
+	s2 := a.NewScope()
+
+	if s1.Name("loop") != s1.Name("loop") {
+		t.Error("same scope and hint gave different names")
+	}
+	if s1.Name("loop") == s1.Name("end") {
+		t.Error("different hints gave the same name")
+	}
+	if s1.Name("loop") == s2.Name("loop") {
+		t.Error("different scopes gave the same name")
+	}
+	if name := a.NewLabel("loop"); name == s1.Name("loop") || name == s2.Name("loop") {
+		t.Errorf("NewLabel conflicts with scope: %q", name)
+	}
+
+	defer func() {
+		if x := fmt.Sprint(recover()); x != "label scope was not allocated with NewScope" {
+			t.Errorf("panic: %s", x)
+		}
+	}()
+	var zero ga.LabelScope
+	zero.Name("loop")
+}
+
+// reusedSnippets calls code generators with internal labels many times.
+func reusedSnippets(a *ga.Assembly) {
+	loop := func(r ga.Reg) {
+		s := a.NewScope()
+		a.Label(s.Name("loop"))
+		a.SubtractImm(r, 1)
+		a.JumpIfImm(ga.NE, r, 0, s.Name("loop"))
+		a.Jump(s.Name("end"))
+		a.Label(s.Name("end"))
+	}
+
+	a.Function("reused")
+	a.Set(r1)
+	for i := 0; i < 3; i++ {
+		a.MoveImm(r0, 10)
+		loop(r0)
+		a.JumpRegRoutine(r1, "reused_retpoline")
+	}
+	a.Return()
+}
+
+func TestLabelReuse(t *testing.T) {
+	for _, arch := range []ga.Arch{ga.AMD64, ga.ARM64, ga.RISCV64, ga.PPC64LE} {
+		t.Run(arch.Machine(), func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			reusedSnippets(a)
+			assembleCheck(t, arch, a.String())
+		})
+	}
+
+	t.Run("go-amd64", func(t *testing.T) {
+		a := ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.GoSyntax))
+		reusedSnippets(a)
+		goAssembleCheck(t, "amd64", a.String())
+	})
+}
+
+func TestCheck(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			a.Function("f")
+			a.Label(".dup")
+			a.Label(".dup")
+			a.Jump(".nowhere")
+			a.Call(".nofunc")
+			a.Address(r0, ".noaddr")
+			a.Call("g")
+			a.Return()
+			a.Function("f")
+			a.Return()
+
+			want := "duplicate definition of .dup; duplicate definition of f; " +
+				"undefined local name .noaddr; undefined local name .nofunc; undefined local name .nowhere"
+			if err := a.Check(); err == nil || err.Error() != want {
+				t.Errorf("error: %v", err)
+			}
+			if b, err := a.BytesChecked(); b != nil || err == nil {
+				t.Errorf("BytesChecked: %v", err)
+			}
+			if len(a.Bytes()) == 0 {
+				t.Error("Bytes is empty")
+			}
+		})
+	}
+}
+
+func TestImports(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			everything(a)
+			reusedSnippets(a)
+			if _, err := a.BytesChecked(); err != nil {
+				t.Error(err)
+			}
+			if s := strings.Join(a.Imports(), " "); s != "def everything_data" {
+				t.Errorf("imports: %s", s)
+			}
+		})
+	}
+}
+
+func TestMultiAssemblyCheck(t *testing.T) {
+	m := ga.NewMultiAssembly(sys, []string{"amd64", "arm64"}, func(a *ga.Assembly) {
+		a.Function("f")
+		a.Jump(".nowhere")
+	})
+
+	want := "amd64: undefined local name .nowhere"
+	if _, err := m.BytesChecked(); err == nil || err.Error() != want {
+		t.Errorf("BytesChecked: %v", err)
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "x.S")
+	if err := m.WriteFile(filename); err == nil || err.Error() != want {
+		t.Errorf("WriteFile: %v", err)
+	}
+	if err := m.WriteArchFiles(dir, "x"); err == nil || err.Error() != want {
+		t.Errorf("WriteArchFiles: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files were written: %v", entries)
+	}
+}
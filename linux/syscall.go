@@ -7,301 +7,886 @@ import (
 )
 
 var (
-	SYS_ACCEPT                 = ga.Syscall{AMD64: 43, ARM64: 202}
-	SYS_ACCEPT4                = ga.Syscall{AMD64: 288, ARM64: 242}
-	SYS_ACCT                   = ga.Syscall{AMD64: 163, ARM64: 89}
-	SYS_ADD_KEY                = ga.Syscall{AMD64: 248, ARM64: 217}
-	SYS_ADJTIMEX               = ga.Syscall{AMD64: 159, ARM64: 171}
-	SYS_BIND                   = ga.Syscall{AMD64: 49, ARM64: 200}
-	SYS_BPF                    = ga.Syscall{AMD64: 321, ARM64: 280}
-	SYS_BRK                    = ga.Syscall{AMD64: 12, ARM64: 214}
-	SYS_CAPGET                 = ga.Syscall{AMD64: 125, ARM64: 90}
-	SYS_CAPSET                 = ga.Syscall{AMD64: 126, ARM64: 91}
-	SYS_CHDIR                  = ga.Syscall{AMD64: 80, ARM64: 49}
-	SYS_CHROOT                 = ga.Syscall{AMD64: 161, ARM64: 51}
-	SYS_CLOCK_ADJTIME          = ga.Syscall{AMD64: 305, ARM64: 266}
-	SYS_CLOCK_GETRES           = ga.Syscall{AMD64: 229, ARM64: 114}
-	SYS_CLOCK_GETTIME          = ga.Syscall{AMD64: 228, ARM64: 113}
-	SYS_CLOCK_NANOSLEEP        = ga.Syscall{AMD64: 230, ARM64: 115}
-	SYS_CLOCK_SETTIME          = ga.Syscall{AMD64: 227, ARM64: 112}
-	SYS_CLONE                  = ga.Syscall{AMD64: 56, ARM64: 220}
-	SYS_CLONE3                 = ga.Syscall{AMD64: 435, ARM64: 435}
-	SYS_CLOSE                  = ga.Syscall{AMD64: 3, ARM64: 57}
-	SYS_CLOSE_RANGE            = ga.Syscall{AMD64: 436, ARM64: 436}
-	SYS_CONNECT                = ga.Syscall{AMD64: 42, ARM64: 203}
-	SYS_COPY_FILE_RANGE        = ga.Syscall{AMD64: 326, ARM64: 285}
-	SYS_DELETE_MODULE          = ga.Syscall{AMD64: 176, ARM64: 106}
-	SYS_DUP                    = ga.Syscall{AMD64: 32, ARM64: 23}
-	SYS_DUP3                   = ga.Syscall{AMD64: 292, ARM64: 24}
-	SYS_EPOLL_CREATE1          = ga.Syscall{AMD64: 291, ARM64: 20}
-	SYS_EPOLL_CTL              = ga.Syscall{AMD64: 233, ARM64: 21}
-	SYS_EPOLL_PWAIT            = ga.Syscall{AMD64: 281, ARM64: 22}
-	SYS_EPOLL_PWAIT2           = ga.Syscall{AMD64: 441, ARM64: 441}
-	SYS_EVENTFD2               = ga.Syscall{AMD64: 290, ARM64: 19}
-	SYS_EXECVE                 = ga.Syscall{AMD64: 59, ARM64: 221}
-	SYS_EXECVEAT               = ga.Syscall{AMD64: 322, ARM64: 281}
-	SYS_EXIT                   = ga.Syscall{AMD64: 60, ARM64: 93}
-	SYS_EXIT_GROUP             = ga.Syscall{AMD64: 231, ARM64: 94}
-	SYS_FACCESSAT              = ga.Syscall{AMD64: 269, ARM64: 48}
-	SYS_FACCESSAT2             = ga.Syscall{AMD64: 439, ARM64: 439}
-	SYS_FADVISE64              = ga.Syscall{AMD64: 221, ARM64: 223}
-	SYS_FALLOCATE              = ga.Syscall{AMD64: 285, ARM64: 47}
-	SYS_FANOTIFY_INIT          = ga.Syscall{AMD64: 300, ARM64: 262}
-	SYS_FANOTIFY_MARK          = ga.Syscall{AMD64: 301, ARM64: 263}
-	SYS_FCHDIR                 = ga.Syscall{AMD64: 81, ARM64: 50}
-	SYS_FCHMOD                 = ga.Syscall{AMD64: 91, ARM64: 52}
-	SYS_FCHMODAT               = ga.Syscall{AMD64: 268, ARM64: 53}
-	SYS_FCHOWN                 = ga.Syscall{AMD64: 93, ARM64: 55}
-	SYS_FCHOWNAT               = ga.Syscall{AMD64: 260, ARM64: 54}
-	SYS_FCNTL                  = ga.Syscall{AMD64: 72, ARM64: 25}
-	SYS_FDATASYNC              = ga.Syscall{AMD64: 75, ARM64: 83}
-	SYS_FGETXATTR              = ga.Syscall{AMD64: 193, ARM64: 10}
-	SYS_FINIT_MODULE           = ga.Syscall{AMD64: 313, ARM64: 273}
-	SYS_FLISTXATTR             = ga.Syscall{AMD64: 196, ARM64: 13}
-	SYS_FLOCK                  = ga.Syscall{AMD64: 73, ARM64: 32}
-	SYS_FREMOVEXATTR           = ga.Syscall{AMD64: 199, ARM64: 16}
-	SYS_FSCONFIG               = ga.Syscall{AMD64: 431, ARM64: 431}
-	SYS_FSETXATTR              = ga.Syscall{AMD64: 190, ARM64: 7}
-	SYS_FSMOUNT                = ga.Syscall{AMD64: 432, ARM64: 432}
-	SYS_FSOPEN                 = ga.Syscall{AMD64: 430, ARM64: 430}
-	SYS_FSPICK                 = ga.Syscall{AMD64: 433, ARM64: 433}
-	SYS_FSTAT                  = ga.Syscall{AMD64: 5, ARM64: 80}
-	SYS_FSTATFS                = ga.Syscall{AMD64: 138, ARM64: 44}
-	SYS_FSYNC                  = ga.Syscall{AMD64: 74, ARM64: 82}
-	SYS_FTRUNCATE              = ga.Syscall{AMD64: 77, ARM64: 46}
-	SYS_FUTEX                  = ga.Syscall{AMD64: 202, ARM64: 98}
-	SYS_GETCPU                 = ga.Syscall{AMD64: 309, ARM64: 168}
-	SYS_GETCWD                 = ga.Syscall{AMD64: 79, ARM64: 17}
-	SYS_GETDENTS64             = ga.Syscall{AMD64: 217, ARM64: 61}
-	SYS_GETEGID                = ga.Syscall{AMD64: 108, ARM64: 177}
-	SYS_GETEUID                = ga.Syscall{AMD64: 107, ARM64: 175}
-	SYS_GETGID                 = ga.Syscall{AMD64: 104, ARM64: 176}
-	SYS_GETGROUPS              = ga.Syscall{AMD64: 115, ARM64: 158}
-	SYS_GETITIMER              = ga.Syscall{AMD64: 36, ARM64: 102}
-	SYS_GETPEERNAME            = ga.Syscall{AMD64: 52, ARM64: 205}
-	SYS_GETPGID                = ga.Syscall{AMD64: 121, ARM64: 155}
-	SYS_GETPID                 = ga.Syscall{AMD64: 39, ARM64: 172}
-	SYS_GETPPID                = ga.Syscall{AMD64: 110, ARM64: 173}
-	SYS_GETPRIORITY            = ga.Syscall{AMD64: 140, ARM64: 141}
-	SYS_GETRANDOM              = ga.Syscall{AMD64: 318, ARM64: 278}
-	SYS_GETRESGID              = ga.Syscall{AMD64: 120, ARM64: 150}
-	SYS_GETRESUID              = ga.Syscall{AMD64: 118, ARM64: 148}
-	SYS_GETRLIMIT              = ga.Syscall{AMD64: 97, ARM64: 163}
-	SYS_GETRUSAGE              = ga.Syscall{AMD64: 98, ARM64: 165}
-	SYS_GETSID                 = ga.Syscall{AMD64: 124, ARM64: 156}
-	SYS_GETSOCKNAME            = ga.Syscall{AMD64: 51, ARM64: 204}
-	SYS_GETSOCKOPT             = ga.Syscall{AMD64: 55, ARM64: 209}
-	SYS_GETTID                 = ga.Syscall{AMD64: 186, ARM64: 178}
-	SYS_GETTIMEOFDAY           = ga.Syscall{AMD64: 96, ARM64: 169}
-	SYS_GETUID                 = ga.Syscall{AMD64: 102, ARM64: 174}
-	SYS_GETXATTR               = ga.Syscall{AMD64: 191, ARM64: 8}
-	SYS_GET_MEMPOLICY          = ga.Syscall{AMD64: 239, ARM64: 236}
-	SYS_GET_ROBUST_LIST        = ga.Syscall{AMD64: 274, ARM64: 100}
-	SYS_INIT_MODULE            = ga.Syscall{AMD64: 175, ARM64: 105}
-	SYS_INOTIFY_ADD_WATCH      = ga.Syscall{AMD64: 254, ARM64: 27}
-	SYS_INOTIFY_INIT1          = ga.Syscall{AMD64: 294, ARM64: 26}
-	SYS_INOTIFY_RM_WATCH       = ga.Syscall{AMD64: 255, ARM64: 28}
-	SYS_IOCTL                  = ga.Syscall{AMD64: 16, ARM64: 29}
-	SYS_IOPRIO_GET             = ga.Syscall{AMD64: 252, ARM64: 31}
-	SYS_IOPRIO_SET             = ga.Syscall{AMD64: 251, ARM64: 30}
-	SYS_IO_CANCEL              = ga.Syscall{AMD64: 210, ARM64: 3}
-	SYS_IO_DESTROY             = ga.Syscall{AMD64: 207, ARM64: 1}
-	SYS_IO_GETEVENTS           = ga.Syscall{AMD64: 208, ARM64: 4}
-	SYS_IO_PGETEVENTS          = ga.Syscall{AMD64: 333, ARM64: 292}
-	SYS_IO_SETUP               = ga.Syscall{AMD64: 206, ARM64: 0}
-	SYS_IO_SUBMIT              = ga.Syscall{AMD64: 209, ARM64: 2}
-	SYS_IO_URING_ENTER         = ga.Syscall{AMD64: 426, ARM64: 426}
-	SYS_IO_URING_REGISTER      = ga.Syscall{AMD64: 427, ARM64: 427}
-	SYS_IO_URING_SETUP         = ga.Syscall{AMD64: 425, ARM64: 425}
-	SYS_KCMP                   = ga.Syscall{AMD64: 312, ARM64: 272}
-	SYS_KEXEC_FILE_LOAD        = ga.Syscall{AMD64: 320, ARM64: 294}
-	SYS_KEXEC_LOAD             = ga.Syscall{AMD64: 246, ARM64: 104}
-	SYS_KEYCTL                 = ga.Syscall{AMD64: 250, ARM64: 219}
-	SYS_KILL                   = ga.Syscall{AMD64: 62, ARM64: 129}
-	SYS_LGETXATTR              = ga.Syscall{AMD64: 192, ARM64: 9}
-	SYS_LINKAT                 = ga.Syscall{AMD64: 265, ARM64: 37}
-	SYS_LISTEN                 = ga.Syscall{AMD64: 50, ARM64: 201}
-	SYS_LISTXATTR              = ga.Syscall{AMD64: 194, ARM64: 11}
-	SYS_LLISTXATTR             = ga.Syscall{AMD64: 195, ARM64: 12}
-	SYS_LOOKUP_DCOOKIE         = ga.Syscall{AMD64: 212, ARM64: 18}
-	SYS_LREMOVEXATTR           = ga.Syscall{AMD64: 198, ARM64: 15}
-	SYS_LSEEK                  = ga.Syscall{AMD64: 8, ARM64: 62}
-	SYS_LSETXATTR              = ga.Syscall{AMD64: 189, ARM64: 6}
-	SYS_MADVISE                = ga.Syscall{AMD64: 28, ARM64: 233}
-	SYS_MBIND                  = ga.Syscall{AMD64: 237, ARM64: 235}
-	SYS_MEMBARRIER             = ga.Syscall{AMD64: 324, ARM64: 283}
-	SYS_MEMFD_CREATE           = ga.Syscall{AMD64: 319, ARM64: 279}
-	SYS_MIGRATE_PAGES          = ga.Syscall{AMD64: 256, ARM64: 238}
-	SYS_MINCORE                = ga.Syscall{AMD64: 27, ARM64: 232}
-	SYS_MKDIRAT                = ga.Syscall{AMD64: 258, ARM64: 34}
-	SYS_MKNODAT                = ga.Syscall{AMD64: 259, ARM64: 33}
-	SYS_MLOCK                  = ga.Syscall{AMD64: 149, ARM64: 228}
-	SYS_MLOCK2                 = ga.Syscall{AMD64: 325, ARM64: 284}
-	SYS_MLOCKALL               = ga.Syscall{AMD64: 151, ARM64: 230}
-	SYS_MMAP                   = ga.Syscall{AMD64: 9, ARM64: 222}
-	SYS_MOUNT                  = ga.Syscall{AMD64: 165, ARM64: 40}
-	SYS_MOUNT_SETATTR          = ga.Syscall{AMD64: 442, ARM64: 442}
-	SYS_MOVE_MOUNT             = ga.Syscall{AMD64: 429, ARM64: 429}
-	SYS_MOVE_PAGES             = ga.Syscall{AMD64: 279, ARM64: 239}
-	SYS_MPROTECT               = ga.Syscall{AMD64: 10, ARM64: 226}
-	SYS_MQ_GETSETATTR          = ga.Syscall{AMD64: 245, ARM64: 185}
-	SYS_MQ_NOTIFY              = ga.Syscall{AMD64: 244, ARM64: 184}
-	SYS_MQ_OPEN                = ga.Syscall{AMD64: 240, ARM64: 180}
-	SYS_MQ_TIMEDRECEIVE        = ga.Syscall{AMD64: 243, ARM64: 183}
-	SYS_MQ_TIMEDSEND           = ga.Syscall{AMD64: 242, ARM64: 182}
-	SYS_MQ_UNLINK              = ga.Syscall{AMD64: 241, ARM64: 181}
-	SYS_MREMAP                 = ga.Syscall{AMD64: 25, ARM64: 216}
-	SYS_MSGCTL                 = ga.Syscall{AMD64: 71, ARM64: 187}
-	SYS_MSGGET                 = ga.Syscall{AMD64: 68, ARM64: 186}
-	SYS_MSGRCV                 = ga.Syscall{AMD64: 70, ARM64: 188}
-	SYS_MSGSND                 = ga.Syscall{AMD64: 69, ARM64: 189}
-	SYS_MSYNC                  = ga.Syscall{AMD64: 26, ARM64: 227}
-	SYS_MUNLOCK                = ga.Syscall{AMD64: 150, ARM64: 229}
-	SYS_MUNLOCKALL             = ga.Syscall{AMD64: 152, ARM64: 231}
-	SYS_MUNMAP                 = ga.Syscall{AMD64: 11, ARM64: 215}
-	SYS_NAME_TO_HANDLE_AT      = ga.Syscall{AMD64: 303, ARM64: 264}
-	SYS_NANOSLEEP              = ga.Syscall{AMD64: 35, ARM64: 101}
-	SYS_NFSSERVCTL             = ga.Syscall{AMD64: 180, ARM64: 42}
-	SYS_OPENAT                 = ga.Syscall{AMD64: 257, ARM64: 56}
-	SYS_OPENAT2                = ga.Syscall{AMD64: 437, ARM64: 437}
-	SYS_OPEN_BY_HANDLE_AT      = ga.Syscall{AMD64: 304, ARM64: 265}
-	SYS_OPEN_TREE              = ga.Syscall{AMD64: 428, ARM64: 428}
-	SYS_PERF_EVENT_OPEN        = ga.Syscall{AMD64: 298, ARM64: 241}
-	SYS_PERSONALITY            = ga.Syscall{AMD64: 135, ARM64: 92}
-	SYS_PIDFD_GETFD            = ga.Syscall{AMD64: 438, ARM64: 438}
-	SYS_PIDFD_OPEN             = ga.Syscall{AMD64: 434, ARM64: 434}
-	SYS_PIDFD_SEND_SIGNAL      = ga.Syscall{AMD64: 424, ARM64: 424}
-	SYS_PIPE2                  = ga.Syscall{AMD64: 293, ARM64: 59}
-	SYS_PIVOT_ROOT             = ga.Syscall{AMD64: 155, ARM64: 41}
-	SYS_PKEY_ALLOC             = ga.Syscall{AMD64: 330, ARM64: 289}
-	SYS_PKEY_FREE              = ga.Syscall{AMD64: 331, ARM64: 290}
-	SYS_PKEY_MPROTECT          = ga.Syscall{AMD64: 329, ARM64: 288}
-	SYS_PPOLL                  = ga.Syscall{AMD64: 271, ARM64: 73}
-	SYS_PRCTL                  = ga.Syscall{AMD64: 157, ARM64: 167}
-	SYS_PREAD64                = ga.Syscall{AMD64: 17, ARM64: 67}
-	SYS_PREADV                 = ga.Syscall{AMD64: 295, ARM64: 69}
-	SYS_PREADV2                = ga.Syscall{AMD64: 327, ARM64: 286}
-	SYS_PRLIMIT64              = ga.Syscall{AMD64: 302, ARM64: 261}
-	SYS_PROCESS_MADVISE        = ga.Syscall{AMD64: 440, ARM64: 440}
-	SYS_PROCESS_VM_READV       = ga.Syscall{AMD64: 310, ARM64: 270}
-	SYS_PROCESS_VM_WRITEV      = ga.Syscall{AMD64: 311, ARM64: 271}
-	SYS_PSELECT6               = ga.Syscall{AMD64: 270, ARM64: 72}
-	SYS_PTRACE                 = ga.Syscall{AMD64: 101, ARM64: 117}
-	SYS_PWRITE64               = ga.Syscall{AMD64: 18, ARM64: 68}
-	SYS_PWRITEV                = ga.Syscall{AMD64: 296, ARM64: 70}
-	SYS_PWRITEV2               = ga.Syscall{AMD64: 328, ARM64: 287}
-	SYS_QUOTACTL               = ga.Syscall{AMD64: 179, ARM64: 60}
-	SYS_READ                   = ga.Syscall{AMD64: 0, ARM64: 63}
-	SYS_READAHEAD              = ga.Syscall{AMD64: 187, ARM64: 213}
-	SYS_READLINKAT             = ga.Syscall{AMD64: 267, ARM64: 78}
-	SYS_READV                  = ga.Syscall{AMD64: 19, ARM64: 65}
-	SYS_REBOOT                 = ga.Syscall{AMD64: 169, ARM64: 142}
-	SYS_RECVFROM               = ga.Syscall{AMD64: 45, ARM64: 207}
-	SYS_RECVMMSG               = ga.Syscall{AMD64: 299, ARM64: 243}
-	SYS_RECVMSG                = ga.Syscall{AMD64: 47, ARM64: 212}
-	SYS_REMAP_FILE_PAGES       = ga.Syscall{AMD64: 216, ARM64: 234}
-	SYS_REMOVEXATTR            = ga.Syscall{AMD64: 197, ARM64: 14}
-	SYS_RENAMEAT               = ga.Syscall{AMD64: 264, ARM64: 38}
-	SYS_RENAMEAT2              = ga.Syscall{AMD64: 316, ARM64: 276}
-	SYS_REQUEST_KEY            = ga.Syscall{AMD64: 249, ARM64: 218}
-	SYS_RESTART_SYSCALL        = ga.Syscall{AMD64: 219, ARM64: 128}
-	SYS_RSEQ                   = ga.Syscall{AMD64: 334, ARM64: 293}
-	SYS_RT_SIGACTION           = ga.Syscall{AMD64: 13, ARM64: 134}
-	SYS_RT_SIGPENDING          = ga.Syscall{AMD64: 127, ARM64: 136}
-	SYS_RT_SIGPROCMASK         = ga.Syscall{AMD64: 14, ARM64: 135}
-	SYS_RT_SIGQUEUEINFO        = ga.Syscall{AMD64: 129, ARM64: 138}
-	SYS_RT_SIGRETURN           = ga.Syscall{AMD64: 15, ARM64: 139}
-	SYS_RT_SIGSUSPEND          = ga.Syscall{AMD64: 130, ARM64: 133}
-	SYS_RT_SIGTIMEDWAIT        = ga.Syscall{AMD64: 128, ARM64: 137}
-	SYS_RT_TGSIGQUEUEINFO      = ga.Syscall{AMD64: 297, ARM64: 240}
-	SYS_SCHED_GETAFFINITY      = ga.Syscall{AMD64: 204, ARM64: 123}
-	SYS_SCHED_GETATTR          = ga.Syscall{AMD64: 315, ARM64: 275}
-	SYS_SCHED_GETPARAM         = ga.Syscall{AMD64: 143, ARM64: 121}
-	SYS_SCHED_GETSCHEDULER     = ga.Syscall{AMD64: 145, ARM64: 120}
-	SYS_SCHED_GET_PRIORITY_MAX = ga.Syscall{AMD64: 146, ARM64: 125}
-	SYS_SCHED_GET_PRIORITY_MIN = ga.Syscall{AMD64: 147, ARM64: 126}
-	SYS_SCHED_RR_GET_INTERVAL  = ga.Syscall{AMD64: 148, ARM64: 127}
-	SYS_SCHED_SETAFFINITY      = ga.Syscall{AMD64: 203, ARM64: 122}
-	SYS_SCHED_SETATTR          = ga.Syscall{AMD64: 314, ARM64: 274}
-	SYS_SCHED_SETPARAM         = ga.Syscall{AMD64: 142, ARM64: 118}
-	SYS_SCHED_SETSCHEDULER     = ga.Syscall{AMD64: 144, ARM64: 119}
-	SYS_SCHED_YIELD            = ga.Syscall{AMD64: 24, ARM64: 124}
-	SYS_SECCOMP                = ga.Syscall{AMD64: 317, ARM64: 277}
-	SYS_SEMCTL                 = ga.Syscall{AMD64: 66, ARM64: 191}
-	SYS_SEMGET                 = ga.Syscall{AMD64: 64, ARM64: 190}
-	SYS_SEMOP                  = ga.Syscall{AMD64: 65, ARM64: 193}
-	SYS_SEMTIMEDOP             = ga.Syscall{AMD64: 220, ARM64: 192}
-	SYS_SENDFILE               = ga.Syscall{AMD64: 40, ARM64: 71}
-	SYS_SENDMMSG               = ga.Syscall{AMD64: 307, ARM64: 269}
-	SYS_SENDMSG                = ga.Syscall{AMD64: 46, ARM64: 211}
-	SYS_SENDTO                 = ga.Syscall{AMD64: 44, ARM64: 206}
-	SYS_SETDOMAINNAME          = ga.Syscall{AMD64: 171, ARM64: 162}
-	SYS_SETFSGID               = ga.Syscall{AMD64: 123, ARM64: 152}
-	SYS_SETFSUID               = ga.Syscall{AMD64: 122, ARM64: 151}
-	SYS_SETGID                 = ga.Syscall{AMD64: 106, ARM64: 144}
-	SYS_SETGROUPS              = ga.Syscall{AMD64: 116, ARM64: 159}
-	SYS_SETHOSTNAME            = ga.Syscall{AMD64: 170, ARM64: 161}
-	SYS_SETITIMER              = ga.Syscall{AMD64: 38, ARM64: 103}
-	SYS_SETNS                  = ga.Syscall{AMD64: 308, ARM64: 268}
-	SYS_SETPGID                = ga.Syscall{AMD64: 109, ARM64: 154}
-	SYS_SETPRIORITY            = ga.Syscall{AMD64: 141, ARM64: 140}
-	SYS_SETREGID               = ga.Syscall{AMD64: 114, ARM64: 143}
-	SYS_SETRESGID              = ga.Syscall{AMD64: 119, ARM64: 149}
-	SYS_SETRESUID              = ga.Syscall{AMD64: 117, ARM64: 147}
-	SYS_SETREUID               = ga.Syscall{AMD64: 113, ARM64: 145}
-	SYS_SETRLIMIT              = ga.Syscall{AMD64: 160, ARM64: 164}
-	SYS_SETSID                 = ga.Syscall{AMD64: 112, ARM64: 157}
-	SYS_SETSOCKOPT             = ga.Syscall{AMD64: 54, ARM64: 208}
-	SYS_SETTIMEOFDAY           = ga.Syscall{AMD64: 164, ARM64: 170}
-	SYS_SETUID                 = ga.Syscall{AMD64: 105, ARM64: 146}
-	SYS_SETXATTR               = ga.Syscall{AMD64: 188, ARM64: 5}
-	SYS_SET_MEMPOLICY          = ga.Syscall{AMD64: 238, ARM64: 237}
-	SYS_SET_ROBUST_LIST        = ga.Syscall{AMD64: 273, ARM64: 99}
-	SYS_SET_TID_ADDRESS        = ga.Syscall{AMD64: 218, ARM64: 96}
-	SYS_SHMAT                  = ga.Syscall{AMD64: 30, ARM64: 196}
-	SYS_SHMCTL                 = ga.Syscall{AMD64: 31, ARM64: 195}
-	SYS_SHMDT                  = ga.Syscall{AMD64: 67, ARM64: 197}
-	SYS_SHMGET                 = ga.Syscall{AMD64: 29, ARM64: 194}
-	SYS_SHUTDOWN               = ga.Syscall{AMD64: 48, ARM64: 210}
-	SYS_SIGALTSTACK            = ga.Syscall{AMD64: 131, ARM64: 132}
-	SYS_SIGNALFD4              = ga.Syscall{AMD64: 289, ARM64: 74}
-	SYS_SOCKET                 = ga.Syscall{AMD64: 41, ARM64: 198}
-	SYS_SOCKETPAIR             = ga.Syscall{AMD64: 53, ARM64: 199}
-	SYS_SPLICE                 = ga.Syscall{AMD64: 275, ARM64: 76}
-	SYS_STATFS                 = ga.Syscall{AMD64: 137, ARM64: 43}
-	SYS_STATX                  = ga.Syscall{AMD64: 332, ARM64: 291}
-	SYS_SWAPOFF                = ga.Syscall{AMD64: 168, ARM64: 225}
-	SYS_SWAPON                 = ga.Syscall{AMD64: 167, ARM64: 224}
-	SYS_SYMLINKAT              = ga.Syscall{AMD64: 266, ARM64: 36}
-	SYS_SYNC                   = ga.Syscall{AMD64: 162, ARM64: 81}
-	SYS_SYNCFS                 = ga.Syscall{AMD64: 306, ARM64: 267}
-	SYS_SYNC_FILE_RANGE        = ga.Syscall{AMD64: 277, ARM64: 84}
-	SYS_SYSINFO                = ga.Syscall{AMD64: 99, ARM64: 179}
-	SYS_SYSLOG                 = ga.Syscall{AMD64: 103, ARM64: 116}
-	SYS_TEE                    = ga.Syscall{AMD64: 276, ARM64: 77}
-	SYS_TGKILL                 = ga.Syscall{AMD64: 234, ARM64: 131}
-	SYS_TIMERFD_CREATE         = ga.Syscall{AMD64: 283, ARM64: 85}
-	SYS_TIMERFD_GETTIME        = ga.Syscall{AMD64: 287, ARM64: 87}
-	SYS_TIMERFD_SETTIME        = ga.Syscall{AMD64: 286, ARM64: 86}
-	SYS_TIMER_CREATE           = ga.Syscall{AMD64: 222, ARM64: 107}
-	SYS_TIMER_DELETE           = ga.Syscall{AMD64: 226, ARM64: 111}
-	SYS_TIMER_GETOVERRUN       = ga.Syscall{AMD64: 225, ARM64: 109}
-	SYS_TIMER_GETTIME          = ga.Syscall{AMD64: 224, ARM64: 108}
-	SYS_TIMER_SETTIME          = ga.Syscall{AMD64: 223, ARM64: 110}
-	SYS_TIMES                  = ga.Syscall{AMD64: 100, ARM64: 153}
-	SYS_TKILL                  = ga.Syscall{AMD64: 200, ARM64: 130}
-	SYS_TRUNCATE               = ga.Syscall{AMD64: 76, ARM64: 45}
-	SYS_UMASK                  = ga.Syscall{AMD64: 95, ARM64: 166}
-	SYS_UMOUNT2                = ga.Syscall{AMD64: 166, ARM64: 39}
-	SYS_UNAME                  = ga.Syscall{AMD64: 63, ARM64: 160}
-	SYS_UNLINKAT               = ga.Syscall{AMD64: 263, ARM64: 35}
-	SYS_UNSHARE                = ga.Syscall{AMD64: 272, ARM64: 97}
-	SYS_USERFAULTFD            = ga.Syscall{AMD64: 323, ARM64: 282}
-	SYS_UTIMENSAT              = ga.Syscall{AMD64: 280, ARM64: 88}
-	SYS_VHANGUP                = ga.Syscall{AMD64: 153, ARM64: 58}
-	SYS_VMSPLICE               = ga.Syscall{AMD64: 278, ARM64: 75}
-	SYS_WAIT4                  = ga.Syscall{AMD64: 61, ARM64: 260}
-	SYS_WAITID                 = ga.Syscall{AMD64: 247, ARM64: 95}
-	SYS_WRITE                  = ga.Syscall{AMD64: 1, ARM64: 64}
-	SYS_WRITEV                 = ga.Syscall{AMD64: 20, ARM64: 66}
+	SYS_ACCEPT                  = ga.Syscall{AMD64: 43, ARM64: 202, PPC64LE: 330, RISCV64: 202}
+	SYS_ACCEPT4                 = ga.Syscall{AMD64: 288, ARM64: 242, PPC64LE: 344, RISCV64: 242}
+	SYS_ACCESS                  = ga.Syscall{AMD64: 21, ARM64: ga.NoSyscall, PPC64LE: 33, RISCV64: ga.NoSyscall}
+	SYS_ACCT                    = ga.Syscall{AMD64: 163, ARM64: 89, PPC64LE: 51, RISCV64: 89}
+	SYS_ADD_KEY                 = ga.Syscall{AMD64: 248, ARM64: 217, PPC64LE: 269, RISCV64: 217}
+	SYS_ADJTIMEX                = ga.Syscall{AMD64: 159, ARM64: 171, PPC64LE: 124, RISCV64: 171}
+	SYS_AFS_SYSCALL             = ga.Syscall{AMD64: 183, ARM64: ga.NoSyscall, PPC64LE: 137, RISCV64: ga.NoSyscall}
+	SYS_ALARM                   = ga.Syscall{AMD64: 37, ARM64: ga.NoSyscall, PPC64LE: 27, RISCV64: ga.NoSyscall}
+	SYS_ARCH_PRCTL              = ga.Syscall{AMD64: 158, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_ARCH_SPECIFIC_SYSCALL   = ga.Syscall{AMD64: ga.NoSyscall, ARM64: 244, PPC64LE: ga.NoSyscall, RISCV64: 244}
+	SYS_BDFLUSH                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 134, RISCV64: ga.NoSyscall}
+	SYS_BIND                    = ga.Syscall{AMD64: 49, ARM64: 200, PPC64LE: 327, RISCV64: 200}
+	SYS_BPF                     = ga.Syscall{AMD64: 321, ARM64: 280, PPC64LE: 361, RISCV64: 280}
+	SYS_BREAK                   = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 17, RISCV64: ga.NoSyscall}
+	SYS_BRK                     = ga.Syscall{AMD64: 12, ARM64: 214, PPC64LE: 45, RISCV64: 214}
+	SYS_CACHESTAT               = ga.Syscall{AMD64: 451, ARM64: 451, PPC64LE: 451, RISCV64: 451}
+	SYS_CAPGET                  = ga.Syscall{AMD64: 125, ARM64: 90, PPC64LE: 183, RISCV64: 90}
+	SYS_CAPSET                  = ga.Syscall{AMD64: 126, ARM64: 91, PPC64LE: 184, RISCV64: 91}
+	SYS_CHDIR                   = ga.Syscall{AMD64: 80, ARM64: 49, PPC64LE: 12, RISCV64: 49}
+	SYS_CHMOD                   = ga.Syscall{AMD64: 90, ARM64: ga.NoSyscall, PPC64LE: 15, RISCV64: ga.NoSyscall}
+	SYS_CHOWN                   = ga.Syscall{AMD64: 92, ARM64: ga.NoSyscall, PPC64LE: 181, RISCV64: ga.NoSyscall}
+	SYS_CHROOT                  = ga.Syscall{AMD64: 161, ARM64: 51, PPC64LE: 61, RISCV64: 51}
+	SYS_CLOCK_ADJTIME           = ga.Syscall{AMD64: 305, ARM64: 266, PPC64LE: 347, RISCV64: 266}
+	SYS_CLOCK_GETRES            = ga.Syscall{AMD64: 229, ARM64: 114, PPC64LE: 247, RISCV64: 114}
+	SYS_CLOCK_GETTIME           = ga.Syscall{AMD64: 228, ARM64: 113, PPC64LE: 246, RISCV64: 113}
+	SYS_CLOCK_NANOSLEEP         = ga.Syscall{AMD64: 230, ARM64: 115, PPC64LE: 248, RISCV64: 115}
+	SYS_CLOCK_SETTIME           = ga.Syscall{AMD64: 227, ARM64: 112, PPC64LE: 245, RISCV64: 112}
+	SYS_CLONE                   = ga.Syscall{AMD64: 56, ARM64: 220, PPC64LE: 120, RISCV64: 220}
+	SYS_CLONE3                  = ga.Syscall{AMD64: 435, ARM64: 435, PPC64LE: 435, RISCV64: 435}
+	SYS_CLOSE                   = ga.Syscall{AMD64: 3, ARM64: 57, PPC64LE: 6, RISCV64: 57}
+	SYS_CLOSE_RANGE             = ga.Syscall{AMD64: 436, ARM64: 436, PPC64LE: 436, RISCV64: 436}
+	SYS_CONNECT                 = ga.Syscall{AMD64: 42, ARM64: 203, PPC64LE: 328, RISCV64: 203}
+	SYS_COPY_FILE_RANGE         = ga.Syscall{AMD64: 326, ARM64: 285, PPC64LE: 379, RISCV64: 285}
+	SYS_CREAT                   = ga.Syscall{AMD64: 85, ARM64: ga.NoSyscall, PPC64LE: 8, RISCV64: ga.NoSyscall}
+	SYS_CREATE_MODULE           = ga.Syscall{AMD64: 174, ARM64: ga.NoSyscall, PPC64LE: 127, RISCV64: ga.NoSyscall}
+	SYS_DELETE_MODULE           = ga.Syscall{AMD64: 176, ARM64: 106, PPC64LE: 129, RISCV64: 106}
+	SYS_DUP                     = ga.Syscall{AMD64: 32, ARM64: 23, PPC64LE: 41, RISCV64: 23}
+	SYS_DUP2                    = ga.Syscall{AMD64: 33, ARM64: ga.NoSyscall, PPC64LE: 63, RISCV64: ga.NoSyscall}
+	SYS_DUP3                    = ga.Syscall{AMD64: 292, ARM64: 24, PPC64LE: 316, RISCV64: 24}
+	SYS_EPOLL_CREATE            = ga.Syscall{AMD64: 213, ARM64: ga.NoSyscall, PPC64LE: 236, RISCV64: ga.NoSyscall}
+	SYS_EPOLL_CREATE1           = ga.Syscall{AMD64: 291, ARM64: 20, PPC64LE: 315, RISCV64: 20}
+	SYS_EPOLL_CTL               = ga.Syscall{AMD64: 233, ARM64: 21, PPC64LE: 237, RISCV64: 21}
+	SYS_EPOLL_CTL_OLD           = ga.Syscall{AMD64: 214, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_EPOLL_PWAIT             = ga.Syscall{AMD64: 281, ARM64: 22, PPC64LE: 303, RISCV64: 22}
+	SYS_EPOLL_PWAIT2            = ga.Syscall{AMD64: 441, ARM64: 441, PPC64LE: 441, RISCV64: 441}
+	SYS_EPOLL_WAIT              = ga.Syscall{AMD64: 232, ARM64: ga.NoSyscall, PPC64LE: 238, RISCV64: ga.NoSyscall}
+	SYS_EPOLL_WAIT_OLD          = ga.Syscall{AMD64: 215, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_EVENTFD                 = ga.Syscall{AMD64: 284, ARM64: ga.NoSyscall, PPC64LE: 307, RISCV64: ga.NoSyscall}
+	SYS_EVENTFD2                = ga.Syscall{AMD64: 290, ARM64: 19, PPC64LE: 314, RISCV64: 19}
+	SYS_EXECVE                  = ga.Syscall{AMD64: 59, ARM64: 221, PPC64LE: 11, RISCV64: 221}
+	SYS_EXECVEAT                = ga.Syscall{AMD64: 322, ARM64: 281, PPC64LE: 362, RISCV64: 281}
+	SYS_EXIT                    = ga.Syscall{AMD64: 60, ARM64: 93, PPC64LE: 1, RISCV64: 93}
+	SYS_EXIT_GROUP              = ga.Syscall{AMD64: 231, ARM64: 94, PPC64LE: 234, RISCV64: 94}
+	SYS_FACCESSAT               = ga.Syscall{AMD64: 269, ARM64: 48, PPC64LE: 298, RISCV64: 48}
+	SYS_FACCESSAT2              = ga.Syscall{AMD64: 439, ARM64: 439, PPC64LE: 439, RISCV64: 439}
+	SYS_FADVISE64               = ga.Syscall{AMD64: 221, ARM64: 223, PPC64LE: 233, RISCV64: 223}
+	SYS_FALLOCATE               = ga.Syscall{AMD64: 285, ARM64: 47, PPC64LE: 309, RISCV64: 47}
+	SYS_FANOTIFY_INIT           = ga.Syscall{AMD64: 300, ARM64: 262, PPC64LE: 323, RISCV64: 262}
+	SYS_FANOTIFY_MARK           = ga.Syscall{AMD64: 301, ARM64: 263, PPC64LE: 324, RISCV64: 263}
+	SYS_FCHDIR                  = ga.Syscall{AMD64: 81, ARM64: 50, PPC64LE: 133, RISCV64: 50}
+	SYS_FCHMOD                  = ga.Syscall{AMD64: 91, ARM64: 52, PPC64LE: 94, RISCV64: 52}
+	SYS_FCHMODAT                = ga.Syscall{AMD64: 268, ARM64: 53, PPC64LE: 297, RISCV64: 53}
+	SYS_FCHMODAT2               = ga.Syscall{AMD64: 452, ARM64: 452, PPC64LE: 452, RISCV64: 452}
+	SYS_FCHOWN                  = ga.Syscall{AMD64: 93, ARM64: 55, PPC64LE: 95, RISCV64: 55}
+	SYS_FCHOWNAT                = ga.Syscall{AMD64: 260, ARM64: 54, PPC64LE: 289, RISCV64: 54}
+	SYS_FCNTL                   = ga.Syscall{AMD64: 72, ARM64: 25, PPC64LE: 55, RISCV64: 25}
+	SYS_FDATASYNC               = ga.Syscall{AMD64: 75, ARM64: 83, PPC64LE: 148, RISCV64: 83}
+	SYS_FGETXATTR               = ga.Syscall{AMD64: 193, ARM64: 10, PPC64LE: 214, RISCV64: 10}
+	SYS_FILE_GETATTR            = ga.Syscall{AMD64: 468, ARM64: 468, PPC64LE: 468, RISCV64: 468}
+	SYS_FILE_SETATTR            = ga.Syscall{AMD64: 469, ARM64: 469, PPC64LE: 469, RISCV64: 469}
+	SYS_FINIT_MODULE            = ga.Syscall{AMD64: 313, ARM64: 273, PPC64LE: 353, RISCV64: 273}
+	SYS_FLISTXATTR              = ga.Syscall{AMD64: 196, ARM64: 13, PPC64LE: 217, RISCV64: 13}
+	SYS_FLOCK                   = ga.Syscall{AMD64: 73, ARM64: 32, PPC64LE: 143, RISCV64: 32}
+	SYS_FORK                    = ga.Syscall{AMD64: 57, ARM64: ga.NoSyscall, PPC64LE: 2, RISCV64: ga.NoSyscall}
+	SYS_FREMOVEXATTR            = ga.Syscall{AMD64: 199, ARM64: 16, PPC64LE: 220, RISCV64: 16}
+	SYS_FSCONFIG                = ga.Syscall{AMD64: 431, ARM64: 431, PPC64LE: 431, RISCV64: 431}
+	SYS_FSETXATTR               = ga.Syscall{AMD64: 190, ARM64: 7, PPC64LE: 211, RISCV64: 7}
+	SYS_FSMOUNT                 = ga.Syscall{AMD64: 432, ARM64: 432, PPC64LE: 432, RISCV64: 432}
+	SYS_FSOPEN                  = ga.Syscall{AMD64: 430, ARM64: 430, PPC64LE: 430, RISCV64: 430}
+	SYS_FSPICK                  = ga.Syscall{AMD64: 433, ARM64: 433, PPC64LE: 433, RISCV64: 433}
+	SYS_FSTAT                   = ga.Syscall{AMD64: 5, ARM64: 80, PPC64LE: 108, RISCV64: 80}
+	SYS_FSTATFS                 = ga.Syscall{AMD64: 138, ARM64: 44, PPC64LE: 100, RISCV64: 44}
+	SYS_FSTATFS64               = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 253, RISCV64: ga.NoSyscall}
+	SYS_FSYNC                   = ga.Syscall{AMD64: 74, ARM64: 82, PPC64LE: 118, RISCV64: 82}
+	SYS_FTIME                   = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 35, RISCV64: ga.NoSyscall}
+	SYS_FTRUNCATE               = ga.Syscall{AMD64: 77, ARM64: 46, PPC64LE: 93, RISCV64: 46}
+	SYS_FUTEX                   = ga.Syscall{AMD64: 202, ARM64: 98, PPC64LE: 221, RISCV64: 98}
+	SYS_FUTEX_REQUEUE           = ga.Syscall{AMD64: 456, ARM64: 456, PPC64LE: 456, RISCV64: 456}
+	SYS_FUTEX_WAIT              = ga.Syscall{AMD64: 455, ARM64: 455, PPC64LE: 455, RISCV64: 455}
+	SYS_FUTEX_WAITV             = ga.Syscall{AMD64: 449, ARM64: 449, PPC64LE: 449, RISCV64: 449}
+	SYS_FUTEX_WAKE              = ga.Syscall{AMD64: 454, ARM64: 454, PPC64LE: 454, RISCV64: 454}
+	SYS_FUTIMESAT               = ga.Syscall{AMD64: 261, ARM64: ga.NoSyscall, PPC64LE: 290, RISCV64: ga.NoSyscall}
+	SYS_GETCPU                  = ga.Syscall{AMD64: 309, ARM64: 168, PPC64LE: 302, RISCV64: 168}
+	SYS_GETCWD                  = ga.Syscall{AMD64: 79, ARM64: 17, PPC64LE: 182, RISCV64: 17}
+	SYS_GETDENTS                = ga.Syscall{AMD64: 78, ARM64: ga.NoSyscall, PPC64LE: 141, RISCV64: ga.NoSyscall}
+	SYS_GETDENTS64              = ga.Syscall{AMD64: 217, ARM64: 61, PPC64LE: 202, RISCV64: 61}
+	SYS_GETEGID                 = ga.Syscall{AMD64: 108, ARM64: 177, PPC64LE: 50, RISCV64: 177}
+	SYS_GETEUID                 = ga.Syscall{AMD64: 107, ARM64: 175, PPC64LE: 49, RISCV64: 175}
+	SYS_GETGID                  = ga.Syscall{AMD64: 104, ARM64: 176, PPC64LE: 47, RISCV64: 176}
+	SYS_GETGROUPS               = ga.Syscall{AMD64: 115, ARM64: 158, PPC64LE: 80, RISCV64: 158}
+	SYS_GETITIMER               = ga.Syscall{AMD64: 36, ARM64: 102, PPC64LE: 105, RISCV64: 102}
+	SYS_GETPEERNAME             = ga.Syscall{AMD64: 52, ARM64: 205, PPC64LE: 332, RISCV64: 205}
+	SYS_GETPGID                 = ga.Syscall{AMD64: 121, ARM64: 155, PPC64LE: 132, RISCV64: 155}
+	SYS_GETPGRP                 = ga.Syscall{AMD64: 111, ARM64: ga.NoSyscall, PPC64LE: 65, RISCV64: ga.NoSyscall}
+	SYS_GETPID                  = ga.Syscall{AMD64: 39, ARM64: 172, PPC64LE: 20, RISCV64: 172}
+	SYS_GETPMSG                 = ga.Syscall{AMD64: 181, ARM64: ga.NoSyscall, PPC64LE: 187, RISCV64: ga.NoSyscall}
+	SYS_GETPPID                 = ga.Syscall{AMD64: 110, ARM64: 173, PPC64LE: 64, RISCV64: 173}
+	SYS_GETPRIORITY             = ga.Syscall{AMD64: 140, ARM64: 141, PPC64LE: 96, RISCV64: 141}
+	SYS_GETRANDOM               = ga.Syscall{AMD64: 318, ARM64: 278, PPC64LE: 359, RISCV64: 278}
+	SYS_GETRESGID               = ga.Syscall{AMD64: 120, ARM64: 150, PPC64LE: 170, RISCV64: 150}
+	SYS_GETRESUID               = ga.Syscall{AMD64: 118, ARM64: 148, PPC64LE: 165, RISCV64: 148}
+	SYS_GETRLIMIT               = ga.Syscall{AMD64: 97, ARM64: 163, PPC64LE: 76, RISCV64: 163}
+	SYS_GETRUSAGE               = ga.Syscall{AMD64: 98, ARM64: 165, PPC64LE: 77, RISCV64: 165}
+	SYS_GETSID                  = ga.Syscall{AMD64: 124, ARM64: 156, PPC64LE: 147, RISCV64: 156}
+	SYS_GETSOCKNAME             = ga.Syscall{AMD64: 51, ARM64: 204, PPC64LE: 331, RISCV64: 204}
+	SYS_GETSOCKOPT              = ga.Syscall{AMD64: 55, ARM64: 209, PPC64LE: 340, RISCV64: 209}
+	SYS_GETTID                  = ga.Syscall{AMD64: 186, ARM64: 178, PPC64LE: 207, RISCV64: 178}
+	SYS_GETTIMEOFDAY            = ga.Syscall{AMD64: 96, ARM64: 169, PPC64LE: 78, RISCV64: 169}
+	SYS_GETUID                  = ga.Syscall{AMD64: 102, ARM64: 174, PPC64LE: 24, RISCV64: 174}
+	SYS_GETXATTR                = ga.Syscall{AMD64: 191, ARM64: 8, PPC64LE: 212, RISCV64: 8}
+	SYS_GETXATTRAT              = ga.Syscall{AMD64: 464, ARM64: 464, PPC64LE: 464, RISCV64: 464}
+	SYS_GET_KERNEL_SYMS         = ga.Syscall{AMD64: 177, ARM64: ga.NoSyscall, PPC64LE: 130, RISCV64: ga.NoSyscall}
+	SYS_GET_MEMPOLICY           = ga.Syscall{AMD64: 239, ARM64: 236, PPC64LE: 260, RISCV64: 236}
+	SYS_GET_ROBUST_LIST         = ga.Syscall{AMD64: 274, ARM64: 100, PPC64LE: 299, RISCV64: 100}
+	SYS_GET_THREAD_AREA         = ga.Syscall{AMD64: 211, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_GTTY                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 32, RISCV64: ga.NoSyscall}
+	SYS_IDLE                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 112, RISCV64: ga.NoSyscall}
+	SYS_INIT_MODULE             = ga.Syscall{AMD64: 175, ARM64: 105, PPC64LE: 128, RISCV64: 105}
+	SYS_INOTIFY_ADD_WATCH       = ga.Syscall{AMD64: 254, ARM64: 27, PPC64LE: 276, RISCV64: 27}
+	SYS_INOTIFY_INIT            = ga.Syscall{AMD64: 253, ARM64: ga.NoSyscall, PPC64LE: 275, RISCV64: ga.NoSyscall}
+	SYS_INOTIFY_INIT1           = ga.Syscall{AMD64: 294, ARM64: 26, PPC64LE: 318, RISCV64: 26}
+	SYS_INOTIFY_RM_WATCH        = ga.Syscall{AMD64: 255, ARM64: 28, PPC64LE: 277, RISCV64: 28}
+	SYS_IOCTL                   = ga.Syscall{AMD64: 16, ARM64: 29, PPC64LE: 54, RISCV64: 29}
+	SYS_IOPERM                  = ga.Syscall{AMD64: 173, ARM64: ga.NoSyscall, PPC64LE: 101, RISCV64: ga.NoSyscall}
+	SYS_IOPL                    = ga.Syscall{AMD64: 172, ARM64: ga.NoSyscall, PPC64LE: 110, RISCV64: ga.NoSyscall}
+	SYS_IOPRIO_GET              = ga.Syscall{AMD64: 252, ARM64: 31, PPC64LE: 274, RISCV64: 31}
+	SYS_IOPRIO_SET              = ga.Syscall{AMD64: 251, ARM64: 30, PPC64LE: 273, RISCV64: 30}
+	SYS_IO_CANCEL               = ga.Syscall{AMD64: 210, ARM64: 3, PPC64LE: 231, RISCV64: 3}
+	SYS_IO_DESTROY              = ga.Syscall{AMD64: 207, ARM64: 1, PPC64LE: 228, RISCV64: 1}
+	SYS_IO_GETEVENTS            = ga.Syscall{AMD64: 208, ARM64: 4, PPC64LE: 229, RISCV64: 4}
+	SYS_IO_PGETEVENTS           = ga.Syscall{AMD64: 333, ARM64: 292, PPC64LE: 388, RISCV64: 292}
+	SYS_IO_SETUP                = ga.Syscall{AMD64: 206, ARM64: 0, PPC64LE: 227, RISCV64: 0}
+	SYS_IO_SUBMIT               = ga.Syscall{AMD64: 209, ARM64: 2, PPC64LE: 230, RISCV64: 2}
+	SYS_IO_URING_ENTER          = ga.Syscall{AMD64: 426, ARM64: 426, PPC64LE: 426, RISCV64: 426}
+	SYS_IO_URING_REGISTER       = ga.Syscall{AMD64: 427, ARM64: 427, PPC64LE: 427, RISCV64: 427}
+	SYS_IO_URING_SETUP          = ga.Syscall{AMD64: 425, ARM64: 425, PPC64LE: 425, RISCV64: 425}
+	SYS_IPC                     = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 117, RISCV64: ga.NoSyscall}
+	SYS_KCMP                    = ga.Syscall{AMD64: 312, ARM64: 272, PPC64LE: 354, RISCV64: 272}
+	SYS_KEXEC_FILE_LOAD         = ga.Syscall{AMD64: 320, ARM64: 294, PPC64LE: 382, RISCV64: 294}
+	SYS_KEXEC_LOAD              = ga.Syscall{AMD64: 246, ARM64: 104, PPC64LE: 268, RISCV64: 104}
+	SYS_KEYCTL                  = ga.Syscall{AMD64: 250, ARM64: 219, PPC64LE: 271, RISCV64: 219}
+	SYS_KILL                    = ga.Syscall{AMD64: 62, ARM64: 129, PPC64LE: 37, RISCV64: 129}
+	SYS_LANDLOCK_ADD_RULE       = ga.Syscall{AMD64: 445, ARM64: 445, PPC64LE: 445, RISCV64: 445}
+	SYS_LANDLOCK_CREATE_RULESET = ga.Syscall{AMD64: 444, ARM64: 444, PPC64LE: 444, RISCV64: 444}
+	SYS_LANDLOCK_RESTRICT_SELF  = ga.Syscall{AMD64: 446, ARM64: 446, PPC64LE: 446, RISCV64: 446}
+	SYS_LCHOWN                  = ga.Syscall{AMD64: 94, ARM64: ga.NoSyscall, PPC64LE: 16, RISCV64: ga.NoSyscall}
+	SYS_LGETXATTR               = ga.Syscall{AMD64: 192, ARM64: 9, PPC64LE: 213, RISCV64: 9}
+	SYS_LINK                    = ga.Syscall{AMD64: 86, ARM64: ga.NoSyscall, PPC64LE: 9, RISCV64: ga.NoSyscall}
+	SYS_LINKAT                  = ga.Syscall{AMD64: 265, ARM64: 37, PPC64LE: 294, RISCV64: 37}
+	SYS_LISTEN                  = ga.Syscall{AMD64: 50, ARM64: 201, PPC64LE: 329, RISCV64: 201}
+	SYS_LISTMOUNT               = ga.Syscall{AMD64: 458, ARM64: 458, PPC64LE: 458, RISCV64: 458}
+	SYS_LISTNS                  = ga.Syscall{AMD64: 470, ARM64: 470, PPC64LE: 470, RISCV64: 470}
+	SYS_LISTXATTR               = ga.Syscall{AMD64: 194, ARM64: 11, PPC64LE: 215, RISCV64: 11}
+	SYS_LISTXATTRAT             = ga.Syscall{AMD64: 465, ARM64: 465, PPC64LE: 465, RISCV64: 465}
+	SYS_LLISTXATTR              = ga.Syscall{AMD64: 195, ARM64: 12, PPC64LE: 216, RISCV64: 12}
+	SYS_LOCK                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 53, RISCV64: ga.NoSyscall}
+	SYS_LOOKUP_DCOOKIE          = ga.Syscall{AMD64: 212, ARM64: 18, PPC64LE: 235, RISCV64: 18}
+	SYS_LREMOVEXATTR            = ga.Syscall{AMD64: 198, ARM64: 15, PPC64LE: 219, RISCV64: 15}
+	SYS_LSEEK                   = ga.Syscall{AMD64: 8, ARM64: 62, PPC64LE: 19, RISCV64: 62}
+	SYS_LSETXATTR               = ga.Syscall{AMD64: 189, ARM64: 6, PPC64LE: 210, RISCV64: 6}
+	SYS_LSM_GET_SELF_ATTR       = ga.Syscall{AMD64: 459, ARM64: 459, PPC64LE: 459, RISCV64: 459}
+	SYS_LSM_LIST_MODULES        = ga.Syscall{AMD64: 461, ARM64: 461, PPC64LE: 461, RISCV64: 461}
+	SYS_LSM_SET_SELF_ATTR       = ga.Syscall{AMD64: 460, ARM64: 460, PPC64LE: 460, RISCV64: 460}
+	SYS_LSTAT                   = ga.Syscall{AMD64: 6, ARM64: ga.NoSyscall, PPC64LE: 107, RISCV64: ga.NoSyscall}
+	SYS_MADVISE                 = ga.Syscall{AMD64: 28, ARM64: 233, PPC64LE: 205, RISCV64: 233}
+	SYS_MAP_SHADOW_STACK        = ga.Syscall{AMD64: 453, ARM64: 453, PPC64LE: 453, RISCV64: 453}
+	SYS_MBIND                   = ga.Syscall{AMD64: 237, ARM64: 235, PPC64LE: 259, RISCV64: 235}
+	SYS_MEMBARRIER              = ga.Syscall{AMD64: 324, ARM64: 283, PPC64LE: 365, RISCV64: 283}
+	SYS_MEMFD_CREATE            = ga.Syscall{AMD64: 319, ARM64: 279, PPC64LE: 360, RISCV64: 279}
+	SYS_MEMFD_SECRET            = ga.Syscall{AMD64: 447, ARM64: 447, PPC64LE: ga.NoSyscall, RISCV64: 447}
+	SYS_MIGRATE_PAGES           = ga.Syscall{AMD64: 256, ARM64: 238, PPC64LE: 258, RISCV64: 238}
+	SYS_MINCORE                 = ga.Syscall{AMD64: 27, ARM64: 232, PPC64LE: 206, RISCV64: 232}
+	SYS_MKDIR                   = ga.Syscall{AMD64: 83, ARM64: ga.NoSyscall, PPC64LE: 39, RISCV64: ga.NoSyscall}
+	SYS_MKDIRAT                 = ga.Syscall{AMD64: 258, ARM64: 34, PPC64LE: 287, RISCV64: 34}
+	SYS_MKNOD                   = ga.Syscall{AMD64: 133, ARM64: ga.NoSyscall, PPC64LE: 14, RISCV64: ga.NoSyscall}
+	SYS_MKNODAT                 = ga.Syscall{AMD64: 259, ARM64: 33, PPC64LE: 288, RISCV64: 33}
+	SYS_MLOCK                   = ga.Syscall{AMD64: 149, ARM64: 228, PPC64LE: 150, RISCV64: 228}
+	SYS_MLOCK2                  = ga.Syscall{AMD64: 325, ARM64: 284, PPC64LE: 378, RISCV64: 284}
+	SYS_MLOCKALL                = ga.Syscall{AMD64: 151, ARM64: 230, PPC64LE: 152, RISCV64: 230}
+	SYS_MMAP                    = ga.Syscall{AMD64: 9, ARM64: 222, PPC64LE: 90, RISCV64: 222}
+	SYS_MODIFY_LDT              = ga.Syscall{AMD64: 154, ARM64: ga.NoSyscall, PPC64LE: 123, RISCV64: ga.NoSyscall}
+	SYS_MOUNT                   = ga.Syscall{AMD64: 165, ARM64: 40, PPC64LE: 21, RISCV64: 40}
+	SYS_MOUNT_SETATTR           = ga.Syscall{AMD64: 442, ARM64: 442, PPC64LE: 442, RISCV64: 442}
+	SYS_MOVE_MOUNT              = ga.Syscall{AMD64: 429, ARM64: 429, PPC64LE: 429, RISCV64: 429}
+	SYS_MOVE_PAGES              = ga.Syscall{AMD64: 279, ARM64: 239, PPC64LE: 301, RISCV64: 239}
+	SYS_MPROTECT                = ga.Syscall{AMD64: 10, ARM64: 226, PPC64LE: 125, RISCV64: 226}
+	SYS_MPX                     = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 56, RISCV64: ga.NoSyscall}
+	SYS_MQ_GETSETATTR           = ga.Syscall{AMD64: 245, ARM64: 185, PPC64LE: 267, RISCV64: 185}
+	SYS_MQ_NOTIFY               = ga.Syscall{AMD64: 244, ARM64: 184, PPC64LE: 266, RISCV64: 184}
+	SYS_MQ_OPEN                 = ga.Syscall{AMD64: 240, ARM64: 180, PPC64LE: 262, RISCV64: 180}
+	SYS_MQ_TIMEDRECEIVE         = ga.Syscall{AMD64: 243, ARM64: 183, PPC64LE: 265, RISCV64: 183}
+	SYS_MQ_TIMEDSEND            = ga.Syscall{AMD64: 242, ARM64: 182, PPC64LE: 264, RISCV64: 182}
+	SYS_MQ_UNLINK               = ga.Syscall{AMD64: 241, ARM64: 181, PPC64LE: 263, RISCV64: 181}
+	SYS_MREMAP                  = ga.Syscall{AMD64: 25, ARM64: 216, PPC64LE: 163, RISCV64: 216}
+	SYS_MSEAL                   = ga.Syscall{AMD64: 462, ARM64: 462, PPC64LE: 462, RISCV64: 462}
+	SYS_MSGCTL                  = ga.Syscall{AMD64: 71, ARM64: 187, PPC64LE: 402, RISCV64: 187}
+	SYS_MSGGET                  = ga.Syscall{AMD64: 68, ARM64: 186, PPC64LE: 399, RISCV64: 186}
+	SYS_MSGRCV                  = ga.Syscall{AMD64: 70, ARM64: 188, PPC64LE: 401, RISCV64: 188}
+	SYS_MSGSND                  = ga.Syscall{AMD64: 69, ARM64: 189, PPC64LE: 400, RISCV64: 189}
+	SYS_MSYNC                   = ga.Syscall{AMD64: 26, ARM64: 227, PPC64LE: 144, RISCV64: 227}
+	SYS_MULTIPLEXER             = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 201, RISCV64: ga.NoSyscall}
+	SYS_MUNLOCK                 = ga.Syscall{AMD64: 150, ARM64: 229, PPC64LE: 151, RISCV64: 229}
+	SYS_MUNLOCKALL              = ga.Syscall{AMD64: 152, ARM64: 231, PPC64LE: 153, RISCV64: 231}
+	SYS_MUNMAP                  = ga.Syscall{AMD64: 11, ARM64: 215, PPC64LE: 91, RISCV64: 215}
+	SYS_NAME_TO_HANDLE_AT       = ga.Syscall{AMD64: 303, ARM64: 264, PPC64LE: 345, RISCV64: 264}
+	SYS_NANOSLEEP               = ga.Syscall{AMD64: 35, ARM64: 101, PPC64LE: 162, RISCV64: 101}
+	SYS_NEWFSTATAT              = ga.Syscall{AMD64: 262, ARM64: 79, PPC64LE: 291, RISCV64: 79}
+	SYS_NFSSERVCTL              = ga.Syscall{AMD64: 180, ARM64: 42, PPC64LE: 168, RISCV64: 42}
+	SYS_NICE                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 34, RISCV64: ga.NoSyscall}
+	SYS_OLDFSTAT                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 28, RISCV64: ga.NoSyscall}
+	SYS_OLDLSTAT                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 84, RISCV64: ga.NoSyscall}
+	SYS_OLDOLDUNAME             = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 59, RISCV64: ga.NoSyscall}
+	SYS_OLDSTAT                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 18, RISCV64: ga.NoSyscall}
+	SYS_OLDUNAME                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 109, RISCV64: ga.NoSyscall}
+	SYS_OPEN                    = ga.Syscall{AMD64: 2, ARM64: ga.NoSyscall, PPC64LE: 5, RISCV64: ga.NoSyscall}
+	SYS_OPENAT                  = ga.Syscall{AMD64: 257, ARM64: 56, PPC64LE: 286, RISCV64: 56}
+	SYS_OPENAT2                 = ga.Syscall{AMD64: 437, ARM64: 437, PPC64LE: 437, RISCV64: 437}
+	SYS_OPEN_BY_HANDLE_AT       = ga.Syscall{AMD64: 304, ARM64: 265, PPC64LE: 346, RISCV64: 265}
+	SYS_OPEN_TREE               = ga.Syscall{AMD64: 428, ARM64: 428, PPC64LE: 428, RISCV64: 428}
+	SYS_OPEN_TREE_ATTR          = ga.Syscall{AMD64: 467, ARM64: 467, PPC64LE: 467, RISCV64: 467}
+	SYS_PAUSE                   = ga.Syscall{AMD64: 34, ARM64: ga.NoSyscall, PPC64LE: 29, RISCV64: ga.NoSyscall}
+	SYS_PCICONFIG_IOBASE        = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 200, RISCV64: ga.NoSyscall}
+	SYS_PCICONFIG_READ          = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 198, RISCV64: ga.NoSyscall}
+	SYS_PCICONFIG_WRITE         = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 199, RISCV64: ga.NoSyscall}
+	SYS_PERF_EVENT_OPEN         = ga.Syscall{AMD64: 298, ARM64: 241, PPC64LE: 319, RISCV64: 241}
+	SYS_PERSONALITY             = ga.Syscall{AMD64: 135, ARM64: 92, PPC64LE: 136, RISCV64: 92}
+	SYS_PIDFD_GETFD             = ga.Syscall{AMD64: 438, ARM64: 438, PPC64LE: 438, RISCV64: 438}
+	SYS_PIDFD_OPEN              = ga.Syscall{AMD64: 434, ARM64: 434, PPC64LE: 434, RISCV64: 434}
+	SYS_PIDFD_SEND_SIGNAL       = ga.Syscall{AMD64: 424, ARM64: 424, PPC64LE: 424, RISCV64: 424}
+	SYS_PIPE                    = ga.Syscall{AMD64: 22, ARM64: ga.NoSyscall, PPC64LE: 42, RISCV64: ga.NoSyscall}
+	SYS_PIPE2                   = ga.Syscall{AMD64: 293, ARM64: 59, PPC64LE: 317, RISCV64: 59}
+	SYS_PIVOT_ROOT              = ga.Syscall{AMD64: 155, ARM64: 41, PPC64LE: 203, RISCV64: 41}
+	SYS_PKEY_ALLOC              = ga.Syscall{AMD64: 330, ARM64: 289, PPC64LE: 384, RISCV64: 289}
+	SYS_PKEY_FREE               = ga.Syscall{AMD64: 331, ARM64: 290, PPC64LE: 385, RISCV64: 290}
+	SYS_PKEY_MPROTECT           = ga.Syscall{AMD64: 329, ARM64: 288, PPC64LE: 386, RISCV64: 288}
+	SYS_POLL                    = ga.Syscall{AMD64: 7, ARM64: ga.NoSyscall, PPC64LE: 167, RISCV64: ga.NoSyscall}
+	SYS_PPOLL                   = ga.Syscall{AMD64: 271, ARM64: 73, PPC64LE: 281, RISCV64: 73}
+	SYS_PRCTL                   = ga.Syscall{AMD64: 157, ARM64: 167, PPC64LE: 171, RISCV64: 167}
+	SYS_PREAD64                 = ga.Syscall{AMD64: 17, ARM64: 67, PPC64LE: 179, RISCV64: 67}
+	SYS_PREADV                  = ga.Syscall{AMD64: 295, ARM64: 69, PPC64LE: 320, RISCV64: 69}
+	SYS_PREADV2                 = ga.Syscall{AMD64: 327, ARM64: 286, PPC64LE: 380, RISCV64: 286}
+	SYS_PRLIMIT64               = ga.Syscall{AMD64: 302, ARM64: 261, PPC64LE: 325, RISCV64: 261}
+	SYS_PROCESS_MADVISE         = ga.Syscall{AMD64: 440, ARM64: 440, PPC64LE: 440, RISCV64: 440}
+	SYS_PROCESS_MRELEASE        = ga.Syscall{AMD64: 448, ARM64: 448, PPC64LE: 448, RISCV64: 448}
+	SYS_PROCESS_VM_READV        = ga.Syscall{AMD64: 310, ARM64: 270, PPC64LE: 351, RISCV64: 270}
+	SYS_PROCESS_VM_WRITEV       = ga.Syscall{AMD64: 311, ARM64: 271, PPC64LE: 352, RISCV64: 271}
+	SYS_PROF                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 44, RISCV64: ga.NoSyscall}
+	SYS_PROFIL                  = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 98, RISCV64: ga.NoSyscall}
+	SYS_PSELECT6                = ga.Syscall{AMD64: 270, ARM64: 72, PPC64LE: 280, RISCV64: 72}
+	SYS_PTRACE                  = ga.Syscall{AMD64: 101, ARM64: 117, PPC64LE: 26, RISCV64: 117}
+	SYS_PUTPMSG                 = ga.Syscall{AMD64: 182, ARM64: ga.NoSyscall, PPC64LE: 188, RISCV64: ga.NoSyscall}
+	SYS_PWRITE64                = ga.Syscall{AMD64: 18, ARM64: 68, PPC64LE: 180, RISCV64: 68}
+	SYS_PWRITEV                 = ga.Syscall{AMD64: 296, ARM64: 70, PPC64LE: 321, RISCV64: 70}
+	SYS_PWRITEV2                = ga.Syscall{AMD64: 328, ARM64: 287, PPC64LE: 381, RISCV64: 287}
+	SYS_QUERY_MODULE            = ga.Syscall{AMD64: 178, ARM64: ga.NoSyscall, PPC64LE: 166, RISCV64: ga.NoSyscall}
+	SYS_QUOTACTL                = ga.Syscall{AMD64: 179, ARM64: 60, PPC64LE: 131, RISCV64: 60}
+	SYS_QUOTACTL_FD             = ga.Syscall{AMD64: 443, ARM64: 443, PPC64LE: 443, RISCV64: 443}
+	SYS_READ                    = ga.Syscall{AMD64: 0, ARM64: 63, PPC64LE: 3, RISCV64: 63}
+	SYS_READAHEAD               = ga.Syscall{AMD64: 187, ARM64: 213, PPC64LE: 191, RISCV64: 213}
+	SYS_READDIR                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 89, RISCV64: ga.NoSyscall}
+	SYS_READLINK                = ga.Syscall{AMD64: 89, ARM64: ga.NoSyscall, PPC64LE: 85, RISCV64: ga.NoSyscall}
+	SYS_READLINKAT              = ga.Syscall{AMD64: 267, ARM64: 78, PPC64LE: 296, RISCV64: 78}
+	SYS_READV                   = ga.Syscall{AMD64: 19, ARM64: 65, PPC64LE: 145, RISCV64: 65}
+	SYS_REBOOT                  = ga.Syscall{AMD64: 169, ARM64: 142, PPC64LE: 88, RISCV64: 142}
+	SYS_RECV                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 336, RISCV64: ga.NoSyscall}
+	SYS_RECVFROM                = ga.Syscall{AMD64: 45, ARM64: 207, PPC64LE: 337, RISCV64: 207}
+	SYS_RECVMMSG                = ga.Syscall{AMD64: 299, ARM64: 243, PPC64LE: 343, RISCV64: 243}
+	SYS_RECVMSG                 = ga.Syscall{AMD64: 47, ARM64: 212, PPC64LE: 342, RISCV64: 212}
+	SYS_REMAP_FILE_PAGES        = ga.Syscall{AMD64: 216, ARM64: 234, PPC64LE: 239, RISCV64: 234}
+	SYS_REMOVEXATTR             = ga.Syscall{AMD64: 197, ARM64: 14, PPC64LE: 218, RISCV64: 14}
+	SYS_REMOVEXATTRAT           = ga.Syscall{AMD64: 466, ARM64: 466, PPC64LE: 466, RISCV64: 466}
+	SYS_RENAME                  = ga.Syscall{AMD64: 82, ARM64: ga.NoSyscall, PPC64LE: 38, RISCV64: ga.NoSyscall}
+	SYS_RENAMEAT                = ga.Syscall{AMD64: 264, ARM64: 38, PPC64LE: 293, RISCV64: ga.NoSyscall}
+	SYS_RENAMEAT2               = ga.Syscall{AMD64: 316, ARM64: 276, PPC64LE: 357, RISCV64: 276}
+	SYS_REQUEST_KEY             = ga.Syscall{AMD64: 249, ARM64: 218, PPC64LE: 270, RISCV64: 218}
+	SYS_RESTART_SYSCALL         = ga.Syscall{AMD64: 219, ARM64: 128, PPC64LE: 0, RISCV64: 128}
+	SYS_RISCV_FLUSH_ICACHE      = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: 259}
+	SYS_RISCV_HWPROBE           = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: 258}
+	SYS_RMDIR                   = ga.Syscall{AMD64: 84, ARM64: ga.NoSyscall, PPC64LE: 40, RISCV64: ga.NoSyscall}
+	SYS_RSEQ                    = ga.Syscall{AMD64: 334, ARM64: 293, PPC64LE: 387, RISCV64: 293}
+	SYS_RSEQ_SLICE_YIELD        = ga.Syscall{AMD64: 471, ARM64: 471, PPC64LE: 471, RISCV64: 471}
+	SYS_RTAS                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 255, RISCV64: ga.NoSyscall}
+	SYS_RT_SIGACTION            = ga.Syscall{AMD64: 13, ARM64: 134, PPC64LE: 173, RISCV64: 134}
+	SYS_RT_SIGPENDING           = ga.Syscall{AMD64: 127, ARM64: 136, PPC64LE: 175, RISCV64: 136}
+	SYS_RT_SIGPROCMASK          = ga.Syscall{AMD64: 14, ARM64: 135, PPC64LE: 174, RISCV64: 135}
+	SYS_RT_SIGQUEUEINFO         = ga.Syscall{AMD64: 129, ARM64: 138, PPC64LE: 177, RISCV64: 138}
+	SYS_RT_SIGRETURN            = ga.Syscall{AMD64: 15, ARM64: 139, PPC64LE: 172, RISCV64: 139}
+	SYS_RT_SIGSUSPEND           = ga.Syscall{AMD64: 130, ARM64: 133, PPC64LE: 178, RISCV64: 133}
+	SYS_RT_SIGTIMEDWAIT         = ga.Syscall{AMD64: 128, ARM64: 137, PPC64LE: 176, RISCV64: 137}
+	SYS_RT_TGSIGQUEUEINFO       = ga.Syscall{AMD64: 297, ARM64: 240, PPC64LE: 322, RISCV64: 240}
+	SYS_SCHED_GETAFFINITY       = ga.Syscall{AMD64: 204, ARM64: 123, PPC64LE: 223, RISCV64: 123}
+	SYS_SCHED_GETATTR           = ga.Syscall{AMD64: 315, ARM64: 275, PPC64LE: 356, RISCV64: 275}
+	SYS_SCHED_GETPARAM          = ga.Syscall{AMD64: 143, ARM64: 121, PPC64LE: 155, RISCV64: 121}
+	SYS_SCHED_GETSCHEDULER      = ga.Syscall{AMD64: 145, ARM64: 120, PPC64LE: 157, RISCV64: 120}
+	SYS_SCHED_GET_PRIORITY_MAX  = ga.Syscall{AMD64: 146, ARM64: 125, PPC64LE: 159, RISCV64: 125}
+	SYS_SCHED_GET_PRIORITY_MIN  = ga.Syscall{AMD64: 147, ARM64: 126, PPC64LE: 160, RISCV64: 126}
+	SYS_SCHED_RR_GET_INTERVAL   = ga.Syscall{AMD64: 148, ARM64: 127, PPC64LE: 161, RISCV64: 127}
+	SYS_SCHED_SETAFFINITY       = ga.Syscall{AMD64: 203, ARM64: 122, PPC64LE: 222, RISCV64: 122}
+	SYS_SCHED_SETATTR           = ga.Syscall{AMD64: 314, ARM64: 274, PPC64LE: 355, RISCV64: 274}
+	SYS_SCHED_SETPARAM          = ga.Syscall{AMD64: 142, ARM64: 118, PPC64LE: 154, RISCV64: 118}
+	SYS_SCHED_SETSCHEDULER      = ga.Syscall{AMD64: 144, ARM64: 119, PPC64LE: 156, RISCV64: 119}
+	SYS_SCHED_YIELD             = ga.Syscall{AMD64: 24, ARM64: 124, PPC64LE: 158, RISCV64: 124}
+	SYS_SECCOMP                 = ga.Syscall{AMD64: 317, ARM64: 277, PPC64LE: 358, RISCV64: 277}
+	SYS_SECURITY                = ga.Syscall{AMD64: 185, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_SELECT                  = ga.Syscall{AMD64: 23, ARM64: ga.NoSyscall, PPC64LE: 82, RISCV64: ga.NoSyscall}
+	SYS_SEMCTL                  = ga.Syscall{AMD64: 66, ARM64: 191, PPC64LE: 394, RISCV64: 191}
+	SYS_SEMGET                  = ga.Syscall{AMD64: 64, ARM64: 190, PPC64LE: 393, RISCV64: 190}
+	SYS_SEMOP                   = ga.Syscall{AMD64: 65, ARM64: 193, PPC64LE: ga.NoSyscall, RISCV64: 193}
+	SYS_SEMTIMEDOP              = ga.Syscall{AMD64: 220, ARM64: 192, PPC64LE: 392, RISCV64: 192}
+	SYS_SEND                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 334, RISCV64: ga.NoSyscall}
+	SYS_SENDFILE                = ga.Syscall{AMD64: 40, ARM64: 71, PPC64LE: 186, RISCV64: 71}
+	SYS_SENDMMSG                = ga.Syscall{AMD64: 307, ARM64: 269, PPC64LE: 349, RISCV64: 269}
+	SYS_SENDMSG                 = ga.Syscall{AMD64: 46, ARM64: 211, PPC64LE: 341, RISCV64: 211}
+	SYS_SENDTO                  = ga.Syscall{AMD64: 44, ARM64: 206, PPC64LE: 335, RISCV64: 206}
+	SYS_SETDOMAINNAME           = ga.Syscall{AMD64: 171, ARM64: 162, PPC64LE: 121, RISCV64: 162}
+	SYS_SETFSGID                = ga.Syscall{AMD64: 123, ARM64: 152, PPC64LE: 139, RISCV64: 152}
+	SYS_SETFSUID                = ga.Syscall{AMD64: 122, ARM64: 151, PPC64LE: 138, RISCV64: 151}
+	SYS_SETGID                  = ga.Syscall{AMD64: 106, ARM64: 144, PPC64LE: 46, RISCV64: 144}
+	SYS_SETGROUPS               = ga.Syscall{AMD64: 116, ARM64: 159, PPC64LE: 81, RISCV64: 159}
+	SYS_SETHOSTNAME             = ga.Syscall{AMD64: 170, ARM64: 161, PPC64LE: 74, RISCV64: 161}
+	SYS_SETITIMER               = ga.Syscall{AMD64: 38, ARM64: 103, PPC64LE: 104, RISCV64: 103}
+	SYS_SETNS                   = ga.Syscall{AMD64: 308, ARM64: 268, PPC64LE: 350, RISCV64: 268}
+	SYS_SETPGID                 = ga.Syscall{AMD64: 109, ARM64: 154, PPC64LE: 57, RISCV64: 154}
+	SYS_SETPRIORITY             = ga.Syscall{AMD64: 141, ARM64: 140, PPC64LE: 97, RISCV64: 140}
+	SYS_SETREGID                = ga.Syscall{AMD64: 114, ARM64: 143, PPC64LE: 71, RISCV64: 143}
+	SYS_SETRESGID               = ga.Syscall{AMD64: 119, ARM64: 149, PPC64LE: 169, RISCV64: 149}
+	SYS_SETRESUID               = ga.Syscall{AMD64: 117, ARM64: 147, PPC64LE: 164, RISCV64: 147}
+	SYS_SETREUID                = ga.Syscall{AMD64: 113, ARM64: 145, PPC64LE: 70, RISCV64: 145}
+	SYS_SETRLIMIT               = ga.Syscall{AMD64: 160, ARM64: 164, PPC64LE: 75, RISCV64: 164}
+	SYS_SETSID                  = ga.Syscall{AMD64: 112, ARM64: 157, PPC64LE: 66, RISCV64: 157}
+	SYS_SETSOCKOPT              = ga.Syscall{AMD64: 54, ARM64: 208, PPC64LE: 339, RISCV64: 208}
+	SYS_SETTIMEOFDAY            = ga.Syscall{AMD64: 164, ARM64: 170, PPC64LE: 79, RISCV64: 170}
+	SYS_SETUID                  = ga.Syscall{AMD64: 105, ARM64: 146, PPC64LE: 23, RISCV64: 146}
+	SYS_SETXATTR                = ga.Syscall{AMD64: 188, ARM64: 5, PPC64LE: 209, RISCV64: 5}
+	SYS_SETXATTRAT              = ga.Syscall{AMD64: 463, ARM64: 463, PPC64LE: 463, RISCV64: 463}
+	SYS_SET_MEMPOLICY           = ga.Syscall{AMD64: 238, ARM64: 237, PPC64LE: 261, RISCV64: 237}
+	SYS_SET_MEMPOLICY_HOME_NODE = ga.Syscall{AMD64: 450, ARM64: 450, PPC64LE: 450, RISCV64: 450}
+	SYS_SET_ROBUST_LIST         = ga.Syscall{AMD64: 273, ARM64: 99, PPC64LE: 300, RISCV64: 99}
+	SYS_SET_THREAD_AREA         = ga.Syscall{AMD64: 205, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_SET_TID_ADDRESS         = ga.Syscall{AMD64: 218, ARM64: 96, PPC64LE: 232, RISCV64: 96}
+	SYS_SGETMASK                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 68, RISCV64: ga.NoSyscall}
+	SYS_SHMAT                   = ga.Syscall{AMD64: 30, ARM64: 196, PPC64LE: 397, RISCV64: 196}
+	SYS_SHMCTL                  = ga.Syscall{AMD64: 31, ARM64: 195, PPC64LE: 396, RISCV64: 195}
+	SYS_SHMDT                   = ga.Syscall{AMD64: 67, ARM64: 197, PPC64LE: 398, RISCV64: 197}
+	SYS_SHMGET                  = ga.Syscall{AMD64: 29, ARM64: 194, PPC64LE: 395, RISCV64: 194}
+	SYS_SHUTDOWN                = ga.Syscall{AMD64: 48, ARM64: 210, PPC64LE: 338, RISCV64: 210}
+	SYS_SIGACTION               = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 67, RISCV64: ga.NoSyscall}
+	SYS_SIGALTSTACK             = ga.Syscall{AMD64: 131, ARM64: 132, PPC64LE: 185, RISCV64: 132}
+	SYS_SIGNAL                  = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 48, RISCV64: ga.NoSyscall}
+	SYS_SIGNALFD                = ga.Syscall{AMD64: 282, ARM64: ga.NoSyscall, PPC64LE: 305, RISCV64: ga.NoSyscall}
+	SYS_SIGNALFD4               = ga.Syscall{AMD64: 289, ARM64: 74, PPC64LE: 313, RISCV64: 74}
+	SYS_SIGPENDING              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 73, RISCV64: ga.NoSyscall}
+	SYS_SIGPROCMASK             = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 126, RISCV64: ga.NoSyscall}
+	SYS_SIGRETURN               = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 119, RISCV64: ga.NoSyscall}
+	SYS_SIGSUSPEND              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 72, RISCV64: ga.NoSyscall}
+	SYS_SOCKET                  = ga.Syscall{AMD64: 41, ARM64: 198, PPC64LE: 326, RISCV64: 198}
+	SYS_SOCKETCALL              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 102, RISCV64: ga.NoSyscall}
+	SYS_SOCKETPAIR              = ga.Syscall{AMD64: 53, ARM64: 199, PPC64LE: 333, RISCV64: 199}
+	SYS_SPLICE                  = ga.Syscall{AMD64: 275, ARM64: 76, PPC64LE: 283, RISCV64: 76}
+	SYS_SPU_CREATE              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 279, RISCV64: ga.NoSyscall}
+	SYS_SPU_RUN                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 278, RISCV64: ga.NoSyscall}
+	SYS_SSETMASK                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 69, RISCV64: ga.NoSyscall}
+	SYS_STAT                    = ga.Syscall{AMD64: 4, ARM64: ga.NoSyscall, PPC64LE: 106, RISCV64: ga.NoSyscall}
+	SYS_STATFS                  = ga.Syscall{AMD64: 137, ARM64: 43, PPC64LE: 99, RISCV64: 43}
+	SYS_STATFS64                = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 252, RISCV64: ga.NoSyscall}
+	SYS_STATMOUNT               = ga.Syscall{AMD64: 457, ARM64: 457, PPC64LE: 457, RISCV64: 457}
+	SYS_STATX                   = ga.Syscall{AMD64: 332, ARM64: 291, PPC64LE: 383, RISCV64: 291}
+	SYS_STIME                   = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 25, RISCV64: ga.NoSyscall}
+	SYS_STTY                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 31, RISCV64: ga.NoSyscall}
+	SYS_SUBPAGE_PROT            = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 310, RISCV64: ga.NoSyscall}
+	SYS_SWAPCONTEXT             = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 249, RISCV64: ga.NoSyscall}
+	SYS_SWAPOFF                 = ga.Syscall{AMD64: 168, ARM64: 225, PPC64LE: 115, RISCV64: 225}
+	SYS_SWAPON                  = ga.Syscall{AMD64: 167, ARM64: 224, PPC64LE: 87, RISCV64: 224}
+	SYS_SWITCH_ENDIAN           = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 363, RISCV64: ga.NoSyscall}
+	SYS_SYMLINK                 = ga.Syscall{AMD64: 88, ARM64: ga.NoSyscall, PPC64LE: 83, RISCV64: ga.NoSyscall}
+	SYS_SYMLINKAT               = ga.Syscall{AMD64: 266, ARM64: 36, PPC64LE: 295, RISCV64: 36}
+	SYS_SYNC                    = ga.Syscall{AMD64: 162, ARM64: 81, PPC64LE: 36, RISCV64: 81}
+	SYS_SYNCFS                  = ga.Syscall{AMD64: 306, ARM64: 267, PPC64LE: 348, RISCV64: 267}
+	SYS_SYNC_FILE_RANGE         = ga.Syscall{AMD64: 277, ARM64: 84, PPC64LE: ga.NoSyscall, RISCV64: 84}
+	SYS_SYNC_FILE_RANGE2        = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 308, RISCV64: ga.NoSyscall}
+	SYS_SYSFS                   = ga.Syscall{AMD64: 139, ARM64: ga.NoSyscall, PPC64LE: 135, RISCV64: ga.NoSyscall}
+	SYS_SYSINFO                 = ga.Syscall{AMD64: 99, ARM64: 179, PPC64LE: 116, RISCV64: 179}
+	SYS_SYSLOG                  = ga.Syscall{AMD64: 103, ARM64: 116, PPC64LE: 103, RISCV64: 116}
+	SYS_SYS_DEBUG_SETCONTEXT    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 256, RISCV64: ga.NoSyscall}
+	SYS_TEE                     = ga.Syscall{AMD64: 276, ARM64: 77, PPC64LE: 284, RISCV64: 77}
+	SYS_TGKILL                  = ga.Syscall{AMD64: 234, ARM64: 131, PPC64LE: 250, RISCV64: 131}
+	SYS_TIME                    = ga.Syscall{AMD64: 201, ARM64: ga.NoSyscall, PPC64LE: 13, RISCV64: ga.NoSyscall}
+	SYS_TIMERFD_CREATE          = ga.Syscall{AMD64: 283, ARM64: 85, PPC64LE: 306, RISCV64: 85}
+	SYS_TIMERFD_GETTIME         = ga.Syscall{AMD64: 287, ARM64: 87, PPC64LE: 312, RISCV64: 87}
+	SYS_TIMERFD_SETTIME         = ga.Syscall{AMD64: 286, ARM64: 86, PPC64LE: 311, RISCV64: 86}
+	SYS_TIMER_CREATE            = ga.Syscall{AMD64: 222, ARM64: 107, PPC64LE: 240, RISCV64: 107}
+	SYS_TIMER_DELETE            = ga.Syscall{AMD64: 226, ARM64: 111, PPC64LE: 244, RISCV64: 111}
+	SYS_TIMER_GETOVERRUN        = ga.Syscall{AMD64: 225, ARM64: 109, PPC64LE: 243, RISCV64: 109}
+	SYS_TIMER_GETTIME           = ga.Syscall{AMD64: 224, ARM64: 108, PPC64LE: 242, RISCV64: 108}
+	SYS_TIMER_SETTIME           = ga.Syscall{AMD64: 223, ARM64: 110, PPC64LE: 241, RISCV64: 110}
+	SYS_TIMES                   = ga.Syscall{AMD64: 100, ARM64: 153, PPC64LE: 43, RISCV64: 153}
+	SYS_TKILL                   = ga.Syscall{AMD64: 200, ARM64: 130, PPC64LE: 208, RISCV64: 130}
+	SYS_TRUNCATE                = ga.Syscall{AMD64: 76, ARM64: 45, PPC64LE: 92, RISCV64: 45}
+	SYS_TUXCALL                 = ga.Syscall{AMD64: 184, ARM64: ga.NoSyscall, PPC64LE: 225, RISCV64: ga.NoSyscall}
+	SYS_UGETRLIMIT              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 190, RISCV64: ga.NoSyscall}
+	SYS_ULIMIT                  = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 58, RISCV64: ga.NoSyscall}
+	SYS_UMASK                   = ga.Syscall{AMD64: 95, ARM64: 166, PPC64LE: 60, RISCV64: 166}
+	SYS_UMOUNT                  = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 22, RISCV64: ga.NoSyscall}
+	SYS_UMOUNT2                 = ga.Syscall{AMD64: 166, ARM64: 39, PPC64LE: 52, RISCV64: 39}
+	SYS_UNAME                   = ga.Syscall{AMD64: 63, ARM64: 160, PPC64LE: 122, RISCV64: 160}
+	SYS_UNLINK                  = ga.Syscall{AMD64: 87, ARM64: ga.NoSyscall, PPC64LE: 10, RISCV64: ga.NoSyscall}
+	SYS_UNLINKAT                = ga.Syscall{AMD64: 263, ARM64: 35, PPC64LE: 292, RISCV64: 35}
+	SYS_UNSHARE                 = ga.Syscall{AMD64: 272, ARM64: 97, PPC64LE: 282, RISCV64: 97}
+	SYS_UPROBE                  = ga.Syscall{AMD64: 336, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_URETPROBE               = ga.Syscall{AMD64: 335, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_USELIB                  = ga.Syscall{AMD64: 134, ARM64: ga.NoSyscall, PPC64LE: 86, RISCV64: ga.NoSyscall}
+	SYS_USERFAULTFD             = ga.Syscall{AMD64: 323, ARM64: 282, PPC64LE: 364, RISCV64: 282}
+	SYS_USTAT                   = ga.Syscall{AMD64: 136, ARM64: ga.NoSyscall, PPC64LE: 62, RISCV64: ga.NoSyscall}
+	SYS_UTIME                   = ga.Syscall{AMD64: 132, ARM64: ga.NoSyscall, PPC64LE: 30, RISCV64: ga.NoSyscall}
+	SYS_UTIMENSAT               = ga.Syscall{AMD64: 280, ARM64: 88, PPC64LE: 304, RISCV64: 88}
+	SYS_UTIMES                  = ga.Syscall{AMD64: 235, ARM64: ga.NoSyscall, PPC64LE: 251, RISCV64: ga.NoSyscall}
+	SYS_VFORK                   = ga.Syscall{AMD64: 58, ARM64: ga.NoSyscall, PPC64LE: 189, RISCV64: ga.NoSyscall}
+	SYS_VHANGUP                 = ga.Syscall{AMD64: 153, ARM64: 58, PPC64LE: 111, RISCV64: 58}
+	SYS_VM86                    = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 113, RISCV64: ga.NoSyscall}
+	SYS_VMSPLICE                = ga.Syscall{AMD64: 278, ARM64: 75, PPC64LE: 285, RISCV64: 75}
+	SYS_VSERVER                 = ga.Syscall{AMD64: 236, ARM64: ga.NoSyscall, PPC64LE: ga.NoSyscall, RISCV64: ga.NoSyscall}
+	SYS_WAIT4                   = ga.Syscall{AMD64: 61, ARM64: 260, PPC64LE: 114, RISCV64: 260}
+	SYS_WAITID                  = ga.Syscall{AMD64: 247, ARM64: 95, PPC64LE: 272, RISCV64: 95}
+	SYS_WAITPID                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 7, RISCV64: ga.NoSyscall}
+	SYS_WRITE                   = ga.Syscall{AMD64: 1, ARM64: 64, PPC64LE: 4, RISCV64: 64}
+	SYS_WRITEV                  = ga.Syscall{AMD64: 20, ARM64: 66, PPC64LE: 146, RISCV64: 66}
+	SYS__LLSEEK                 = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 140, RISCV64: ga.NoSyscall}
+	SYS__NEWSELECT              = ga.Syscall{AMD64: ga.NoSyscall, ARM64: ga.NoSyscall, PPC64LE: 142, RISCV64: ga.NoSyscall}
+	SYS__SYSCTL                 = ga.Syscall{AMD64: 156, ARM64: ga.NoSyscall, PPC64LE: 149, RISCV64: ga.NoSyscall}
 )
+
+// Syscalls by name.
+var Syscalls = map[string]ga.Syscall{
+	"SYS_ACCEPT":                  SYS_ACCEPT,
+	"SYS_ACCEPT4":                 SYS_ACCEPT4,
+	"SYS_ACCESS":                  SYS_ACCESS,
+	"SYS_ACCT":                    SYS_ACCT,
+	"SYS_ADD_KEY":                 SYS_ADD_KEY,
+	"SYS_ADJTIMEX":                SYS_ADJTIMEX,
+	"SYS_AFS_SYSCALL":             SYS_AFS_SYSCALL,
+	"SYS_ALARM":                   SYS_ALARM,
+	"SYS_ARCH_PRCTL":              SYS_ARCH_PRCTL,
+	"SYS_ARCH_SPECIFIC_SYSCALL":   SYS_ARCH_SPECIFIC_SYSCALL,
+	"SYS_BDFLUSH":                 SYS_BDFLUSH,
+	"SYS_BIND":                    SYS_BIND,
+	"SYS_BPF":                     SYS_BPF,
+	"SYS_BREAK":                   SYS_BREAK,
+	"SYS_BRK":                     SYS_BRK,
+	"SYS_CACHESTAT":               SYS_CACHESTAT,
+	"SYS_CAPGET":                  SYS_CAPGET,
+	"SYS_CAPSET":                  SYS_CAPSET,
+	"SYS_CHDIR":                   SYS_CHDIR,
+	"SYS_CHMOD":                   SYS_CHMOD,
+	"SYS_CHOWN":                   SYS_CHOWN,
+	"SYS_CHROOT":                  SYS_CHROOT,
+	"SYS_CLOCK_ADJTIME":           SYS_CLOCK_ADJTIME,
+	"SYS_CLOCK_GETRES":            SYS_CLOCK_GETRES,
+	"SYS_CLOCK_GETTIME":           SYS_CLOCK_GETTIME,
+	"SYS_CLOCK_NANOSLEEP":         SYS_CLOCK_NANOSLEEP,
+	"SYS_CLOCK_SETTIME":           SYS_CLOCK_SETTIME,
+	"SYS_CLONE":                   SYS_CLONE,
+	"SYS_CLONE3":                  SYS_CLONE3,
+	"SYS_CLOSE":                   SYS_CLOSE,
+	"SYS_CLOSE_RANGE":             SYS_CLOSE_RANGE,
+	"SYS_CONNECT":                 SYS_CONNECT,
+	"SYS_COPY_FILE_RANGE":         SYS_COPY_FILE_RANGE,
+	"SYS_CREAT":                   SYS_CREAT,
+	"SYS_CREATE_MODULE":           SYS_CREATE_MODULE,
+	"SYS_DELETE_MODULE":           SYS_DELETE_MODULE,
+	"SYS_DUP":                     SYS_DUP,
+	"SYS_DUP2":                    SYS_DUP2,
+	"SYS_DUP3":                    SYS_DUP3,
+	"SYS_EPOLL_CREATE":            SYS_EPOLL_CREATE,
+	"SYS_EPOLL_CREATE1":           SYS_EPOLL_CREATE1,
+	"SYS_EPOLL_CTL":               SYS_EPOLL_CTL,
+	"SYS_EPOLL_CTL_OLD":           SYS_EPOLL_CTL_OLD,
+	"SYS_EPOLL_PWAIT":             SYS_EPOLL_PWAIT,
+	"SYS_EPOLL_PWAIT2":            SYS_EPOLL_PWAIT2,
+	"SYS_EPOLL_WAIT":              SYS_EPOLL_WAIT,
+	"SYS_EPOLL_WAIT_OLD":          SYS_EPOLL_WAIT_OLD,
+	"SYS_EVENTFD":                 SYS_EVENTFD,
+	"SYS_EVENTFD2":                SYS_EVENTFD2,
+	"SYS_EXECVE":                  SYS_EXECVE,
+	"SYS_EXECVEAT":                SYS_EXECVEAT,
+	"SYS_EXIT":                    SYS_EXIT,
+	"SYS_EXIT_GROUP":              SYS_EXIT_GROUP,
+	"SYS_FACCESSAT":               SYS_FACCESSAT,
+	"SYS_FACCESSAT2":              SYS_FACCESSAT2,
+	"SYS_FADVISE64":               SYS_FADVISE64,
+	"SYS_FALLOCATE":               SYS_FALLOCATE,
+	"SYS_FANOTIFY_INIT":           SYS_FANOTIFY_INIT,
+	"SYS_FANOTIFY_MARK":           SYS_FANOTIFY_MARK,
+	"SYS_FCHDIR":                  SYS_FCHDIR,
+	"SYS_FCHMOD":                  SYS_FCHMOD,
+	"SYS_FCHMODAT":                SYS_FCHMODAT,
+	"SYS_FCHMODAT2":               SYS_FCHMODAT2,
+	"SYS_FCHOWN":                  SYS_FCHOWN,
+	"SYS_FCHOWNAT":                SYS_FCHOWNAT,
+	"SYS_FCNTL":                   SYS_FCNTL,
+	"SYS_FDATASYNC":               SYS_FDATASYNC,
+	"SYS_FGETXATTR":               SYS_FGETXATTR,
+	"SYS_FILE_GETATTR":            SYS_FILE_GETATTR,
+	"SYS_FILE_SETATTR":            SYS_FILE_SETATTR,
+	"SYS_FINIT_MODULE":            SYS_FINIT_MODULE,
+	"SYS_FLISTXATTR":              SYS_FLISTXATTR,
+	"SYS_FLOCK":                   SYS_FLOCK,
+	"SYS_FORK":                    SYS_FORK,
+	"SYS_FREMOVEXATTR":            SYS_FREMOVEXATTR,
+	"SYS_FSCONFIG":                SYS_FSCONFIG,
+	"SYS_FSETXATTR":               SYS_FSETXATTR,
+	"SYS_FSMOUNT":                 SYS_FSMOUNT,
+	"SYS_FSOPEN":                  SYS_FSOPEN,
+	"SYS_FSPICK":                  SYS_FSPICK,
+	"SYS_FSTAT":                   SYS_FSTAT,
+	"SYS_FSTATFS":                 SYS_FSTATFS,
+	"SYS_FSTATFS64":               SYS_FSTATFS64,
+	"SYS_FSYNC":                   SYS_FSYNC,
+	"SYS_FTIME":                   SYS_FTIME,
+	"SYS_FTRUNCATE":               SYS_FTRUNCATE,
+	"SYS_FUTEX":                   SYS_FUTEX,
+	"SYS_FUTEX_REQUEUE":           SYS_FUTEX_REQUEUE,
+	"SYS_FUTEX_WAIT":              SYS_FUTEX_WAIT,
+	"SYS_FUTEX_WAITV":             SYS_FUTEX_WAITV,
+	"SYS_FUTEX_WAKE":              SYS_FUTEX_WAKE,
+	"SYS_FUTIMESAT":               SYS_FUTIMESAT,
+	"SYS_GETCPU":                  SYS_GETCPU,
+	"SYS_GETCWD":                  SYS_GETCWD,
+	"SYS_GETDENTS":                SYS_GETDENTS,
+	"SYS_GETDENTS64":              SYS_GETDENTS64,
+	"SYS_GETEGID":                 SYS_GETEGID,
+	"SYS_GETEUID":                 SYS_GETEUID,
+	"SYS_GETGID":                  SYS_GETGID,
+	"SYS_GETGROUPS":               SYS_GETGROUPS,
+	"SYS_GETITIMER":               SYS_GETITIMER,
+	"SYS_GETPEERNAME":             SYS_GETPEERNAME,
+	"SYS_GETPGID":                 SYS_GETPGID,
+	"SYS_GETPGRP":                 SYS_GETPGRP,
+	"SYS_GETPID":                  SYS_GETPID,
+	"SYS_GETPMSG":                 SYS_GETPMSG,
+	"SYS_GETPPID":                 SYS_GETPPID,
+	"SYS_GETPRIORITY":             SYS_GETPRIORITY,
+	"SYS_GETRANDOM":               SYS_GETRANDOM,
+	"SYS_GETRESGID":               SYS_GETRESGID,
+	"SYS_GETRESUID":               SYS_GETRESUID,
+	"SYS_GETRLIMIT":               SYS_GETRLIMIT,
+	"SYS_GETRUSAGE":               SYS_GETRUSAGE,
+	"SYS_GETSID":                  SYS_GETSID,
+	"SYS_GETSOCKNAME":             SYS_GETSOCKNAME,
+	"SYS_GETSOCKOPT":              SYS_GETSOCKOPT,
+	"SYS_GETTID":                  SYS_GETTID,
+	"SYS_GETTIMEOFDAY":            SYS_GETTIMEOFDAY,
+	"SYS_GETUID":                  SYS_GETUID,
+	"SYS_GETXATTR":                SYS_GETXATTR,
+	"SYS_GETXATTRAT":              SYS_GETXATTRAT,
+	"SYS_GET_KERNEL_SYMS":         SYS_GET_KERNEL_SYMS,
+	"SYS_GET_MEMPOLICY":           SYS_GET_MEMPOLICY,
+	"SYS_GET_ROBUST_LIST":         SYS_GET_ROBUST_LIST,
+	"SYS_GET_THREAD_AREA":         SYS_GET_THREAD_AREA,
+	"SYS_GTTY":                    SYS_GTTY,
+	"SYS_IDLE":                    SYS_IDLE,
+	"SYS_INIT_MODULE":             SYS_INIT_MODULE,
+	"SYS_INOTIFY_ADD_WATCH":       SYS_INOTIFY_ADD_WATCH,
+	"SYS_INOTIFY_INIT":            SYS_INOTIFY_INIT,
+	"SYS_INOTIFY_INIT1":           SYS_INOTIFY_INIT1,
+	"SYS_INOTIFY_RM_WATCH":        SYS_INOTIFY_RM_WATCH,
+	"SYS_IOCTL":                   SYS_IOCTL,
+	"SYS_IOPERM":                  SYS_IOPERM,
+	"SYS_IOPL":                    SYS_IOPL,
+	"SYS_IOPRIO_GET":              SYS_IOPRIO_GET,
+	"SYS_IOPRIO_SET":              SYS_IOPRIO_SET,
+	"SYS_IO_CANCEL":               SYS_IO_CANCEL,
+	"SYS_IO_DESTROY":              SYS_IO_DESTROY,
+	"SYS_IO_GETEVENTS":            SYS_IO_GETEVENTS,
+	"SYS_IO_PGETEVENTS":           SYS_IO_PGETEVENTS,
+	"SYS_IO_SETUP":                SYS_IO_SETUP,
+	"SYS_IO_SUBMIT":               SYS_IO_SUBMIT,
+	"SYS_IO_URING_ENTER":          SYS_IO_URING_ENTER,
+	"SYS_IO_URING_REGISTER":       SYS_IO_URING_REGISTER,
+	"SYS_IO_URING_SETUP":          SYS_IO_URING_SETUP,
+	"SYS_IPC":                     SYS_IPC,
+	"SYS_KCMP":                    SYS_KCMP,
+	"SYS_KEXEC_FILE_LOAD":         SYS_KEXEC_FILE_LOAD,
+	"SYS_KEXEC_LOAD":              SYS_KEXEC_LOAD,
+	"SYS_KEYCTL":                  SYS_KEYCTL,
+	"SYS_KILL":                    SYS_KILL,
+	"SYS_LANDLOCK_ADD_RULE":       SYS_LANDLOCK_ADD_RULE,
+	"SYS_LANDLOCK_CREATE_RULESET": SYS_LANDLOCK_CREATE_RULESET,
+	"SYS_LANDLOCK_RESTRICT_SELF":  SYS_LANDLOCK_RESTRICT_SELF,
+	"SYS_LCHOWN":                  SYS_LCHOWN,
+	"SYS_LGETXATTR":               SYS_LGETXATTR,
+	"SYS_LINK":                    SYS_LINK,
+	"SYS_LINKAT":                  SYS_LINKAT,
+	"SYS_LISTEN":                  SYS_LISTEN,
+	"SYS_LISTMOUNT":               SYS_LISTMOUNT,
+	"SYS_LISTNS":                  SYS_LISTNS,
+	"SYS_LISTXATTR":               SYS_LISTXATTR,
+	"SYS_LISTXATTRAT":             SYS_LISTXATTRAT,
+	"SYS_LLISTXATTR":              SYS_LLISTXATTR,
+	"SYS_LOCK":                    SYS_LOCK,
+	"SYS_LOOKUP_DCOOKIE":          SYS_LOOKUP_DCOOKIE,
+	"SYS_LREMOVEXATTR":            SYS_LREMOVEXATTR,
+	"SYS_LSEEK":                   SYS_LSEEK,
+	"SYS_LSETXATTR":               SYS_LSETXATTR,
+	"SYS_LSM_GET_SELF_ATTR":       SYS_LSM_GET_SELF_ATTR,
+	"SYS_LSM_LIST_MODULES":        SYS_LSM_LIST_MODULES,
+	"SYS_LSM_SET_SELF_ATTR":       SYS_LSM_SET_SELF_ATTR,
+	"SYS_LSTAT":                   SYS_LSTAT,
+	"SYS_MADVISE":                 SYS_MADVISE,
+	"SYS_MAP_SHADOW_STACK":        SYS_MAP_SHADOW_STACK,
+	"SYS_MBIND":                   SYS_MBIND,
+	"SYS_MEMBARRIER":              SYS_MEMBARRIER,
+	"SYS_MEMFD_CREATE":            SYS_MEMFD_CREATE,
+	"SYS_MEMFD_SECRET":            SYS_MEMFD_SECRET,
+	"SYS_MIGRATE_PAGES":           SYS_MIGRATE_PAGES,
+	"SYS_MINCORE":                 SYS_MINCORE,
+	"SYS_MKDIR":                   SYS_MKDIR,
+	"SYS_MKDIRAT":                 SYS_MKDIRAT,
+	"SYS_MKNOD":                   SYS_MKNOD,
+	"SYS_MKNODAT":                 SYS_MKNODAT,
+	"SYS_MLOCK":                   SYS_MLOCK,
+	"SYS_MLOCK2":                  SYS_MLOCK2,
+	"SYS_MLOCKALL":                SYS_MLOCKALL,
+	"SYS_MMAP":                    SYS_MMAP,
+	"SYS_MODIFY_LDT":              SYS_MODIFY_LDT,
+	"SYS_MOUNT":                   SYS_MOUNT,
+	"SYS_MOUNT_SETATTR":           SYS_MOUNT_SETATTR,
+	"SYS_MOVE_MOUNT":              SYS_MOVE_MOUNT,
+	"SYS_MOVE_PAGES":              SYS_MOVE_PAGES,
+	"SYS_MPROTECT":                SYS_MPROTECT,
+	"SYS_MPX":                     SYS_MPX,
+	"SYS_MQ_GETSETATTR":           SYS_MQ_GETSETATTR,
+	"SYS_MQ_NOTIFY":               SYS_MQ_NOTIFY,
+	"SYS_MQ_OPEN":                 SYS_MQ_OPEN,
+	"SYS_MQ_TIMEDRECEIVE":         SYS_MQ_TIMEDRECEIVE,
+	"SYS_MQ_TIMEDSEND":            SYS_MQ_TIMEDSEND,
+	"SYS_MQ_UNLINK":               SYS_MQ_UNLINK,
+	"SYS_MREMAP":                  SYS_MREMAP,
+	"SYS_MSEAL":                   SYS_MSEAL,
+	"SYS_MSGCTL":                  SYS_MSGCTL,
+	"SYS_MSGGET":                  SYS_MSGGET,
+	"SYS_MSGRCV":                  SYS_MSGRCV,
+	"SYS_MSGSND":                  SYS_MSGSND,
+	"SYS_MSYNC":                   SYS_MSYNC,
+	"SYS_MULTIPLEXER":             SYS_MULTIPLEXER,
+	"SYS_MUNLOCK":                 SYS_MUNLOCK,
+	"SYS_MUNLOCKALL":              SYS_MUNLOCKALL,
+	"SYS_MUNMAP":                  SYS_MUNMAP,
+	"SYS_NAME_TO_HANDLE_AT":       SYS_NAME_TO_HANDLE_AT,
+	"SYS_NANOSLEEP":               SYS_NANOSLEEP,
+	"SYS_NEWFSTATAT":              SYS_NEWFSTATAT,
+	"SYS_NFSSERVCTL":              SYS_NFSSERVCTL,
+	"SYS_NICE":                    SYS_NICE,
+	"SYS_OLDFSTAT":                SYS_OLDFSTAT,
+	"SYS_OLDLSTAT":                SYS_OLDLSTAT,
+	"SYS_OLDOLDUNAME":             SYS_OLDOLDUNAME,
+	"SYS_OLDSTAT":                 SYS_OLDSTAT,
+	"SYS_OLDUNAME":                SYS_OLDUNAME,
+	"SYS_OPEN":                    SYS_OPEN,
+	"SYS_OPENAT":                  SYS_OPENAT,
+	"SYS_OPENAT2":                 SYS_OPENAT2,
+	"SYS_OPEN_BY_HANDLE_AT":       SYS_OPEN_BY_HANDLE_AT,
+	"SYS_OPEN_TREE":               SYS_OPEN_TREE,
+	"SYS_OPEN_TREE_ATTR":          SYS_OPEN_TREE_ATTR,
+	"SYS_PAUSE":                   SYS_PAUSE,
+	"SYS_PCICONFIG_IOBASE":        SYS_PCICONFIG_IOBASE,
+	"SYS_PCICONFIG_READ":          SYS_PCICONFIG_READ,
+	"SYS_PCICONFIG_WRITE":         SYS_PCICONFIG_WRITE,
+	"SYS_PERF_EVENT_OPEN":         SYS_PERF_EVENT_OPEN,
+	"SYS_PERSONALITY":             SYS_PERSONALITY,
+	"SYS_PIDFD_GETFD":             SYS_PIDFD_GETFD,
+	"SYS_PIDFD_OPEN":              SYS_PIDFD_OPEN,
+	"SYS_PIDFD_SEND_SIGNAL":       SYS_PIDFD_SEND_SIGNAL,
+	"SYS_PIPE":                    SYS_PIPE,
+	"SYS_PIPE2":                   SYS_PIPE2,
+	"SYS_PIVOT_ROOT":              SYS_PIVOT_ROOT,
+	"SYS_PKEY_ALLOC":              SYS_PKEY_ALLOC,
+	"SYS_PKEY_FREE":               SYS_PKEY_FREE,
+	"SYS_PKEY_MPROTECT":           SYS_PKEY_MPROTECT,
+	"SYS_POLL":                    SYS_POLL,
+	"SYS_PPOLL":                   SYS_PPOLL,
+	"SYS_PRCTL":                   SYS_PRCTL,
+	"SYS_PREAD64":                 SYS_PREAD64,
+	"SYS_PREADV":                  SYS_PREADV,
+	"SYS_PREADV2":                 SYS_PREADV2,
+	"SYS_PRLIMIT64":               SYS_PRLIMIT64,
+	"SYS_PROCESS_MADVISE":         SYS_PROCESS_MADVISE,
+	"SYS_PROCESS_MRELEASE":        SYS_PROCESS_MRELEASE,
+	"SYS_PROCESS_VM_READV":        SYS_PROCESS_VM_READV,
+	"SYS_PROCESS_VM_WRITEV":       SYS_PROCESS_VM_WRITEV,
+	"SYS_PROF":                    SYS_PROF,
+	"SYS_PROFIL":                  SYS_PROFIL,
+	"SYS_PSELECT6":                SYS_PSELECT6,
+	"SYS_PTRACE":                  SYS_PTRACE,
+	"SYS_PUTPMSG":                 SYS_PUTPMSG,
+	"SYS_PWRITE64":                SYS_PWRITE64,
+	"SYS_PWRITEV":                 SYS_PWRITEV,
+	"SYS_PWRITEV2":                SYS_PWRITEV2,
+	"SYS_QUERY_MODULE":            SYS_QUERY_MODULE,
+	"SYS_QUOTACTL":                SYS_QUOTACTL,
+	"SYS_QUOTACTL_FD":             SYS_QUOTACTL_FD,
+	"SYS_READ":                    SYS_READ,
+	"SYS_READAHEAD":               SYS_READAHEAD,
+	"SYS_READDIR":                 SYS_READDIR,
+	"SYS_READLINK":                SYS_READLINK,
+	"SYS_READLINKAT":              SYS_READLINKAT,
+	"SYS_READV":                   SYS_READV,
+	"SYS_REBOOT":                  SYS_REBOOT,
+	"SYS_RECV":                    SYS_RECV,
+	"SYS_RECVFROM":                SYS_RECVFROM,
+	"SYS_RECVMMSG":                SYS_RECVMMSG,
+	"SYS_RECVMSG":                 SYS_RECVMSG,
+	"SYS_REMAP_FILE_PAGES":        SYS_REMAP_FILE_PAGES,
+	"SYS_REMOVEXATTR":             SYS_REMOVEXATTR,
+	"SYS_REMOVEXATTRAT":           SYS_REMOVEXATTRAT,
+	"SYS_RENAME":                  SYS_RENAME,
+	"SYS_RENAMEAT":                SYS_RENAMEAT,
+	"SYS_RENAMEAT2":               SYS_RENAMEAT2,
+	"SYS_REQUEST_KEY":             SYS_REQUEST_KEY,
+	"SYS_RESTART_SYSCALL":         SYS_RESTART_SYSCALL,
+	"SYS_RISCV_FLUSH_ICACHE":      SYS_RISCV_FLUSH_ICACHE,
+	"SYS_RISCV_HWPROBE":           SYS_RISCV_HWPROBE,
+	"SYS_RMDIR":                   SYS_RMDIR,
+	"SYS_RSEQ":                    SYS_RSEQ,
+	"SYS_RSEQ_SLICE_YIELD":        SYS_RSEQ_SLICE_YIELD,
+	"SYS_RTAS":                    SYS_RTAS,
+	"SYS_RT_SIGACTION":            SYS_RT_SIGACTION,
+	"SYS_RT_SIGPENDING":           SYS_RT_SIGPENDING,
+	"SYS_RT_SIGPROCMASK":          SYS_RT_SIGPROCMASK,
+	"SYS_RT_SIGQUEUEINFO":         SYS_RT_SIGQUEUEINFO,
+	"SYS_RT_SIGRETURN":            SYS_RT_SIGRETURN,
+	"SYS_RT_SIGSUSPEND":           SYS_RT_SIGSUSPEND,
+	"SYS_RT_SIGTIMEDWAIT":         SYS_RT_SIGTIMEDWAIT,
+	"SYS_RT_TGSIGQUEUEINFO":       SYS_RT_TGSIGQUEUEINFO,
+	"SYS_SCHED_GETAFFINITY":       SYS_SCHED_GETAFFINITY,
+	"SYS_SCHED_GETATTR":           SYS_SCHED_GETATTR,
+	"SYS_SCHED_GETPARAM":          SYS_SCHED_GETPARAM,
+	"SYS_SCHED_GETSCHEDULER":      SYS_SCHED_GETSCHEDULER,
+	"SYS_SCHED_GET_PRIORITY_MAX":  SYS_SCHED_GET_PRIORITY_MAX,
+	"SYS_SCHED_GET_PRIORITY_MIN":  SYS_SCHED_GET_PRIORITY_MIN,
+	"SYS_SCHED_RR_GET_INTERVAL":   SYS_SCHED_RR_GET_INTERVAL,
+	"SYS_SCHED_SETAFFINITY":       SYS_SCHED_SETAFFINITY,
+	"SYS_SCHED_SETATTR":           SYS_SCHED_SETATTR,
+	"SYS_SCHED_SETPARAM":          SYS_SCHED_SETPARAM,
+	"SYS_SCHED_SETSCHEDULER":      SYS_SCHED_SETSCHEDULER,
+	"SYS_SCHED_YIELD":             SYS_SCHED_YIELD,
+	"SYS_SECCOMP":                 SYS_SECCOMP,
+	"SYS_SECURITY":                SYS_SECURITY,
+	"SYS_SELECT":                  SYS_SELECT,
+	"SYS_SEMCTL":                  SYS_SEMCTL,
+	"SYS_SEMGET":                  SYS_SEMGET,
+	"SYS_SEMOP":                   SYS_SEMOP,
+	"SYS_SEMTIMEDOP":              SYS_SEMTIMEDOP,
+	"SYS_SEND":                    SYS_SEND,
+	"SYS_SENDFILE":                SYS_SENDFILE,
+	"SYS_SENDMMSG":                SYS_SENDMMSG,
+	"SYS_SENDMSG":                 SYS_SENDMSG,
+	"SYS_SENDTO":                  SYS_SENDTO,
+	"SYS_SETDOMAINNAME":           SYS_SETDOMAINNAME,
+	"SYS_SETFSGID":                SYS_SETFSGID,
+	"SYS_SETFSUID":                SYS_SETFSUID,
+	"SYS_SETGID":                  SYS_SETGID,
+	"SYS_SETGROUPS":               SYS_SETGROUPS,
+	"SYS_SETHOSTNAME":             SYS_SETHOSTNAME,
+	"SYS_SETITIMER":               SYS_SETITIMER,
+	"SYS_SETNS":                   SYS_SETNS,
+	"SYS_SETPGID":                 SYS_SETPGID,
+	"SYS_SETPRIORITY":             SYS_SETPRIORITY,
+	"SYS_SETREGID":                SYS_SETREGID,
+	"SYS_SETRESGID":               SYS_SETRESGID,
+	"SYS_SETRESUID":               SYS_SETRESUID,
+	"SYS_SETREUID":                SYS_SETREUID,
+	"SYS_SETRLIMIT":               SYS_SETRLIMIT,
+	"SYS_SETSID":                  SYS_SETSID,
+	"SYS_SETSOCKOPT":              SYS_SETSOCKOPT,
+	"SYS_SETTIMEOFDAY":            SYS_SETTIMEOFDAY,
+	"SYS_SETUID":                  SYS_SETUID,
+	"SYS_SETXATTR":                SYS_SETXATTR,
+	"SYS_SETXATTRAT":              SYS_SETXATTRAT,
+	"SYS_SET_MEMPOLICY":           SYS_SET_MEMPOLICY,
+	"SYS_SET_MEMPOLICY_HOME_NODE": SYS_SET_MEMPOLICY_HOME_NODE,
+	"SYS_SET_ROBUST_LIST":         SYS_SET_ROBUST_LIST,
+	"SYS_SET_THREAD_AREA":         SYS_SET_THREAD_AREA,
+	"SYS_SET_TID_ADDRESS":         SYS_SET_TID_ADDRESS,
+	"SYS_SGETMASK":                SYS_SGETMASK,
+	"SYS_SHMAT":                   SYS_SHMAT,
+	"SYS_SHMCTL":                  SYS_SHMCTL,
+	"SYS_SHMDT":                   SYS_SHMDT,
+	"SYS_SHMGET":                  SYS_SHMGET,
+	"SYS_SHUTDOWN":                SYS_SHUTDOWN,
+	"SYS_SIGACTION":               SYS_SIGACTION,
+	"SYS_SIGALTSTACK":             SYS_SIGALTSTACK,
+	"SYS_SIGNAL":                  SYS_SIGNAL,
+	"SYS_SIGNALFD":                SYS_SIGNALFD,
+	"SYS_SIGNALFD4":               SYS_SIGNALFD4,
+	"SYS_SIGPENDING":              SYS_SIGPENDING,
+	"SYS_SIGPROCMASK":             SYS_SIGPROCMASK,
+	"SYS_SIGRETURN":               SYS_SIGRETURN,
+	"SYS_SIGSUSPEND":              SYS_SIGSUSPEND,
+	"SYS_SOCKET":                  SYS_SOCKET,
+	"SYS_SOCKETCALL":              SYS_SOCKETCALL,
+	"SYS_SOCKETPAIR":              SYS_SOCKETPAIR,
+	"SYS_SPLICE":                  SYS_SPLICE,
+	"SYS_SPU_CREATE":              SYS_SPU_CREATE,
+	"SYS_SPU_RUN":                 SYS_SPU_RUN,
+	"SYS_SSETMASK":                SYS_SSETMASK,
+	"SYS_STAT":                    SYS_STAT,
+	"SYS_STATFS":                  SYS_STATFS,
+	"SYS_STATFS64":                SYS_STATFS64,
+	"SYS_STATMOUNT":               SYS_STATMOUNT,
+	"SYS_STATX":                   SYS_STATX,
+	"SYS_STIME":                   SYS_STIME,
+	"SYS_STTY":                    SYS_STTY,
+	"SYS_SUBPAGE_PROT":            SYS_SUBPAGE_PROT,
+	"SYS_SWAPCONTEXT":             SYS_SWAPCONTEXT,
+	"SYS_SWAPOFF":                 SYS_SWAPOFF,
+	"SYS_SWAPON":                  SYS_SWAPON,
+	"SYS_SWITCH_ENDIAN":           SYS_SWITCH_ENDIAN,
+	"SYS_SYMLINK":                 SYS_SYMLINK,
+	"SYS_SYMLINKAT":               SYS_SYMLINKAT,
+	"SYS_SYNC":                    SYS_SYNC,
+	"SYS_SYNCFS":                  SYS_SYNCFS,
+	"SYS_SYNC_FILE_RANGE":         SYS_SYNC_FILE_RANGE,
+	"SYS_SYNC_FILE_RANGE2":        SYS_SYNC_FILE_RANGE2,
+	"SYS_SYSFS":                   SYS_SYSFS,
+	"SYS_SYSINFO":                 SYS_SYSINFO,
+	"SYS_SYSLOG":                  SYS_SYSLOG,
+	"SYS_SYS_DEBUG_SETCONTEXT":    SYS_SYS_DEBUG_SETCONTEXT,
+	"SYS_TEE":                     SYS_TEE,
+	"SYS_TGKILL":                  SYS_TGKILL,
+	"SYS_TIME":                    SYS_TIME,
+	"SYS_TIMERFD_CREATE":          SYS_TIMERFD_CREATE,
+	"SYS_TIMERFD_GETTIME":         SYS_TIMERFD_GETTIME,
+	"SYS_TIMERFD_SETTIME":         SYS_TIMERFD_SETTIME,
+	"SYS_TIMER_CREATE":            SYS_TIMER_CREATE,
+	"SYS_TIMER_DELETE":            SYS_TIMER_DELETE,
+	"SYS_TIMER_GETOVERRUN":        SYS_TIMER_GETOVERRUN,
+	"SYS_TIMER_GETTIME":           SYS_TIMER_GETTIME,
+	"SYS_TIMER_SETTIME":           SYS_TIMER_SETTIME,
+	"SYS_TIMES":                   SYS_TIMES,
+	"SYS_TKILL":                   SYS_TKILL,
+	"SYS_TRUNCATE":                SYS_TRUNCATE,
+	"SYS_TUXCALL":                 SYS_TUXCALL,
+	"SYS_UGETRLIMIT":              SYS_UGETRLIMIT,
+	"SYS_ULIMIT":                  SYS_ULIMIT,
+	"SYS_UMASK":                   SYS_UMASK,
+	"SYS_UMOUNT":                  SYS_UMOUNT,
+	"SYS_UMOUNT2":                 SYS_UMOUNT2,
+	"SYS_UNAME":                   SYS_UNAME,
+	"SYS_UNLINK":                  SYS_UNLINK,
+	"SYS_UNLINKAT":                SYS_UNLINKAT,
+	"SYS_UNSHARE":                 SYS_UNSHARE,
+	"SYS_UPROBE":                  SYS_UPROBE,
+	"SYS_URETPROBE":               SYS_URETPROBE,
+	"SYS_USELIB":                  SYS_USELIB,
+	"SYS_USERFAULTFD":             SYS_USERFAULTFD,
+	"SYS_USTAT":                   SYS_USTAT,
+	"SYS_UTIME":                   SYS_UTIME,
+	"SYS_UTIMENSAT":               SYS_UTIMENSAT,
+	"SYS_UTIMES":                  SYS_UTIMES,
+	"SYS_VFORK":                   SYS_VFORK,
+	"SYS_VHANGUP":                 SYS_VHANGUP,
+	"SYS_VM86":                    SYS_VM86,
+	"SYS_VMSPLICE":                SYS_VMSPLICE,
+	"SYS_VSERVER":                 SYS_VSERVER,
+	"SYS_WAIT4":                   SYS_WAIT4,
+	"SYS_WAITID":                  SYS_WAITID,
+	"SYS_WAITPID":                 SYS_WAITPID,
+	"SYS_WRITE":                   SYS_WRITE,
+	"SYS_WRITEV":                  SYS_WRITEV,
+	"SYS__LLSEEK":                 SYS__LLSEEK,
+	"SYS__NEWSELECT":              SYS__NEWSELECT,
+	"SYS__SYSCTL":                 SYS__SYSCTL,
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MultiAssembly contains the same code generated for multiple architectures.
+type MultiAssembly struct {
+	Names      []string // Go-style architecture names (keys of Archs).
+	Assemblies []*Assembly
+}
+
+// NewMultiAssembly calls gen for each named architecture.
+func NewMultiAssembly(sys *System, names []string, gen func(*Assembly), opts ...Option) *MultiAssembly {
+	m := &MultiAssembly{
+		Names: names,
+	}
+
+	for _, name := range names {
+		arch, found := Archs[name]
+		if !found {
+			panic(fmt.Sprintf("unknown architecture: %s", name))
+		}
+
+		a := NewAssembly(arch, sys, opts...)
+		gen(a)
+		m.Assemblies = append(m.Assemblies, a)
+	}
+
+	return m
+}
+
+// Check each assembly for undefined and duplicate names.
+func (m *MultiAssembly) Check() error {
+	for i, a := range m.Assemblies {
+		if err := a.Check(); err != nil {
+			return fmt.Errorf("%s: %w", m.Names[i], err)
+		}
+	}
+	return nil
+}
+
+// Bytes of a universal source file which must be preprocessed by the C
+// preprocessor (typically named with the .S extension).  Architecture is
+// selected using compiler-defined macros.  The C architecture is not included,
+// as its output is not assembly source.  Names are not checked; see
+// BytesChecked.
+func (m *MultiAssembly) Bytes() []byte {
+	b := new(bytes.Buffer)
+	b.WriteString(header1)
+
+	n := 0
+	for _, a := range m.Assemblies {
+		if a.Arch.ID() == IDC {
+			continue
+		}
+
+		if n == 0 {
+			fmt.Fprintf(b, "\n#if %s\n", predefinedCondition(a.Arch))
+		} else {
+			fmt.Fprintf(b, "\n#elif %s\n", predefinedCondition(a.Arch))
+		}
+		b.Write(bytes.TrimPrefix(a.Bytes(), []byte(header1)))
+		n++
+	}
+
+	if n > 0 {
+		b.WriteString("\n#else\n")
+		b.WriteString("#error \"unsupported architecture\"\n")
+		b.WriteString("#endif\n")
+	}
+
+	return b.Bytes()
+}
+
+// BytesChecked returns the universal source file, or an error if Check fails.
+func (m *MultiAssembly) BytesChecked() ([]byte, error) {
+	if err := m.Check(); err != nil {
+		return nil, err
+	}
+	return m.Bytes(), nil
+}
+
+func (m *MultiAssembly) String() string {
+	return string(m.Bytes())
+}
+
+// WriteFile writes the universal source file.  See Bytes.  Nothing is written
+// if Check returns an error.
+func (m *MultiAssembly) WriteFile(filename string) error {
+	b, err := m.BytesChecked()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// WriteArchFiles writes a source file per architecture.  The filenames follow
+// the Go build constraint convention: name_amd64.S, name_arm64.S, etc.  Go
+// assembler syntax uses the .s extension, and the C architecture uses the .c
+// extension.  Nothing is written if Check returns an error.
+func (m *MultiAssembly) WriteArchFiles(dir, name string) error {
+	if err := m.Check(); err != nil {
+		return err
+	}
+
+	for i, a := range m.Assemblies {
+		ext := ".S"
+		switch {
+		case a.Arch.ID() == IDC:
+			ext = ".c"
+		case a.syntax == GoSyntax:
+			ext = ".s"
+		}
+
+		filename := filepath.Join(dir, name+"_"+m.Names[i]+ext)
+		if err := os.WriteFile(filename, a.Bytes(), 0666); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func predefinedCondition(arch Arch) string {
+	switch arch.ID() {
+	case IDAMD64:
+		return "defined(__x86_64__)"
+	case IDARM64:
+		return "defined(__aarch64__)"
+	case IDRISCV64:
+		return "defined(__riscv) && __riscv_xlen == 64"
+	case IDPPC64LE:
+		return "defined(__powerpc64__) && defined(__LITTLE_ENDIAN__)"
+	}
+
+	panic(fmt.Sprintf("architecture %s cannot be selected by preprocessor", arch.Machine()))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func multiGen(a *ga.Assembly) {
+	a.Function("f")
+	a.MoveImm(r0, 42)
+	a.Return()
+}
+
+func TestMultiAssembly(t *testing.T) {
+	m := ga.NewMultiAssembly(sys, []string{"amd64", "arm64", "riscv64", "ppc64le", "c"}, multiGen)
+
+	s := m.String()
+	for _, x := range []string{
+		"\n#if defined(__x86_64__)\n\n.intel_syntax noprefix\n",
+		"\n#elif defined(__aarch64__)\n",
+		"\n#elif defined(__riscv) && __riscv_xlen == 64\n",
+		"\n#elif defined(__powerpc64__) && defined(__LITTLE_ENDIAN__)\n",
+		"\n#else\n#error \"unsupported architecture\"\n#endif\n",
+	} {
+		if !strings.Contains(s, x) {
+			t.Errorf("%q not found in:\n%s", x, s)
+		}
+	}
+	if strings.Contains(s, "#include") {
+		t.Errorf("C source included:\n%s", s)
+	}
+
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "x.S")
+	if err := m.WriteFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(cc, "-c", "-o", filepath.Join(dir, "x.o"), filename).CombinedOutput(); err != nil {
+		t.Errorf("cc: %v\n%s", err, out)
+	}
+}
+
+func TestMultiAssemblyArchFiles(t *testing.T) {
+	m := ga.NewMultiAssembly(sys, []string{"amd64", "arm64", "c"}, multiGen)
+
+	dir := t.TempDir()
+	if err := m.WriteArchFiles(dir, "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	if s := strings.Join(names, " "); s != "x_amd64.S x_arm64.S x_c.c" {
+		t.Errorf("files: %s", s)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "x_arm64.S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); s != m.Assemblies[1].String() {
+		t.Errorf("x_arm64.S:\n%s", s)
+	}
+}
+
+func TestMultiAssemblyGoSyntax(t *testing.T) {
+	m := ga.NewMultiAssembly(sys, []string{"amd64", "arm64"}, multiGen, ga.WithSyntax(ga.GoSyntax))
+
+	dir := t.TempDir()
+	if err := m.WriteArchFiles(dir, "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, goarch := range m.Names {
+		if _, err := os.Stat(filepath.Join(dir, "x_"+goarch+".s")); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+// Syntax of assembly source.
+type Syntax uint8
+
+const (
+	GNUSyntax Syntax = iota // GNU assembler (default).
+	GoSyntax                // Go assembler (amd64 and arm64 only).
+	ATTSyntax               // GNU assembler with AT&T operands (amd64 only).
+)
+
+type options struct {
+	syntax        Syntax
+	farAddress    bool
+	pic           bool
+	external      map[string]bool
+	framePtr      bool
+	callFrameInfo bool
+	sourceInfo    SourceInfo
+	indexMasking  bool
+}
+
+// Option for NewAssembly.
+type Option func(*options)
+
+// WithSyntax selects the output flavor.
+func WithSyntax(s Syntax) Option {
+	return func(o *options) {
+		o.syntax = s
+	}
+}
+
+// WithFarAddress makes Address reach symbols beyond the range of a single
+// PC-relative instruction (±1MiB on arm64).
+func WithFarAddress() Option {
+	return func(o *options) {
+		o.farAddress = true
+	}
+}
+
+// WithPIC generates position-independent code.  MoveDef panics, because it
+// would need an absolute relocation.  The option cannot be combined with Go
+// syntax.
+func WithPIC() Option {
+	return func(o *options) {
+		o.pic = true
+	}
+}
+
+// WithExternal marks symbols which may be defined outside the link unit or be
+// preempted.  Their addresses are loaded from the global offset table, and
+// calls go through the procedure linkage table.  The option cannot be
+// combined with Go syntax.
+func WithExternal(names ...string) Option {
+	return func(o *options) {
+		if o.external == nil {
+			o.external = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.external[name] = true
+		}
+	}
+}
+
+// WithFramePointer generates standard frame pointer prologues and epilogues so
+// that profilers and debuggers can unwind the stack.  System.FramePtr is
+// reserved.  AMD64, ARM64 and RISC-V with GNU assembler only.
+func WithFramePointer() Option {
+	return func(o *options) {
+		o.framePtr = true
+	}
+}
+
+// WithCallFrameInfo generates DWARF call frame information directives so that
+// unwinders can walk through functions.  Function prologues and epilogues,
+// Push, Pop and stack frames are described.  Stack pointer may also be updated
+// with AddImm, SubtractImm and MoveReg, relative to itself or to the register
+// which currently defines the frame; other updates panic.  The stack state at
+// a local label is taken from the preceding jumps to it, or from the preceding
+// instruction.  GNU assembler only.
+func WithCallFrameInfo() Option {
+	return func(o *options) {
+		o.callFrameInfo = true
+	}
+}
+
+// WithSourceInfo maps instructions to the Go code which generated them.  The
+// location is that of the innermost caller outside this package.  SourceLines
+// is not supported with Go syntax.
+func WithSourceInfo(mode SourceInfo) Option {
+	return func(o *options) {
+		o.sourceInfo = mode
+	}
+}
+
+// WithIndexMasking clamps jump table indexes after bounds checks, so that a
+// mispredicted check can't make the processor speculatively read beyond the
+// table.  Tables are padded to a power of two.
+func WithIndexMasking() Option {
+	return func(o *options) {
+		o.indexMasking = true
+	}
+}
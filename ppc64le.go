@@ -0,0 +1,649 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+const headerPPC64LE = header1 + `
+.abiversion 2
+` + header2
+
+// minFramePPC64LE is the size of the ELFv2 frame header: back chain, CR save
+// word, link register save doubleword and TOC pointer save doubleword.
+const minFramePPC64LE = 32
+
+type RegPPC64LE uint8
+
+// GPR0 is used as a scratch register by the backend (and for the syscall
+// number), GPR1 is the stack pointer, GPR2 is the TOC pointer and GPR13 is the
+// thread pointer.  GPR0 cannot be used as a general register: as a base
+// register it would be interpreted as literal zero.
+const (
+	GPR0 RegPPC64LE = iota
+	GPR1
+	GPR2
+	GPR3
+	GPR4
+	GPR5
+	GPR6
+	GPR7
+	GPR8
+	GPR9
+	GPR10
+	GPR11
+	GPR12
+	GPR13
+	GPR14
+	GPR15
+	GPR16
+	GPR17
+	GPR18
+	GPR19
+	GPR20
+	GPR21
+	GPR22
+	GPR23
+	GPR24
+	GPR25
+	GPR26
+	GPR27
+	GPR28
+	GPR29
+	GPR30
+	GPR31
+)
+
+func (r RegPPC64LE) String() string {
+	return r.reg()
+}
+
+func (r RegPPC64LE) reg() string {
+	if r < 32 {
+		return fmt.Sprintf("%%r%d", r)
+	}
+	panic(r)
+}
+
+var PPC64LE = &ArchPPC64LE{
+	ClearableRegs: []RegPPC64LE{
+		// GPR0
+		// GPR1
+		// GPR2
+		GPR3,
+		GPR4,
+		GPR5,
+		GPR6,
+		GPR7,
+		GPR8,
+		GPR9,
+		GPR10,
+		GPR11,
+		GPR12,
+		// GPR13
+		GPR14,
+		GPR15,
+		GPR16,
+		GPR17,
+		GPR18,
+		GPR19,
+		GPR20,
+		GPR21,
+		GPR22,
+		GPR23,
+		GPR24,
+		GPR25,
+		GPR26,
+		GPR27,
+		GPR28,
+		GPR29,
+		GPR30,
+		GPR31,
+	},
+}
+
+type ArchPPC64LE struct {
+	ClearableRegs []RegPPC64LE
+}
+
+func (*ArchPPC64LE) ID() ArchID {
+	return IDPPC64LE
+}
+
+func (*ArchPPC64LE) Machine() string {
+	return "powerpc64le"
+}
+
+func (*ArchPPC64LE) Specify(x Specific) int {
+	return x.PPC64LE
+}
+
+func (arch *ArchPPC64LE) ClearReg(a *Assembly, r RegPPC64LE) {
+	if a.Arch != arch {
+		panic(a.Arch)
+	}
+	a.insn("li", r.reg(), "0")
+}
+
+func (*ArchPPC64LE) NewArchAssembly(sys *System, out *Output) ArchAssembly {
+	if out.Syntax() != GNUSyntax {
+		panic("unsupported syntax for ppc64le")
+	}
+
+	out.buf.WriteString(headerPPC64LE)
+	return &ppc64le{
+		System: sys,
+		buffer: out.buf,
+	}
+}
+
+type ppc64le struct {
+	*System
+	*buffer
+}
+
+func (a *ppc64le) check(r Reg) {
+	a.checkReserved(r)
+	a.checkUsage(uint8(r.PPC64LE), r.Use)
+}
+
+func (a *ppc64le) Set(r Reg) {
+	a.checkReserved(r)
+	a.buffer.regUsage[r.PPC64LE] = r.Use
+}
+
+// checkReserved panics if the register is the scratch register, which is
+// overwritten by the backend without notice.
+func (a *ppc64le) checkReserved(r Reg) {
+	if r.PPC64LE == GPR0 {
+		panic(fmt.Sprintf("register %s (%s) is reserved", r.PPC64LE, r.Use))
+	}
+}
+
+func (a *ppc64le) Label(name string, opts ...SymbolOptions) {
+	if global(name) {
+		a.declare(name, "@function", opts)
+	} else {
+		symbolOptions(name, opts)
+	}
+	a.printf("")
+	a.label(name)
+	if global(name) {
+		a.cfiStartProc(0)
+	}
+}
+
+func (a *ppc64le) FunctionEpilogue() {
+	a.cfiRememberState()
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(minFramePPC64LE))
+	a.cfiAdjust(-minFramePPC64LE)
+	a.insn("ld", a.scratch(), a.mem(a.StackPtr, 16))
+	a.insn("mtlr", a.scratch())
+	a.cfiRestore("lr", 16)
+}
+
+// Function allocates a minimal ELFv2 stack frame, saving the link register in
+// the caller's frame.
+func (a *ppc64le) Function(name string, opts ...SymbolOptions) {
+	a.declare(name, "@function", opts)
+	a.printf("")
+	a.label(name)
+	a.entry(name)
+	a.insn("mflr", a.scratch())
+	a.insn("std", a.scratch(), a.mem(a.StackPtr, 16))
+	a.cfiSave("lr", 16)
+	a.insn("stdu", a.reg(a.StackPtr), a.mem(a.StackPtr, -minFramePPC64LE))
+	a.cfiAdjust(minFramePPC64LE)
+}
+
+func (a *ppc64le) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.declare(name, "@function", opts)
+	a.printf("")
+	a.label(name)
+	a.entry(name)
+}
+
+// entry sets the TOC pointer from r12 at the global entry point.  Local calls
+// use the local entry point, which follows it.  A procedure is started for
+// call frame information.
+func (a *ppc64le) entry(name string) {
+	a.cfiStartProc(0)
+	a.insn("addis", GPR2.reg(), GPR12.reg(), ".TOC.-"+symbol(name)+"@ha")
+	a.insn("addi", GPR2.reg(), GPR2.reg(), ".TOC.-"+symbol(name)+"@l")
+	a.printf(".localentry %s, .-%s", symbol(name), symbol(name))
+}
+
+func (a *ppc64le) Return() {
+	a.FunctionEpilogue()
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *ppc64le) ReturnWithoutEpilogue() {
+	a.insn("blr")
+	a.speculationBarrier()
+	a.cfiRestoreState()
+}
+
+// Address is computed relative to the TOC pointer (medium code model).
+func (a *ppc64le) Address(dest Reg, name string) {
+	if a.external[name] {
+		a.insn("addis", a.reg(dest), GPR2.reg(), symbol(name)+"@got@ha")
+		a.insnf("ld %s, %s@got@l(%s)", a.reg(dest), symbol(name), a.reg(dest))
+	} else {
+		a.insn("addis", a.reg(dest), GPR2.reg(), symbol(name)+"@toc@ha")
+		a.insn("addi", a.reg(dest), a.reg(dest), symbol(name)+"@toc@l")
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) MoveDef(dest Reg, name string) {
+	a.checkAbsolute(name)
+	a.insn("li", a.reg(dest), symbol(name))
+	a.Set(dest)
+}
+
+func (a *ppc64le) MoveImm(dest Reg, value int) {
+	a.moveImm(a.reg(dest), int64(value))
+	a.Set(dest)
+}
+
+func (a *ppc64le) MoveImm64(dest Reg, value uint64) {
+	a.moveImm(a.reg(dest), int64(value))
+	a.Set(dest)
+}
+
+func (a *ppc64le) MoveReg(dest, src Reg) {
+	a.check(src)
+	if a.reg(dest) != a.reg(src) {
+		a.insn("mr", a.reg(dest), a.reg(src))
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) MoveRegFloat(dest Reg, src FloatReg) {
+	a.insn("mfvsrd", a.reg(dest), a.floatreg(src))
+	a.Set(dest)
+}
+
+func (a *ppc64le) AddImm(dest, src Reg, value int) {
+	a.check(src)
+	if imm16(value) {
+		a.insn("addi", a.reg(dest), a.reg(src), a.imm(value))
+	} else {
+		a.moveImm(a.scratch(), int64(value))
+		a.insn("add", a.reg(dest), a.reg(src), a.scratch())
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) AddReg(dest, src1, src2 Reg) {
+	a.check(src1)
+	a.check(src2)
+	a.insn("add", a.reg(dest), a.reg(src1), a.reg(src2))
+	a.Set(dest)
+}
+
+func (a *ppc64le) SubtractImm(dest Reg, value int) {
+	a.check(dest)
+	if value != 0 {
+		a.AddImm(dest, dest, -value)
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) SubtractReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("sub", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *ppc64le) MultiplyImm(dest, src Reg, value int, temp Reg) {
+	a.check(src)
+	if imm16(value) {
+		a.insn("mulli", a.reg(dest), a.reg(src), a.imm(value))
+	} else {
+		a.MoveImm(temp, value)
+		a.insn("mulld", a.reg(dest), a.reg(src), a.reg(temp))
+	}
+	a.Set(dest)
+	a.Set(temp.As(""))
+}
+
+func (a *ppc64le) AndImm(dest Reg, value int) {
+	a.check(dest)
+	if value >= 0 && value <= 0xffff {
+		a.insn("andi.", a.reg(dest), a.reg(dest), a.imm(value))
+	} else {
+		a.moveImm(a.scratch(), int64(value))
+		a.insn("and", a.reg(dest), a.reg(dest), a.scratch())
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) AndReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("and", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *ppc64le) OrImm(dest Reg, value int) {
+	a.check(dest)
+	if value >= 0 && value <= 0xffff {
+		a.insn("ori", a.reg(dest), a.reg(dest), a.imm(value))
+	} else {
+		a.moveImm(a.scratch(), int64(value))
+		a.insn("or", a.reg(dest), a.reg(dest), a.scratch())
+	}
+	a.Set(dest)
+}
+
+func (a *ppc64le) OrReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("or", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *ppc64le) ShiftImm(s Shift, r Reg, count int) {
+	a.check(r)
+	if count != 0 {
+		a.insn(a.shift(s), a.reg(r), a.reg(r), a.imm(count))
+	}
+	a.Set(r)
+}
+
+func (a *ppc64le) Load(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("ld", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *ppc64le) Load4Bytes(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("lwz", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *ppc64le) LoadByte(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("lbz", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *ppc64le) Store(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("std", a.reg(src), a.mem(base, offset))
+}
+
+func (a *ppc64le) Store4Bytes(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("stw", a.reg(src), a.mem(base, offset))
+}
+
+func (a *ppc64le) LoadThreadPointer(dest Reg) {
+	a.insn("mr", a.reg(dest), GPR13.reg())
+	a.Set(dest)
+}
+
+func (a *ppc64le) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	switch model {
+	case InitialExecTLS:
+		a.initialExecTLS(dest, name)
+	case LocalExecTLS:
+		a.insn("addis", a.reg(dest), GPR13.reg(), symbol(name)+"@tprel@ha")
+		a.insn("addi", a.reg(dest), a.reg(dest), symbol(name)+"@tprel@l")
+	default:
+		panic(model)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+// LoadThreadLocal uses DS-form instruction, so the address must be aligned.
+func (a *ppc64le) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	switch model {
+	case InitialExecTLS:
+		a.initialExecTLS(dest, name)
+		a.insn("ld", a.reg(dest), a.mem(dest, offset))
+	case LocalExecTLS:
+		expr := "(" + symbolOffset(name, offset) + ")"
+		a.insn("addis", a.reg(dest), GPR13.reg(), expr+"@tprel@ha")
+		a.insnf("ld %s, %s@tprel@l(%s)", a.reg(dest), expr, a.reg(dest))
+	default:
+		panic(model)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+func (a *ppc64le) initialExecTLS(r Reg, name string) {
+	a.insn("addis", a.reg(r), GPR2.reg(), symbol(name)+"@got@tprel@ha")
+	a.insnf("ld %s, %s@got@tprel@l(%s)", a.reg(r), symbol(name), a.reg(r))
+	a.insn("add", a.reg(r), a.reg(r), symbol(name)+"@tls")
+}
+
+// LoadGlobal uses DS-form instruction, so the address must be aligned.
+func (a *ppc64le) LoadGlobal(dest Reg, name string, offset int) {
+	a.loadGlobal("ld", dest, name, offset)
+}
+
+func (a *ppc64le) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.loadGlobal("lwz", dest, name, offset)
+}
+
+func (a *ppc64le) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.loadGlobal("lbz", dest, name, offset)
+}
+
+// StoreGlobal uses DS-form instruction, so the address must be aligned.
+func (a *ppc64le) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.storeGlobal("std", name, offset, src, temp)
+}
+
+func (a *ppc64le) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.storeGlobal("stw", name, offset, src, temp)
+}
+
+func (a *ppc64le) loadGlobal(mnemonic string, dest Reg, name string, offset int) {
+	if a.external[name] {
+		a.Address(dest, name)
+		a.insn(mnemonic, a.reg(dest), a.mem(dest, offset))
+		a.Set(dest)
+		return
+	}
+
+	expr := "(" + symbolOffset(name, offset) + ")"
+	a.insn("addis", a.reg(dest), GPR2.reg(), expr+"@toc@ha")
+	a.insnf("%s %s, %s@toc@l(%s)", mnemonic, a.reg(dest), expr, a.reg(dest))
+	a.Set(dest)
+}
+
+func (a *ppc64le) storeGlobal(mnemonic, name string, offset int, src, temp Reg) {
+	a.check(src)
+	if a.external[name] {
+		a.Address(temp, name)
+		a.insn(mnemonic, a.reg(src), a.mem(temp, offset))
+		a.Set(temp.As(""))
+		return
+	}
+
+	expr := "(" + symbolOffset(name, offset) + ")"
+	a.insn("addis", a.reg(temp), GPR2.reg(), expr+"@toc@ha")
+	a.insnf("%s %s, %s@toc@l(%s)", mnemonic, a.reg(src), expr, a.reg(temp))
+	a.Set(temp.As(""))
+}
+
+// Push allocates a slot the size of an ELFv2 frame header, so that the stack
+// pointer stays aligned.  The value is stored in the back chain position: a
+// callee may save CR, link register and TOC pointer 8-31 bytes above the stack
+// pointer.
+func (a *ppc64le) Push(r Reg) {
+	a.check(r)
+	a.insn("stdu", a.reg(r), a.mem(a.StackPtr, -minFramePPC64LE))
+	a.cfiAdjust(minFramePPC64LE)
+}
+
+func (a *ppc64le) Pop(r Reg) {
+	a.insn("ld", a.reg(r), a.mem(a.StackPtr, 0))
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(minFramePPC64LE))
+	a.cfiAdjust(-minFramePPC64LE)
+	a.Set(r)
+}
+
+func (a *ppc64le) Jump(name string) {
+	a.insn("b", symbol(name))
+}
+
+func (a *ppc64le) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	a.check(r)
+	a.insn("mtctr", a.reg(r))
+	a.insn("bctr")
+	a.speculationBarrier()
+}
+
+func (a *ppc64le) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.bit(r, bit)
+	a.insn("bne", symbol(name))
+}
+
+func (a *ppc64le) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.bit(r, bit)
+	a.insn("beq", symbol(name))
+}
+
+func (a *ppc64le) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.check(r)
+	if imm16(value) {
+		a.insn("cmpdi", a.reg(r), a.imm(value))
+	} else {
+		a.moveImm(a.scratch(), int64(value))
+		a.insn("cmpd", a.reg(r), a.scratch())
+	}
+	a.insn("b"+a.cond(c), symbol(name))
+}
+
+func (a *ppc64le) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.check(dest)
+	a.check(src)
+	a.insn("cmpd", a.reg(dest), a.reg(src))
+	a.insn("b"+a.cond(c), symbol(name))
+}
+
+// Call leaves a nop after the branch for the linker to restore the TOC
+// pointer when calling across modules.
+func (a *ppc64le) Call(name string) {
+	a.refer(name)
+	a.insn("bl", symbol(name))
+	a.insn("nop")
+}
+
+// Syscall result is normalized to negative errno: the kernel sets the
+// summary overflow bit of CR0 and returns positive errno on error.
+func (a *ppc64le) Syscall(nr Syscall) {
+	a.insn("li", a.reg(a.SyscallNr), a.imm(nr.num(PPC64LE)))
+	a.insn("sc")
+	a.insn("bns", ".+8")
+	a.insn("neg", a.reg(a.SysResult), a.reg(a.SysResult))
+	a.Set(a.SysResult)
+}
+
+func (a *ppc64le) Unreachable() {
+	a.insn("trap")
+}
+
+func (a *ppc64le) speculationBarrier() {
+	a.insn("trap")
+}
+
+// bit sets CR0 according to the bit.
+func (a *ppc64le) bit(r Reg, bit uint) {
+	a.insn("rldicl.", a.scratch(), a.reg(r), a.imm(int((64-bit)%64)), a.imm(63))
+}
+
+func (a *ppc64le) moveImm(dest string, value int64) {
+	switch {
+	case imm16(int(value)):
+		a.insn("li", dest, a.imm(int(value)))
+
+	case value == int64(int32(value)):
+		a.insn("lis", dest, a.imm(int(int16(value>>16))))
+		if lo := int(uint16(value)); lo != 0 {
+			a.insn("ori", dest, dest, a.imm(lo))
+		}
+
+	default:
+		a.moveImm(dest, int64(int32(value>>32)))
+		a.insn("sldi", dest, dest, a.imm(32))
+		if hi := int(uint16(value >> 16)); hi != 0 {
+			a.insn("oris", dest, dest, a.imm(hi))
+		}
+		if lo := int(uint16(value)); lo != 0 {
+			a.insn("ori", dest, dest, a.imm(lo))
+		}
+	}
+}
+
+func imm16(x int) bool {
+	return x >= -0x8000 && x <= 0x7fff
+}
+
+func (a *ppc64le) scratch() string {
+	return GPR0.reg()
+}
+
+func (a *ppc64le) imm(x int) string {
+	return fmt.Sprintf("%d", x)
+}
+
+func (a *ppc64le) mem(base Reg, offset int) string {
+	return fmt.Sprintf("%d(%s)", offset, a.reg(base))
+}
+
+func (a *ppc64le) reg(x Reg) string {
+	return x.PPC64LE.reg()
+}
+
+func (a *ppc64le) floatreg(x FloatReg) string {
+	return fmt.Sprintf("%%f%d", x)
+}
+
+func (a *ppc64le) cond(x Cond) string {
+	switch x {
+	case EQ:
+		return "eq"
+	case NE:
+		return "ne"
+	case LT:
+		return "lt"
+	case LE:
+		return "le"
+	case GT:
+		return "gt"
+	case GE:
+		return "ge"
+	}
+
+	panic(x)
+}
+
+func (a *ppc64le) shift(x Shift) string {
+	switch x {
+	case Left:
+		return "sldi"
+	case RightLogical:
+		return "srdi"
+	case RightArithmetic:
+		return "sradi"
+	}
+
+	panic(x)
+}
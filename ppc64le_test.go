@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func TestPPC64LEStackFrame(t *testing.T) {
+	a := ga.NewAssembly(ga.PPC64LE, sys)
+	a.Function("f")
+	a.Set(r0)
+	a.Push(r0)
+	a.Call("g")
+	a.Pop(r0)
+	a.Return()
+
+	s := a.String()
+	for _, insn := range []string{
+		"addis\t%r2, %r12, .TOC.-\"f\"@ha\n\taddi\t%r2, %r2, .TOC.-\"f\"@l\n.localentry\t\"f\", .-\"f\"\n",
+		"mflr\t%r0\n\tstd\t%r0, 16(%r1)\n\tstdu\t%r1, -32(%r1)\n",
+		"stdu\t%r14, -32(%r1)\n\tbl\t\"g\"\n\tnop\n\tld\t%r14, 0(%r1)\n\taddi\t%r1, %r1, 32\n",
+		"addi\t%r1, %r1, 32\n\tld\t%r0, 16(%r1)\n\tmtlr\t%r0\n\tblr\n",
+	} {
+		if !strings.Contains(s, insn) {
+			t.Errorf("%q not found in:\n%s", insn, s)
+		}
+	}
+}
+
+func TestPPC64LEReservedRegister(t *testing.T) {
+	reserved := ga.Reg{AMD64: ga.R15, ARM64: ga.X23, RISCV64: ga.S5, PPC64LE: ga.GPR0, Use: "reserved"}
+
+	for name, gen := range map[string]func(*ga.Assembly){
+		"Set":  func(a *ga.Assembly) { a.Set(reserved) },
+		"Load": func(a *ga.Assembly) { a.Load(r0, reserved, 0) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Error("no panic")
+				} else if !strings.Contains(fmt.Sprint(x), "reserved") {
+					t.Error(x)
+				}
+			}()
+
+			a := ga.NewAssembly(ga.PPC64LE, sys)
+			gen(a)
+		})
+	}
+}
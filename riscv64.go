@@ -0,0 +1,611 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+const headerRISCV64 = header1 + header2
+
+type RegRISCV64 uint8
+
+const (
+	ZERO RegRISCV64 = iota
+	RA
+	SP
+	GP
+	TP
+	T0
+	T1
+	T2
+	S0
+	S1
+	A0
+	A1
+	A2
+	A3
+	A4
+	A5
+	A6
+	A7
+	S2
+	S3
+	S4
+	S5
+	S6
+	S7
+	S8
+	S9
+	S10
+	S11
+	T3
+	T4
+	T5
+	T6 // Scratch register of the backend.
+)
+
+func (r RegRISCV64) String() string {
+	return r.reg()
+}
+
+func (r RegRISCV64) reg() string {
+	switch {
+	case r == ZERO:
+		return "zero"
+	case r == RA:
+		return "ra"
+	case r == SP:
+		return "sp"
+	case r == GP:
+		return "gp"
+	case r == TP:
+		return "tp"
+	case r <= T2:
+		return fmt.Sprintf("t%d", r-T0)
+	case r <= S1:
+		return fmt.Sprintf("s%d", r-S0)
+	case r <= A7:
+		return fmt.Sprintf("a%d", r-A0)
+	case r <= S11:
+		return fmt.Sprintf("s%d", r-S2+2)
+	case r <= T6:
+		return fmt.Sprintf("t%d", r-T3+3)
+	}
+
+	panic(r)
+}
+
+var RISCV64 = &ArchRISCV64{
+	ClearableRegs: []RegRISCV64{
+		// ZERO
+		RA,
+		// SP
+		// GP
+		// TP
+		T0,
+		T1,
+		T2,
+		S0,
+		S1,
+		A0,
+		A1,
+		A2,
+		A3,
+		A4,
+		A5,
+		A6,
+		A7,
+		S2,
+		S3,
+		S4,
+		S5,
+		S6,
+		S7,
+		S8,
+		S9,
+		S10,
+		S11,
+		T3,
+		T4,
+		T5,
+		// T6
+	},
+}
+
+type ArchRISCV64 struct {
+	ClearableRegs []RegRISCV64
+}
+
+func (*ArchRISCV64) ID() ArchID {
+	return IDRISCV64
+}
+
+func (*ArchRISCV64) Machine() string {
+	return "riscv64"
+}
+
+func (*ArchRISCV64) Specify(x Specific) int {
+	return x.RISCV64
+}
+
+func (arch *ArchRISCV64) ClearReg(a *Assembly, r RegRISCV64) {
+	if a.Arch != arch {
+		panic(a.Arch)
+	}
+	a.insn("li", r.reg(), "0")
+}
+
+func (*ArchRISCV64) NewArchAssembly(sys *System, out *Output) ArchAssembly {
+	if out.Syntax() != GNUSyntax {
+		panic("unsupported syntax for riscv64")
+	}
+
+	out.buf.WriteString(headerRISCV64)
+	return &riscv64{
+		System: sys,
+		buffer: out.buf,
+	}
+}
+
+type riscv64 struct {
+	*System
+	*buffer
+}
+
+func (a *riscv64) check(r Reg) {
+	a.checkReserved(r)
+	a.checkUsage(uint8(r.RISCV64), r.Use)
+}
+
+func (a *riscv64) Set(r Reg) {
+	a.checkReserved(r)
+	a.buffer.regUsage[r.RISCV64] = r.Use
+}
+
+// checkReserved panics if the register is the scratch register, which is
+// overwritten by the backend without notice.
+func (a *riscv64) checkReserved(r Reg) {
+	if r.RISCV64 == T6 {
+		panic(fmt.Sprintf("register %s (%s) is reserved", r.RISCV64, r.Use))
+	}
+}
+
+func (a *riscv64) Label(name string, opts ...SymbolOptions) {
+	if global(name) {
+		a.declare(name, "@function", opts)
+	} else {
+		symbolOptions(name, opts)
+	}
+	a.printf("")
+	a.label(name)
+	if global(name) {
+		a.cfiStartProc(0)
+	}
+}
+
+func (a *riscv64) FunctionEpilogue() {
+	a.cfiRememberState()
+	if a.framePtr {
+		a.cfiDefStack(a.reg(a.StackPtr))
+		a.insn("ld", a.reg(a.FramePtr), a.mem(a.StackPtr, 0))
+		a.cfiRestore(a.reg(a.FramePtr), 0)
+	}
+	a.insn("ld", "ra", a.mem(a.StackPtr, 8))
+	a.cfiRestore("ra", 8)
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(16))
+	a.cfiAdjust(-16)
+}
+
+// Function prologue.  The return address is stored in a 16-byte slot so that
+// the stack pointer stays aligned.  With frame pointer, the frame record is
+// stored in the same slot, and the frame pointer points to the caller's stack
+// pointer.
+func (a *riscv64) Function(name string, opts ...SymbolOptions) {
+	a.FunctionWithoutPrologue(name, opts...)
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(-16))
+	a.cfiAdjust(16)
+	a.insn("sd", "ra", a.mem(a.StackPtr, 8))
+	a.cfiSave("ra", 8)
+	if a.framePtr {
+		a.insn("sd", a.reg(a.FramePtr), a.mem(a.StackPtr, 0))
+		a.cfiSave(a.reg(a.FramePtr), 0)
+		a.insn("addi", a.reg(a.FramePtr), a.reg(a.StackPtr), a.imm(16))
+		a.cfiDefFrame(a.reg(a.FramePtr), 16)
+	}
+}
+
+func (a *riscv64) FunctionWithoutPrologue(name string, opts ...SymbolOptions) {
+	a.declare(name, "@function", opts)
+	a.printf("")
+	a.label(name)
+	a.cfiStartProc(0)
+}
+
+func (a *riscv64) Return() {
+	a.FunctionEpilogue()
+	a.ReturnWithoutEpilogue()
+}
+
+func (a *riscv64) ReturnWithoutEpilogue() {
+	a.insn("ret")
+	a.speculationBarrier()
+	a.cfiRestoreState()
+}
+
+func (a *riscv64) Address(dest Reg, name string) {
+	if a.external[name] {
+		a.printf("1:")
+		a.insnf("auipc %s, %%got_pcrel_hi(%s)", a.reg(dest), symbol(name))
+		a.insnf("ld %s, %%pcrel_lo(1b)(%s)", a.reg(dest), a.reg(dest))
+	} else {
+		a.insn("lla", a.reg(dest), symbol(name))
+	}
+	a.Set(dest)
+}
+
+func (a *riscv64) MoveDef(dest Reg, name string) {
+	a.checkAbsolute(name)
+	a.insn("li", a.reg(dest), symbol(name))
+	a.Set(dest)
+}
+
+func (a *riscv64) MoveImm(dest Reg, value int) {
+	a.insn("li", a.reg(dest), a.imm(value))
+	a.Set(dest)
+}
+
+func (a *riscv64) MoveImm64(dest Reg, value uint64) {
+	a.insn("li", a.reg(dest), a.imm(int(int64(value))))
+	a.Set(dest)
+}
+
+func (a *riscv64) MoveReg(dest, src Reg) {
+	a.check(src)
+	if a.reg(dest) != a.reg(src) {
+		a.insn("mv", a.reg(dest), a.reg(src))
+	}
+	a.Set(dest)
+}
+
+func (a *riscv64) MoveRegFloat(dest Reg, src FloatReg) {
+	a.insn("fmv.x.d", a.reg(dest), a.floatreg(src))
+	a.Set(dest)
+}
+
+func (a *riscv64) AddImm(dest, src Reg, value int) {
+	a.check(src)
+	if imm12(value) {
+		a.insn("addi", a.reg(dest), a.reg(src), a.imm(value))
+	} else {
+		a.insn("li", a.scratch(), a.imm(value))
+		a.insn("add", a.reg(dest), a.reg(src), a.scratch())
+	}
+	a.Set(dest)
+}
+
+func (a *riscv64) AddReg(dest, src1, src2 Reg) {
+	a.check(src1)
+	a.check(src2)
+	a.insn("add", a.reg(dest), a.reg(src1), a.reg(src2))
+	a.Set(dest)
+}
+
+func (a *riscv64) SubtractImm(dest Reg, value int) {
+	a.check(dest)
+	if value != 0 {
+		a.AddImm(dest, dest, -value)
+	}
+	a.Set(dest)
+}
+
+func (a *riscv64) SubtractReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("sub", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *riscv64) MultiplyImm(dest, src Reg, value int, temp Reg) {
+	a.check(src)
+	a.MoveImm(temp, value)
+	a.insn("mul", a.reg(dest), a.reg(src), a.reg(temp))
+	a.Set(dest)
+	a.Set(temp.As(""))
+}
+
+func (a *riscv64) AndImm(dest Reg, value int) {
+	a.check(dest)
+	a.logicImm("and", dest, value)
+	a.Set(dest)
+}
+
+func (a *riscv64) AndReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("and", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *riscv64) OrImm(dest Reg, value int) {
+	a.check(dest)
+	a.logicImm("or", dest, value)
+	a.Set(dest)
+}
+
+func (a *riscv64) OrReg(dest, src Reg) {
+	a.check(dest)
+	a.check(src)
+	a.insn("or", a.reg(dest), a.reg(dest), a.reg(src))
+	a.Set(dest)
+}
+
+func (a *riscv64) ShiftImm(s Shift, r Reg, count int) {
+	a.check(r)
+	if count != 0 {
+		a.insn(a.shift(s), a.reg(r), a.reg(r), a.imm(count))
+	}
+	a.Set(r)
+}
+
+func (a *riscv64) Load(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("ld", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *riscv64) Load4Bytes(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("lwu", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *riscv64) LoadByte(dest, base Reg, offset int) {
+	a.check(base)
+	a.insn("lbu", a.reg(dest), a.mem(base, offset))
+	a.Set(dest)
+}
+
+func (a *riscv64) Store(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("sd", a.reg(src), a.mem(base, offset))
+}
+
+func (a *riscv64) Store4Bytes(base Reg, offset int, src Reg) {
+	a.check(base)
+	a.check(src)
+	a.insn("sw", a.reg(src), a.mem(base, offset))
+}
+
+func (a *riscv64) LoadThreadPointer(dest Reg) {
+	a.insn("mv", a.reg(dest), "tp")
+	a.Set(dest)
+}
+
+func (a *riscv64) ThreadLocalAddress(dest Reg, name string, model TLSModel, temp Reg) {
+	if expr := a.threadLocal(dest, name, 0, model); expr != "0" {
+		a.insn("addi", a.reg(dest), a.reg(dest), expr)
+	}
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+func (a *riscv64) LoadThreadLocal(dest Reg, name string, offset int, model TLSModel, temp Reg) {
+	expr := a.threadLocal(dest, name, offset, model)
+	a.insnf("ld %s, %s(%s)", a.reg(dest), expr, a.reg(dest))
+	a.Set(temp.As(""))
+	a.Set(dest)
+}
+
+// threadLocal computes the high part of thread-local variable address into
+// the register.  The returned expression is the remaining offset.
+func (a *riscv64) threadLocal(r Reg, name string, offset int, model TLSModel) string {
+	switch model {
+	case InitialExecTLS:
+		a.printf("1:")
+		a.insnf("auipc %s, %%tls_ie_pcrel_hi(%s)", a.reg(r), symbol(name))
+		a.insnf("ld %s, %%pcrel_lo(1b)(%s)", a.reg(r), a.reg(r))
+		a.insn("add", a.reg(r), a.reg(r), "tp")
+		return fmt.Sprint(offset)
+	case LocalExecTLS:
+		expr := symbolOffset(name, offset)
+		a.insnf("lui %s, %%tprel_hi(%s)", a.reg(r), expr)
+		a.insnf("add %s, %s, tp, %%tprel_add(%s)", a.reg(r), a.reg(r), expr)
+		return "%tprel_lo(" + expr + ")"
+	}
+	panic(model)
+}
+
+func (a *riscv64) LoadGlobal(dest Reg, name string, offset int) {
+	a.loadGlobal("ld", dest, name, offset)
+}
+
+func (a *riscv64) LoadGlobal4Bytes(dest Reg, name string, offset int) {
+	a.loadGlobal("lwu", dest, name, offset)
+}
+
+func (a *riscv64) LoadGlobalByte(dest Reg, name string, offset int) {
+	a.loadGlobal("lbu", dest, name, offset)
+}
+
+func (a *riscv64) StoreGlobal(name string, offset int, src, temp Reg) {
+	a.storeGlobal("sd", name, offset, src, temp)
+}
+
+func (a *riscv64) StoreGlobal4Bytes(name string, offset int, src, temp Reg) {
+	a.storeGlobal("sw", name, offset, src, temp)
+}
+
+func (a *riscv64) loadGlobal(mnemonic string, dest Reg, name string, offset int) {
+	if a.external[name] {
+		a.Address(dest, name)
+		a.insn(mnemonic, a.reg(dest), a.mem(dest, offset))
+	} else {
+		a.insn(mnemonic, a.reg(dest), symbolOffset(name, offset))
+	}
+	a.Set(dest)
+}
+
+func (a *riscv64) storeGlobal(mnemonic, name string, offset int, src, temp Reg) {
+	a.check(src)
+	if a.external[name] {
+		a.Address(temp, name)
+		a.insn(mnemonic, a.reg(src), a.mem(temp, offset))
+	} else {
+		a.insn(mnemonic, a.reg(src), symbolOffset(name, offset), a.reg(temp))
+	}
+	a.Set(temp.As(""))
+}
+
+// Push uses a 16-byte slot to keep the stack pointer aligned.
+func (a *riscv64) Push(r Reg) {
+	a.check(r)
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(-16))
+	a.cfiAdjust(16)
+	a.insn("sd", a.reg(r), a.mem(a.StackPtr, 0))
+}
+
+func (a *riscv64) Pop(r Reg) {
+	a.insn("ld", a.reg(r), a.mem(a.StackPtr, 0))
+	a.insn("addi", a.reg(a.StackPtr), a.reg(a.StackPtr), a.imm(16))
+	a.cfiAdjust(-16)
+	a.Set(r)
+}
+
+func (a *riscv64) Jump(name string) {
+	a.insn("j", symbol(name))
+}
+
+func (a *riscv64) JumpRegRoutine(r Reg, internalNamePrefix string) {
+	a.check(r)
+	a.insn("jr", a.reg(r))
+	a.speculationBarrier()
+}
+
+func (a *riscv64) JumpIfBitSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.bit(r, bit)
+	a.insn("bltz", a.scratch(), symbol(name))
+}
+
+func (a *riscv64) JumpIfBitNotSet(r Reg, bit uint, name string) {
+	a.check(r)
+	a.bit(r, bit)
+	a.insn("bgez", a.scratch(), symbol(name))
+}
+
+func (a *riscv64) JumpIfImm(c Cond, r Reg, value int, name string) {
+	a.check(r)
+	if value == 0 {
+		a.insn("b"+a.cond(c)+"z", a.reg(r), symbol(name))
+	} else {
+		a.insn("li", a.scratch(), a.imm(value))
+		a.insn("b"+a.cond(c), a.reg(r), a.scratch(), symbol(name))
+	}
+}
+
+func (a *riscv64) JumpIfReg(c Cond, dest, src Reg, name string) {
+	a.check(dest)
+	a.check(src)
+	a.insn("b"+a.cond(c), a.reg(dest), a.reg(src), symbol(name))
+}
+
+func (a *riscv64) Call(name string) {
+	a.refer(name)
+	if a.external[name] {
+		a.insn("call", symbol(name)+"@plt")
+	} else {
+		a.insn("call", symbol(name))
+	}
+}
+
+func (a *riscv64) Syscall(nr Syscall) {
+	a.insn("li", a.reg(a.SyscallNr), a.imm(nr.num(RISCV64)))
+	a.insn("ecall")
+	a.Set(a.SysResult)
+}
+
+func (a *riscv64) Unreachable() {
+	a.insn("ebreak")
+}
+
+func (a *riscv64) speculationBarrier() {
+	a.insn("ebreak")
+}
+
+// bit moves the bit to the sign position of the scratch register.
+func (a *riscv64) bit(r Reg, bit uint) {
+	if bit == 63 {
+		a.insn("mv", a.scratch(), a.reg(r))
+	} else {
+		a.insn("slli", a.scratch(), a.reg(r), a.imm(int(63-bit)))
+	}
+}
+
+func (a *riscv64) logicImm(op string, dest Reg, value int) {
+	if imm12(value) {
+		a.insn(op+"i", a.reg(dest), a.reg(dest), a.imm(value))
+	} else {
+		a.insn("li", a.scratch(), a.imm(value))
+		a.insn(op, a.reg(dest), a.reg(dest), a.scratch())
+	}
+}
+
+func imm12(x int) bool {
+	return x >= -0x800 && x <= 0x7ff
+}
+
+func (a *riscv64) scratch() string {
+	return T6.reg()
+}
+
+func (a *riscv64) imm(x int) string {
+	return fmt.Sprintf("%d", x)
+}
+
+func (a *riscv64) mem(base Reg, offset int) string {
+	return fmt.Sprintf("%d(%s)", offset, a.reg(base))
+}
+
+func (a *riscv64) reg(x Reg) string {
+	return x.RISCV64.reg()
+}
+
+func (a *riscv64) floatreg(x FloatReg) string {
+	return fmt.Sprintf("f%d", x)
+}
+
+func (a *riscv64) cond(x Cond) string {
+	switch x {
+	case EQ:
+		return "eq"
+	case NE:
+		return "ne"
+	case LT:
+		return "lt"
+	case LE:
+		return "le"
+	case GT:
+		return "gt"
+	case GE:
+		return "ge"
+	}
+
+	panic(x)
+}
+
+func (a *riscv64) shift(x Shift) string {
+	switch x {
+	case Left:
+		return "slli"
+	case RightLogical:
+		return "srli"
+	case RightArithmetic:
+		return "srai"
+	}
+
+	panic(x)
+}
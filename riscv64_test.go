@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/linux"
+)
+
+func TestRISCV64StackAlignment(t *testing.T) {
+	a := ga.NewAssembly(ga.RISCV64, sys)
+	a.Function("f")
+	a.Set(r0)
+	a.Push(r0)
+	a.Pop(r0)
+	a.Return()
+
+	s := a.String()
+	for _, insn := range []string{
+		"addi\tsp, sp, -16\n\tsd\tra, 8(sp)\n",
+		"addi\tsp, sp, -16\n\tsd\ts1, 0(sp)\n",
+		"ld\ts1, 0(sp)\n\taddi\tsp, sp, 16\n",
+		"ld\tra, 8(sp)\n\taddi\tsp, sp, 16\n",
+	} {
+		if !strings.Contains(s, insn) {
+			t.Errorf("%q not found in:\n%s", insn, s)
+		}
+	}
+}
+
+func TestRISCV64ReservedRegister(t *testing.T) {
+	reserved := ga.Reg{AMD64: ga.R15, ARM64: ga.X23, RISCV64: ga.T6, Use: "reserved"}
+
+	for name, gen := range map[string]func(*ga.Assembly){
+		"Set":     func(a *ga.Assembly) { a.Set(reserved) },
+		"MoveImm": func(a *ga.Assembly) { a.MoveImm(reserved, 1) },
+		"AddReg":  func(a *ga.Assembly) { a.AddReg(r0, r0, reserved) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if x := recover(); x == nil {
+					t.Error("no panic")
+				} else if !strings.Contains(fmt.Sprint(x), "reserved") {
+					t.Error(x)
+				}
+			}()
+
+			a := ga.NewAssembly(ga.RISCV64, sys)
+			a.Set(r0)
+			gen(a)
+		})
+	}
+}
+
+func TestRISCV64UnavailableSyscall(t *testing.T) {
+	defer func() {
+		if x := recover(); x == nil {
+			t.Error("no panic")
+		}
+	}()
+
+	a := ga.NewAssembly(ga.RISCV64, sys)
+	a.Syscall(linux.SYS_OPEN)
+}
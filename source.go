@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// SourceInfo selects how instructions are mapped to the Go code which
+// generated them.
+type SourceInfo uint8
+
+const (
+	NoSourceInfo   SourceInfo = iota
+	SourceLines               // .file and .loc directives (#line with C).
+	SourceComments            // Comment with file name and line after each instruction.
+)
+
+type sourceLocation struct {
+	file string
+	line int
+}
+
+func (loc sourceLocation) comment(prefix string) string {
+	return fmt.Sprintf("%s %s:%d", prefix, filepath.Base(loc.file), loc.line)
+}
+
+// commentPrefix starts a comment which extends to the end of line.
+func commentPrefix(id ArchID, s Syntax) string {
+	if s == GoSyntax || id == IDARM64 || id == IDC {
+		return "//"
+	}
+	return "#"
+}
+
+var packagePrefix = reflect.TypeOf(buffer{}).PkgPath() + "."
+
+// callerLocation finds the innermost caller outside this package.
+func callerLocation() (loc sourceLocation, found bool) {
+	pc := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, packagePrefix) {
+			return sourceLocation{f.File, f.Line}, f.File != ""
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// sourceLocation of code which is being generated, if enabled.
+func (b *buffer) sourceLocation() (loc sourceLocation, found bool) {
+	if b.sourceInfo == NoSourceInfo || b.section != TextSection {
+		return
+	}
+	return callerLocation()
+}
+
+// insnSource emits a line number directive before an instruction if the
+// location has changed.  The returned string terminates the instruction line.
+func (b *buffer) insnSource(mnemonic string) string {
+	if strings.HasPrefix(mnemonic, ".") {
+		return "\n"
+	}
+
+	loc, found := b.sourceLocation()
+	if !found {
+		return "\n"
+	}
+
+	switch b.sourceInfo {
+	case SourceLines:
+		if loc != b.loc {
+			num, found := b.files[loc.file]
+			if !found {
+				if b.files == nil {
+					b.files = make(map[string]int)
+				}
+				num = len(b.files) + 1
+				b.files[loc.file] = num
+				b.printf(".file %d %s", num, quoteString(loc.file))
+			}
+			b.printf(".loc %d %d", num, loc.line)
+			b.loc = loc
+		}
+
+	case SourceComments:
+		return "\t" + loc.comment(b.comment) + "\n"
+	}
+
+	return "\n"
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+// sourceInfo generates two instructions on consecutive lines and returns the
+// file name and the line of the first one.
+func sourceInfo(a *ga.Assembly) (file string, line int) {
+	a.Function("f")
+	_, file, line, _ = runtime.Caller(0)
+	a.MoveImm64(r0, 0xfedcba9876543210)
+	a.MoveImm(r1, 1)
+	a.Return()
+	return file, line + 1
+}
+
+func TestSourceLines(t *testing.T) {
+	for _, arch := range []ga.Arch{ga.AMD64, ga.ARM64, ga.RISCV64, ga.PPC64LE} {
+		t.Run(arch.Machine(), func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys, ga.WithSourceInfo(ga.SourceLines))
+			file, line := sourceInfo(a)
+			a.Section(ga.DataSection)
+			a.DataSymbol("f_data", 8)
+			a.DataWord8(1)
+			s := a.String()
+
+			if want := fmt.Sprintf(".file\t1 %q\n", file); strings.Count(s, want) != 1 {
+				t.Errorf("no single %q:\n%s", want, s)
+			}
+			for _, l := range []int{line, line + 1} {
+				if want := fmt.Sprintf(".loc\t1 %d\n", l); strings.Count(s, want) != 1 {
+					t.Errorf("no single %q:\n%s", want, s)
+				}
+			}
+			if strings.Contains(s[strings.Index(s, ".section\t.data"):], ".loc\t") {
+				t.Errorf("line info in data section:\n%s", s)
+			}
+
+			assembleCheck(t, arch, s)
+		})
+	}
+}
+
+func TestSourceLinesAddr2line(t *testing.T) {
+	addr2line, err := exec.LookPath("addr2line")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	a := ga.NewAssembly(ga.AMD64, sys, ga.WithSourceInfo(ga.SourceLines))
+	_, line := sourceInfo(a)
+	obj := gnuObject(t, a.String())
+
+	out, err := exec.Command(addr2line, "-e", obj, "0").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "source_test.go:" + strconv.Itoa(line); !strings.HasSuffix(strings.TrimSpace(string(out)), want) {
+		t.Errorf("addr2line: %q does not end with %q", out, want)
+	}
+}
+
+func TestSourceComments(t *testing.T) {
+	for _, test := range []struct {
+		arch   ga.Arch
+		syntax ga.Syntax
+		prefix string
+	}{
+		{ga.AMD64, ga.GNUSyntax, "#"},
+		{ga.AMD64, ga.ATTSyntax, "#"},
+		{ga.AMD64, ga.GoSyntax, "//"},
+		{ga.ARM64, ga.GNUSyntax, "//"},
+		{ga.RISCV64, ga.GNUSyntax, "#"},
+		{ga.PPC64LE, ga.GNUSyntax, "#"},
+	} {
+		t.Run(fmt.Sprintf("%s-%d", test.arch.Machine(), test.syntax), func(t *testing.T) {
+			a := ga.NewAssembly(test.arch, sys, ga.WithSyntax(test.syntax), ga.WithSourceInfo(ga.SourceComments))
+			_, line := sourceInfo(a)
+			s := a.String()
+
+			want := fmt.Sprintf("\t%s source_test.go:%d\n", test.prefix, line+1)
+			if !strings.Contains(s, want) {
+				t.Errorf("no %q:\n%s", want, s)
+			}
+			if strings.Contains(s, ".loc\t") {
+				t.Errorf("line directive in comment mode:\n%s", s)
+			}
+
+			switch {
+			case test.syntax == ga.GoSyntax:
+				goAssembleCheck(t, "amd64", s)
+			case test.arch == ga.AMD64:
+				gnuObject(t, s)
+			default:
+				assembleCheck(t, test.arch, s)
+			}
+		})
+	}
+}
+
+func TestSourceLinesC(t *testing.T) {
+	a := ga.NewAssembly(ga.C, sys, ga.WithSourceInfo(ga.SourceLines))
+	file, line := sourceInfo(a)
+	s := a.String()
+
+	if want := fmt.Sprintf("#line %d %q\n", line+1, file); !strings.Contains(s, want) {
+		t.Errorf("no %q:\n%s", want, s)
+	}
+	buildC(t, s, "-c")
+}
+
+func TestSourceLinesGoSyntax(t *testing.T) {
+	defer func() {
+		if x := fmt.Sprint(recover()); x != "source lines are not supported with this syntax" {
+			t.Errorf("panic: %s", x)
+		}
+	}()
+	ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.GoSyntax), ga.WithSourceInfo(ga.SourceLines))
+}
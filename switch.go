@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"sort"
+)
+
+const (
+	maxJumpTableSpan   = 4096 // Fits in an ARM64 compare immediate.
+	minJumpTableCases  = 4
+	maxJumpTableSparse = 3    // Table entries per case.
+	maxLinearCases     = 3    // Compare tree leaf size.
+	maxCompareImm      = 4095 // Fits in compare immediates of all backends.
+)
+
+// jumpTabler is implemented by backends which can jump via a table of 32-bit
+// offsets relative to the start of the table.
+type jumpTabler interface {
+	// jumpTable jumps to def if index is not below count (unsigned), and
+	// otherwise via the table entry.  If mask is nonzero, index is ANDed with
+	// it after the check.  Both registers are destroyed.
+	jumpTable(index, base Reg, count, mask int, table, def string)
+}
+
+// Switch jumps to the name associated with the value of r, or to def if there
+// is no such case.  Dense cases are dispatched via a bounds-checked jump table
+// in the read-only data section when the backend supports it (AMD64 and ARM64
+// with GNU assembler); otherwise a tree of comparisons is generated.  Case
+// values which don't fit in a compare immediate are moved to temp, and its
+// register usage is cleared.  If a jump table is used, the value of r is
+// destroyed unless the jump is to def, and its usage is cleared when the case
+// labels are defined.
+//
+// The table holds 32-bit offsets relative to its start.  AMD64 dispatches
+// with lea, movsxd, add and jmp, and ARM64 with adr, ldrsw, add and br.  The
+// adr instruction reaches ±1MiB, so the read-only data section must be placed
+// near the code on ARM64 (WithFarAddress doesn't apply).  See
+// WithIndexMasking.
+func (a *Assembly) Switch(r Reg, cases map[int]string, def string, temp Reg) {
+	values := make([]int, 0, len(cases))
+	for value := range cases {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+
+	if j, ok := a.ArchAssembly.(jumpTabler); ok && len(values) >= minJumpTableCases {
+		first := values[0]
+		span := values[len(values)-1] - first + 1
+		switch {
+		case first <= -maxJumpTableSpan, first >= maxJumpTableSpan:
+		case span <= 0, span > maxJumpTableSpan, span > len(values)*maxJumpTableSparse:
+		default:
+			a.switchTable(j, r, cases, def, temp, first, span)
+			a.Set(temp.As(""))
+			return
+		}
+	}
+
+	a.switchTree(r, values, cases, def, temp)
+	a.Set(temp.As(""))
+}
+
+func (a *Assembly) switchTable(j jumpTabler, r Reg, cases map[int]string, def string, temp Reg, first, span int) {
+	size := span
+	mask := 0
+	if a.indexMasking {
+		for size = 1; size < span; size <<= 1 {
+		}
+		mask = size - 1
+	}
+
+	table := a.internalLabel("table")
+
+	a.AddImm(temp, r, -first)
+	a.refer(table)
+	j.jumpTable(temp, r, span, mask, table, def)
+	a.recordJump(def)
+
+	targets := make([]string, size)
+	recorded := map[string]bool{def: true}
+	for i := range targets {
+		target, found := cases[first+i]
+		if !found {
+			target = def
+		}
+		targets[i] = target
+		if !recorded[target] {
+			a.recordJump(target)
+			s := a.jumps[target]
+			s.clobbers = append(s.clobbers, r, temp)
+			a.jumps[target] = s
+			recorded[target] = true
+		}
+	}
+
+	a.printf(".pushsection .rodata")
+	a.printf(".balign 4")
+	a.define(table)
+	a.printf("%s:", symbol(table))
+	for _, target := range targets {
+		a.insn(".4byte", symbol(target)+" - "+symbol(table))
+	}
+	a.printf(".popsection")
+}
+
+func (a *Assembly) switchTree(r Reg, values []int, cases map[int]string, def string, temp Reg) {
+	if len(values) <= maxLinearCases {
+		for _, value := range values {
+			a.switchCompare(EQ, r, value, cases[value], temp)
+		}
+		a.Jump(def)
+		return
+	}
+
+	i := len(values) / 2
+	upper := a.internalLabel("switch")
+	a.switchCompare(GE, r, values[i], upper, temp)
+	a.switchTree(r, values[:i], cases, def, temp)
+	a.Label(upper)
+	a.switchTree(r, values[i:], cases, def, temp)
+}
+
+func (a *Assembly) switchCompare(c Cond, r Reg, value int, name string, temp Reg) {
+	if value >= -maxCompareImm && value <= maxCompareImm {
+		a.JumpIfImm(c, r, value, name)
+	} else {
+		a.MoveImm(temp, value)
+		a.JumpIfReg(c, r, temp, name)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+// switchCases generates a dense switch and returns the case labels.
+func switchCases(a *ga.Assembly) (cases map[int]string, def string) {
+	cases = make(map[int]string)
+	for _, value := range []int{1, 2, 3, 5, 6} {
+		cases[value] = a.NewLabel("case")
+	}
+	def = a.NewLabel("default")
+
+	a.Function("dispatch")
+	a.MoveImm(r0, 3)
+	a.Switch(r0, cases, def, temp)
+	return
+}
+
+func TestSwitchTable(t *testing.T) {
+	for _, test := range []struct {
+		arch ga.Arch
+		opts []ga.Option
+		want string
+	}{
+		{ga.AMD64, nil, `
+	cmp	r14, 5
+	ja	"\.Ldefault\.\d+"
+	lea	rbx, \[rip \+ "\.Ltable\.\d+"\]
+	movsxd	r14, dword ptr \[rbx \+ r14\*4\]
+	add	r14, rbx
+	jmp	r14
+`},
+		{ga.AMD64, []ga.Option{ga.WithSyntax(ga.ATTSyntax), ga.WithIndexMasking()}, `
+	cmpq	\$5, %r14
+	ja	"\.Ldefault\.\d+"
+	andq	\$7, %r14
+	leaq	"\.Ltable\.\d+"\(%rip\), %rbx
+	movslq	\(%rbx,%r14,4\), %r14
+	addq	%rbx, %r14
+	jmp	\*%r14
+`},
+		{ga.ARM64, []ga.Option{ga.WithFarAddress()}, `
+	cmp	x22, 5
+	b\.hi	"\.Ldefault\.\d+"
+	adr	x19, "\.Ltable\.\d+"
+	ldrsw	x22, \[x19, x22, lsl 2\]
+	add	x22, x19, x22
+	br	x22
+`},
+	} {
+		t.Run(test.arch.Machine(), func(t *testing.T) {
+			a := ga.NewAssembly(test.arch, sys, test.opts...)
+			cases, def := switchCases(a)
+			a.Label(def)
+			for _, value := range []int{1, 2, 3, 5, 6} {
+				a.Label(cases[value])
+			}
+			a.Return()
+
+			s, err := a.BytesChecked()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !regexp.MustCompile(test.want).Match(s) {
+				t.Errorf("dispatch not found:\n%s", s)
+			}
+			if n := strings.Count(string(s), "\t.4byte\t"); n != 6 && n != 8 {
+				t.Errorf("%d table entries:\n%s", n, s)
+			}
+			assembleCheck(t, test.arch, string(s))
+		})
+	}
+}
+
+func TestSwitchTree(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			a.Function("dispatch")
+			a.MoveImm(r0, 3)
+			a.Switch(r0, map[int]string{-5000: ".a", 0: ".b", 7: ".a", 5000: ".b", 1 << 40: ".a"}, ".b", temp)
+			a.Label(".a")
+			a.Label(".b")
+			a.Return()
+
+			s, err := a.BytesChecked()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(s), ".rodata") {
+				t.Errorf("jump table:\n%s", s)
+			}
+			if arch == ga.C {
+				buildC(t, string(s), "-c")
+			} else {
+				assembleCheck(t, arch, string(s))
+			}
+		})
+	}
+}
+
+func TestSwitchUsage(t *testing.T) {
+	use := func(a *ga.Assembly, r ga.Reg) string {
+		return a.Usage()[r.Num(ga.AMD64.ID())]
+	}
+
+	a := ga.NewAssembly(ga.AMD64, sys)
+	cases, def := switchCases(a)
+	if use(a, r0) != "r0" || use(a, temp) != "" {
+		t.Errorf("usage after Switch: %q", a.Usage())
+	}
+
+	a.Label(def)
+	if use(a, r0) != "r0" {
+		t.Errorf("usage at default label: %q", a.Usage())
+	}
+
+	a.Label(cases[1])
+	if use(a, r0) != "" {
+		t.Errorf("usage at case label: %q", a.Usage())
+	}
+}
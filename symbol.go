@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+import (
+	"fmt"
+)
+
+type Visibility uint8
+
+const (
+	DefaultVisibility Visibility = iota
+	HiddenVisibility
+	ProtectedVisibility
+)
+
+// SymbolOptions of a global name defined by Function, Label or DataSymbol.
+// At most one SymbolOptions value may be passed.
+type SymbolOptions struct {
+	Visibility Visibility
+	Weak       bool
+
+	// Stack frame size and size of arguments and results of a TEXT symbol
+	// with Go syntax.  If the frame size is nonzero, the Go assembler
+	// allocates the frame (saving the link register if needed) in the
+	// prologue and releases it before returning.  Zero argument size is left
+	// unspecified.
+	GoFrameSize int
+	GoArgSize   int
+}
+
+func symbolOptions(name string, opts []SymbolOptions) SymbolOptions {
+	switch len(opts) {
+	case 0:
+		return SymbolOptions{}
+
+	case 1:
+		if !global(name) && opts[0] != (SymbolOptions{}) {
+			panic(fmt.Sprintf("local name %s cannot have symbol options", name))
+		}
+		return opts[0]
+	}
+
+	panic("multiple symbol options")
+}
+
+// checkNoGoSymbolOptions panics if options are specified which are supported
+// only with Go syntax.
+func checkNoGoSymbolOptions(name string, opts []SymbolOptions) SymbolOptions {
+	o := symbolOptions(name, opts)
+	if o.GoFrameSize != 0 || o.GoArgSize != 0 {
+		panic("Go frame and argument sizes are supported only with Go syntax")
+	}
+	return o
+}
+
+// checkGoSymbolOptions panics if options are specified which are not supported
+// with Go syntax.
+func checkGoSymbolOptions(name string, opts []SymbolOptions) SymbolOptions {
+	o := symbolOptions(name, opts)
+	if o.Visibility != DefaultVisibility || o.Weak {
+		panic("symbol visibility and weak binding are not supported with Go syntax")
+	}
+	return o
+}
+
+// declare ends the previous symbol.  If the name is global, its binding,
+// visibility and type are declared, and size will be emitted when the next
+// symbol is declared or the section is changed.
+func (b *buffer) declare(name, typ string, opts []SymbolOptions) {
+	o := checkNoGoSymbolOptions(name, opts)
+
+	b.endSymbol()
+
+	if !global(name) {
+		return
+	}
+
+	b.printf("")
+	if o.Weak {
+		b.printf(".weak %s", symbol(name))
+	} else {
+		b.printf(".globl %s", symbol(name))
+	}
+	switch o.Visibility {
+	case DefaultVisibility:
+	case HiddenVisibility:
+		b.printf(".hidden %s", symbol(name))
+	case ProtectedVisibility:
+		b.printf(".protected %s", symbol(name))
+	default:
+		panic(o.Visibility)
+	}
+	b.printf(".type  %s,%s", symbol(name), typ)
+
+	b.sized = name
+}
+
+// endSymbol ends the call frame information and size of the previous symbol.
+func (b *buffer) endSymbol() {
+	b.WriteString(b.symbolEnd())
+	b.cfi = cfiState{}
+	b.sized = ""
+}
+
+// symbolEnd returns the directives which are pending for the previous symbol.
+func (b *buffer) symbolEnd() string {
+	var s string
+	if b.cfi.open {
+		s += ".cfi_endproc\n"
+	}
+	if b.sized != "" {
+		s += fmt.Sprintf(".size\t%s, .-%s\n", symbol(b.sized), symbol(b.sized))
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func symbols(a *ga.Assembly) {
+	a.Function("sym_default")
+	a.Return()
+	a.Function("sym_hidden", ga.SymbolOptions{Visibility: ga.HiddenVisibility})
+	a.Return()
+	a.FunctionWithoutPrologue("sym_weak", ga.SymbolOptions{Weak: true, Visibility: ga.ProtectedVisibility})
+	a.ReturnWithoutEpilogue()
+	a.Label("sym_label")
+	a.Label(".sym_local")
+	a.ReturnWithoutEpilogue()
+	a.Section(ga.DataSection)
+	a.DataSymbol("sym_object", 8, ga.SymbolOptions{Visibility: ga.HiddenVisibility})
+	a.DataWord8(1)
+	a.DataWord4(2)
+}
+
+func TestSymbols(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue // See TestSymbolsC.
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			symbols(a)
+			s := a.String()
+			for _, x := range []string{
+				".globl\t\"sym_default\"\n",
+				".hidden\t\"sym_hidden\"\n",
+				".weak\t\"sym_weak\"\n",
+				".protected\t\"sym_weak\"\n",
+				".type\t\"sym_weak\",@function\n",
+				".type\t\"sym_object\",@object\n",
+				".size\t\"sym_label\", .-\"sym_label\"\n\n.section\t.data\n",
+				".size\t\"sym_object\", .-\"sym_object\"\n",
+			} {
+				if !strings.Contains(s, x) {
+					t.Errorf("%q not found in output:\n%s", x, s)
+				}
+			}
+			if strings.Contains(s, "sym_local\",@function") {
+				t.Errorf("local label has type:\n%s", s)
+			}
+			assembleCheck(t, arch, s)
+		})
+	}
+}
+
+// TestSymbolTable checks the symbols of an x86-64 object file.
+func TestSymbolTable(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+	symbols(a)
+	obj := gnuObject(t, a.String())
+
+	out, err := exec.Command("readelf", "--syms", "--wide", obj).Output()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Value, size, type, binding, visibility.
+	want := map[string]string{
+		"sym_default": "FUNC GLOBAL DEFAULT",
+		"sym_hidden":  "FUNC GLOBAL HIDDEN",
+		"sym_weak":    "FUNC WEAK PROTECTED",
+		"sym_label":   "FUNC GLOBAL DEFAULT",
+		"sym_object":  "OBJECT GLOBAL HIDDEN",
+	}
+	sizes := make(map[string]string)
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 8 {
+			continue
+		}
+		name := fields[7]
+		if x, found := want[name]; found {
+			if s := strings.Join(fields[3:6], " "); s != x {
+				t.Errorf("%s: %s", name, s)
+			}
+			sizes[name] = fields[2]
+			delete(want, name)
+		}
+	}
+	for name := range want {
+		t.Errorf("symbol %s not found:\n%s", name, out)
+	}
+
+	for name, size := range sizes {
+		if size == "0" {
+			t.Errorf("%s has zero size", name)
+		}
+	}
+	if s := sizes["sym_object"]; s != "12" {
+		t.Errorf("sym_object size: %s", s)
+	}
+}
+
+func TestSymbolsC(t *testing.T) {
+	a := ga.NewAssembly(ga.C, sys)
+	a.Function("sym_hidden", ga.SymbolOptions{Visibility: ga.HiddenVisibility})
+	a.Return()
+	a.Function("sym_weak", ga.SymbolOptions{Weak: true})
+	a.Return()
+
+	s := a.String()
+	for _, x := range []string{
+		"__attribute__((visibility(\"hidden\")))\nvoid sym_hidden(void)\n",
+		"__attribute__((weak))\nvoid sym_weak(void)\n",
+	} {
+		if !strings.Contains(s, x) {
+			t.Errorf("%q not found in output:\n%s", x, s)
+		}
+	}
+	buildC(t, s, "-c")
+}
+
+func TestSymbolOptionsUnsupported(t *testing.T) {
+	for _, x := range []struct {
+		arch  ga.Arch
+		opts  []ga.Option
+		name  string
+		sopts ga.SymbolOptions
+		want  string
+	}{
+		{ga.AMD64, nil, "f", ga.SymbolOptions{GoFrameSize: 8}, "Go frame and argument sizes are supported only with Go syntax"},
+		{ga.ARM64, nil, "f", ga.SymbolOptions{GoArgSize: 8}, "Go frame and argument sizes are supported only with Go syntax"},
+		{ga.C, nil, "f", ga.SymbolOptions{GoFrameSize: 8}, "Go frame and argument sizes are supported only with Go syntax"},
+		{ga.AMD64, []ga.Option{ga.WithSyntax(ga.GoSyntax)}, "f", ga.SymbolOptions{Weak: true}, "symbol visibility and weak binding are not supported with Go syntax"},
+		{ga.ARM64, []ga.Option{ga.WithSyntax(ga.GoSyntax)}, "f", ga.SymbolOptions{Visibility: ga.HiddenVisibility}, "symbol visibility and weak binding are not supported with Go syntax"},
+		{ga.RISCV64, nil, ".f", ga.SymbolOptions{Weak: true}, "local name .f cannot have symbol options"},
+	} {
+		func() {
+			defer func() {
+				if s := fmt.Sprint(recover()); s != x.want {
+					t.Errorf("%s %q: %s", x.arch.Machine(), x.name, s)
+				}
+			}()
+			ga.NewAssembly(x.arch, sys, x.opts...).Function(x.name, x.sopts)
+		}()
+	}
+}
@@ -5,35 +5,76 @@
 package ga
 
 type System struct {
-	StackPtr  Reg
-	SyscallNr Reg
-	SysParams []Reg
-	SysResult Reg
-	LibParams []Reg
-	LibResult Reg
+	StackPtr    Reg
+	FramePtr    Reg // Reserved by WithFramePointer.
+	SyscallNr   Reg
+	SysParams   []Reg
+	SysResult   Reg
+	SysClobbers RegSet // Destroyed by Syscall in addition to SysResult.
+	LibParams   []Reg
+	LibArgRegs  Specific // Number of LibParams used per architecture (0 means all).
+	LibResult   Reg
+	LibResult2  Reg    // Second half of a two-register result.
+	LibClobbers RegSet // Destroyed by Call (caller-saved registers).
+	CalleeSaved RegSet // Preserved by library functions.
+}
+
+// libArgRegs returns the number of arguments which are passed in registers.
+func (s *System) libArgRegs(id ArchID) int {
+	var n int
+	if id >= firstExternalID {
+		n = s.LibArgRegs.Other[id]
+	} else {
+		n = s.LibArgRegs.Value(id)
+	}
+	if n == 0 || n > len(s.LibParams) {
+		n = len(s.LibParams)
+	}
+	return n
 }
 
 func Linux() *System {
 	return &System{
-		StackPtr:  Reg{RSP, XSP, "stack"},
-		SyscallNr: Reg{RAX, X8, "syscall"},
+		StackPtr:  Reg{AMD64: RSP, ARM64: XSP, RISCV64: SP, PPC64LE: GPR1, Use: "stack"},
+		FramePtr:  Reg{AMD64: RBP, ARM64: X29, RISCV64: S0, PPC64LE: GPR31, Use: "frame"},
+		SyscallNr: Reg{AMD64: RAX, ARM64: X8, RISCV64: A7, PPC64LE: GPR0, Use: "syscall"},
 		SysParams: []Reg{
-			{RDI, X0, "sysparam0"},
-			{RSI, X1, "sysparam1"},
-			{RDX, X2, "sysparam2"},
-			{R10, X3, "sysparam3"},
-			{R8, X4, "sysparam4"},
-			{R9, X5, "sysparam5"},
+			{AMD64: RDI, ARM64: X0, RISCV64: A0, PPC64LE: GPR3, Use: "sysparam0"},
+			{AMD64: RSI, ARM64: X1, RISCV64: A1, PPC64LE: GPR4, Use: "sysparam1"},
+			{AMD64: RDX, ARM64: X2, RISCV64: A2, PPC64LE: GPR5, Use: "sysparam2"},
+			{AMD64: R10, ARM64: X3, RISCV64: A3, PPC64LE: GPR6, Use: "sysparam3"},
+			{AMD64: R8, ARM64: X4, RISCV64: A4, PPC64LE: GPR7, Use: "sysparam4"},
+			{AMD64: R9, ARM64: X5, RISCV64: A5, PPC64LE: GPR8, Use: "sysparam5"},
+		},
+		SysResult: Reg{AMD64: RAX, ARM64: X0, RISCV64: A0, PPC64LE: GPR3, Use: "sysresult"},
+		SysClobbers: RegSet{
+			AMD64:   1<<RCX | 1<<R11,
+			PPC64LE: 1<<GPR0 | (1<<(GPR12+1) - 1<<GPR4),
 		},
-		SysResult: Reg{RAX, X0, "sysresult"},
 		LibParams: []Reg{
-			{RDI, X0, "libparam0"},
-			{RSI, X1, "libparam1"},
-			{RDX, X2, "libparam2"},
-			{RCX, X3, "libparam3"},
-			{R8, X4, "libparam4"},
-			{R9, X5, "libparam5"},
+			{AMD64: RDI, ARM64: X0, RISCV64: A0, PPC64LE: GPR3, Use: "libparam0"},
+			{AMD64: RSI, ARM64: X1, RISCV64: A1, PPC64LE: GPR4, Use: "libparam1"},
+			{AMD64: RDX, ARM64: X2, RISCV64: A2, PPC64LE: GPR5, Use: "libparam2"},
+			{AMD64: RCX, ARM64: X3, RISCV64: A3, PPC64LE: GPR6, Use: "libparam3"},
+			{AMD64: R8, ARM64: X4, RISCV64: A4, PPC64LE: GPR7, Use: "libparam4"},
+			{AMD64: R9, ARM64: X5, RISCV64: A5, PPC64LE: GPR8, Use: "libparam5"},
+			{ARM64: X6, RISCV64: A6, PPC64LE: GPR9, Use: "libparam6"},
+			{ARM64: X7, RISCV64: A7, PPC64LE: GPR10, Use: "libparam7"},
+		},
+		LibArgRegs: Specific{AMD64: 6, ARM64: 8, RISCV64: 8, PPC64LE: 8},
+		LibResult:  Reg{AMD64: RAX, ARM64: X0, RISCV64: A0, PPC64LE: GPR3, Use: "libresult"},
+		LibResult2: Reg{AMD64: RDX, ARM64: X1, RISCV64: A1, PPC64LE: GPR4, Use: "libresult2"},
+		LibClobbers: RegSet{
+			AMD64:   1<<RAX | 1<<RCX | 1<<RDX | 1<<RSI | 1<<RDI | 1<<R8 | 1<<R9 | 1<<R10 | 1<<R11,
+			ARM64:   (1<<(X18+1) - 1) | 1<<XLR,
+			RISCV64: 1<<RA | 1<<T0 | 1<<T1 | 1<<T2 | (1<<(A7+1) - 1<<A0) | -1<<T3,
+			PPC64LE: 1<<GPR0 | (1<<(GPR12+1) - 1<<GPR3),
+		},
+		CalleeSaved: RegSet{
+			AMD64:   1<<RBX | 1<<RBP | 1<<R12 | 1<<R13 | 1<<R14 | 1<<R15,
+			ARM64:   1<<(X29+1) - 1<<X19,
+			RISCV64: 1<<S0 | 1<<S1 | (1<<(S11+1) - 1<<S2),
+			PPC64LE: -1 << GPR14,
 		},
-		LibResult: Reg{RAX, X0, "libresult"},
 	}
 }
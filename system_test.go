@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"testing"
+
+	"gate.computer/ga"
+	"gate.computer/ga/linux"
+)
+
+func TestLinuxRegSets(t *testing.T) {
+	for name, arch := range ga.Archs {
+		id := arch.ID()
+		bit := func(r ga.Reg) uint32 { return 1 << r.Num(id) }
+
+		clobbers := sys.LibClobbers.Mask(id)
+		saved := sys.CalleeSaved.Mask(id)
+
+		if clobbers&saved != 0 {
+			t.Errorf("%s: registers are both clobbered and callee-saved: %#x", name, clobbers&saved)
+		}
+		if (clobbers|saved)&bit(sys.StackPtr) != 0 {
+			t.Errorf("%s: stack pointer is in a register set", name)
+		}
+		if saved&bit(sys.FramePtr) == 0 {
+			t.Errorf("%s: frame pointer is not callee-saved", name)
+		}
+		for _, r := range append(sys.LibParams, sys.LibResult) {
+			if clobbers&bit(r) == 0 {
+				t.Errorf("%s: %s is not clobbered by call", name, r.Use)
+			}
+		}
+		for _, r := range []ga.Reg{r0, r1, r2, temp} {
+			if saved&bit(r) == 0 {
+				t.Errorf("%s: %s is not callee-saved", name, r.Use)
+			}
+		}
+		if sys.SysClobbers.Mask(id)&saved != 0 {
+			t.Errorf("%s: syscall clobbers callee-saved registers", name)
+		}
+	}
+}
+
+func TestCallClobbers(t *testing.T) {
+	for name, arch := range ga.Archs {
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			a.Function("f")
+			a.Set(r0)
+			a.Set(sys.LibParams[0])
+			a.Set(sys.LibParams[5])
+			a.Call("f")
+
+			usage := a.Usage()
+			if usage[r0.Num(arch.ID())] != r0.Use {
+				t.Error("callee-saved register was clobbered by call")
+			}
+			for _, r := range []ga.Reg{sys.LibParams[0], sys.LibParams[5]} {
+				if s := usage[r.Num(arch.ID())]; s != "" {
+					t.Errorf("%s is still in use after call", s)
+				}
+			}
+			if usage[sys.StackPtr.Num(arch.ID())] == "" {
+				t.Error("stack pointer was clobbered by call")
+			}
+		})
+	}
+}
+
+func TestSyscallClobbers(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+	rcx := ga.Reg{AMD64: ga.RCX, Use: "rcx"}
+	r11 := ga.Reg{AMD64: ga.R11, Use: "r11"}
+	a.Set(rcx)
+	a.Set(r11)
+	a.Set(sys.SysParams[0])
+	a.Syscall(linux.SYS_GETPID)
+
+	usage := a.Usage()
+	if usage[ga.RCX] != "" || usage[ga.R11] != "" {
+		t.Errorf("rcx or r11 is still in use after syscall: %q %q", usage[ga.RCX], usage[ga.R11])
+	}
+	if usage[ga.RDI] == "" {
+		t.Error("syscall parameter was clobbered")
+	}
+}
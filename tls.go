@@ -0,0 +1,23 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga
+
+// TLSModel determines how the offset of a thread-local variable from the
+// thread pointer is resolved.  Thread-local storage is supported only with
+// GNU assembler.
+type TLSModel uint8
+
+const (
+	// InitialExecTLS loads the offset from the global offset table.  The
+	// variable may be defined in the executable or in a shared library which
+	// is loaded at program startup.
+	InitialExecTLS TLSModel = iota
+
+	// LocalExecTLS resolves the offset at link time.  The variable must be
+	// defined in the executable.
+	LocalExecTLS
+)
+
+const tlsUnsupported = "thread-local storage is not supported with this syntax or architecture"
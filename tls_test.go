@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ga_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gate.computer/ga"
+)
+
+func threadLocal(a *ga.Assembly) {
+	a.Section(ga.TBSSSection)
+	a.DataSymbol("tls_zero", 8)
+	a.DataZero(16)
+	a.Section(ga.TDataSection)
+	a.DataSymbol("tls_data", 8)
+	a.DataWord8(1)
+
+	a.Section(ga.TextSection)
+	a.Function("tls")
+	a.LoadThreadPointer(r0)
+	for _, model := range []ga.TLSModel{ga.InitialExecTLS, ga.LocalExecTLS} {
+		a.ThreadLocalAddress(r1, "tls_zero", model, temp)
+		a.LoadThreadLocal(r2, "tls_data", 0, model, temp)
+		a.LoadThreadLocal(r2, "tls_zero", 8, model, temp)
+	}
+	a.Return()
+}
+
+func TestThreadLocal(t *testing.T) {
+	for name, arch := range ga.Archs {
+		if arch == ga.C {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			a := ga.NewAssembly(arch, sys)
+			threadLocal(a)
+			s := a.String()
+			for _, x := range []string{
+				".section\t.tbss\n",
+				".type\t\"tls_zero\",@tls_object\n",
+				".type\t\"tls_data\",@tls_object\n",
+			} {
+				if !strings.Contains(s, x) {
+					t.Errorf("%q not found in output:\n%s", x, s)
+				}
+			}
+			assembleCheck(t, arch, s)
+		})
+	}
+}
+
+func TestThreadLocalAMD64(t *testing.T) {
+	a := ga.NewAssembly(ga.AMD64, sys)
+	threadLocal(a)
+	s := a.String()
+	for _, x := range []string{
+		"\tmov\trbx, fs:0\n",
+		"\tadd\tr12, [rip + \"tls_zero\"@GOTTPOFF]\n",
+		"\tmov\tr13, fs:\"tls_zero\"@tpoff+8\n",
+	} {
+		if !strings.Contains(s, x) {
+			t.Errorf("%q not found in output:\n%s", x, s)
+		}
+	}
+}
+
+func TestThreadLocalUnsupported(t *testing.T) {
+	for _, a := range []*ga.Assembly{
+		ga.NewAssembly(ga.AMD64, sys, ga.WithSyntax(ga.GoSyntax)),
+		ga.NewAssembly(ga.ARM64, sys, ga.WithSyntax(ga.GoSyntax)),
+		ga.NewAssembly(ga.C, sys),
+	} {
+		func() {
+			defer func() {
+				if s := fmt.Sprint(recover()); s != "thread-local storage is not supported with this syntax or architecture" {
+					t.Error(s)
+				}
+			}()
+			a.LoadThreadPointer(r0)
+		}()
+	}
+}